@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"bwanews/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var exportFormat string
+var exportOutput string
+
+var ExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export categories and contents",
+	Long:  `Streams every category and content, with tags, author emails, slugs and timestamps, as JSON Lines or CSV.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		app.RunExport(exportFormat, exportOutput)
+	},
+}
+
+func init() {
+	ExportCmd.Flags().StringVarP(&exportFormat, "format", "f", "jsonl", "output format: jsonl or csv")
+	ExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "output file (default is stdout)")
+	rootCmd.AddCommand(ExportCmd)
+}
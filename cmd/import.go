@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bwanews/internal/app"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/lib/transfer"
+
+	"github.com/spf13/cobra"
+)
+
+var importFormat string
+var importDryRun bool
+var importDefaultAuthor string
+
+var ImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import categories and contents",
+	Long:  `Upserts categories and contents by slug from a JSON Lines or CSV export and prints a report of created, updated and failed records.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := importFormat
+		if format == "" {
+			format = transfer.FormatFromFilename(args[0])
+		}
+
+		app.RunImport(args[0], entity.ImportOptions{
+			Format:             format,
+			DryRun:             importDryRun,
+			DefaultAuthorEmail: importDefaultAuthor,
+		})
+	},
+}
+
+func init() {
+	ImportCmd.Flags().StringVarP(&importFormat, "format", "f", "", "input format: jsonl or csv (default from the file extension)")
+	ImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "report what would change without writing")
+	ImportCmd.Flags().StringVar(&importDefaultAuthor, "default-author", "", "email of the author for records whose author is missing or unknown")
+	rootCmd.AddCommand(ImportCmd)
+}
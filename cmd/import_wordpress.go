@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bwanews/internal/app"
+	"bwanews/internal/core/domain/entity"
+
+	"github.com/spf13/cobra"
+)
+
+var wordPressUploads string
+var wordPressRedirects string
+var wordPressPasswords string
+var wordPressDryRun bool
+var wordPressDefaultAuthor string
+
+var ImportWordPressCmd = &cobra.Command{
+	Use:   "import-wordpress <wxr-file>",
+	Short: "Import a WordPress WXR export",
+	Long: `Imports categories, tags, posts and authors from a WordPress export file. Featured images are
+re-uploaded to R2 from --uploads (a copy of wp-content/uploads) or downloaded from the old site,
+and a CSV redirect map from the old permalinks can be written with --redirects. The temporary
+passwords of created accounts are written, readable by the owner only, to --passwords.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app.RunImportWordPress(args[0], wordPressRedirects, wordPressPasswords, entity.WordPressImportOptions{
+			DryRun:             wordPressDryRun,
+			UploadsDir:         wordPressUploads,
+			DefaultAuthorEmail: wordPressDefaultAuthor,
+		})
+	},
+}
+
+func init() {
+	ImportWordPressCmd.Flags().StringVar(&wordPressUploads, "uploads", "", "local copy of wp-content/uploads")
+	ImportWordPressCmd.Flags().StringVar(&wordPressRedirects, "redirects", "", "write the redirect map as CSV to this file")
+	ImportWordPressCmd.Flags().StringVar(&wordPressPasswords, "passwords", "wordpress-passwords.csv", "write the temporary passwords of created accounts as CSV to this file")
+	ImportWordPressCmd.Flags().BoolVar(&wordPressDryRun, "dry-run", false, "report what would change without writing or uploading")
+	ImportWordPressCmd.Flags().StringVar(&wordPressDefaultAuthor, "default-author", "", "email of the author for categories and for posts whose author has no email")
+	ImportWordPressCmd.MarkFlagRequired("default-author")
+	rootCmd.AddCommand(ImportWordPressCmd)
+}
@@ -8,6 +8,44 @@ type App struct {
 
 	JwtSecretKey string `json:"jwt_secret_key"`
 	JwtIssuer    string `json:"jwt_issuer"`
+
+	RequestTimeout int `json:"request_timeout"`
+	ContentLockTTL int `json:"content_lock_ttl"`
+
+	ProxyHeader    string `json:"proxy_header"`
+	TrustedProxies string `json:"trusted_proxies"`
+
+	ContentPath   string `json:"content_path"`
+	CategoryPath  string `json:"category_path"`
+	TagPath       string `json:"tag_path"`
+	PublicBaseURL string `json:"public_base_url"`
+	SiteName      string `json:"site_name"`
+	SiteLanguage  string `json:"site_language"`
+	SiteLogo      string `json:"site_logo"`
+
+	SitemapCacheTTL int `json:"sitemap_cache_ttl"`
+
+	EmbedCacheTTL      int    `json:"embed_cache_ttl"`
+	EmbedFetchTimeout  int    `json:"embed_fetch_timeout"`
+	EmbedFacebookToken string `json:"embed_facebook_token"`
+
+	CommentAutoApprove bool   `json:"comment_auto_approve"`
+	CommentMaxLinks    int    `json:"comment_max_links"`
+	CommentBannedWords string `json:"comment_banned_words"`
+	CommentRateLimit   int    `json:"comment_rate_limit"`
+	CommentRateWindow  int    `json:"comment_rate_window"`
+
+	ReaderVerifyURL string `json:"reader_verify_url"`
+	ReaderVerifyTTL int    `json:"reader_verify_ttl"`
+
+	ViewFlushInterval int `json:"view_flush_interval"`
+	ViewDedupeWindow  int `json:"view_dedupe_window"`
+
+	LivePollInterval int `json:"live_poll_interval"`
+
+	WebhookPollInterval int `json:"webhook_poll_interval"`
+	WebhookTimeout      int `json:"webhook_timeout"`
+	WebhookMaxAttempts  int `json:"webhook_max_attempts"`
 }
 
 type PsqlDB struct {
@@ -18,6 +56,8 @@ type PsqlDB struct {
 	DBName    string `json:"db_name"`
 	DBMaxOpen int    `json:"db_max_open"`
 	DBMaxIdle int    `json:"db_max_idle"`
+
+	StatementTimeout int `json:"statement_timeout"`
 }
 
 type CloudflareR2 struct {
@@ -30,10 +70,19 @@ type CloudflareR2 struct {
 	ExpiresTime int    `json:"expires_time"`
 }
 
+type Mail struct {
+	SMTPHost     string `json:"smtp_host"`
+	SMTPPort     int    `json:"smtp_port"`
+	SMTPUser     string `json:"smtp_user"`
+	SMTPPassword string `json:"smtp_password"`
+	From         string `json:"from"`
+}
+
 type Config struct {
 	App  App
 	Psql PsqlDB
 	R2   CloudflareR2
+	Mail Mail
 }
 
 func NewConfig() *Config {
@@ -43,6 +92,44 @@ func NewConfig() *Config {
 			AppEnv:       viper.GetString("APP_ENV"),
 			JwtSecretKey: viper.GetString("JWT_SECRET_KEY"),
 			JwtIssuer:    viper.GetString("JWT_ISSUER"),
+
+			RequestTimeout: viper.GetInt("APP_REQUEST_TIMEOUT"),
+			ContentLockTTL: viper.GetInt("APP_CONTENT_LOCK_TTL"),
+
+			ProxyHeader:    viper.GetString("APP_PROXY_HEADER"),
+			TrustedProxies: viper.GetString("APP_TRUSTED_PROXIES"),
+
+			ContentPath:   viper.GetString("APP_CONTENT_PATH"),
+			CategoryPath:  viper.GetString("APP_CATEGORY_PATH"),
+			TagPath:       viper.GetString("APP_TAG_PATH"),
+			PublicBaseURL: viper.GetString("APP_PUBLIC_BASE_URL"),
+			SiteName:      viper.GetString("APP_SITE_NAME"),
+			SiteLanguage:  viper.GetString("APP_SITE_LANGUAGE"),
+			SiteLogo:      viper.GetString("APP_SITE_LOGO"),
+
+			SitemapCacheTTL: viper.GetInt("APP_SITEMAP_CACHE_TTL"),
+
+			EmbedCacheTTL:      viper.GetInt("APP_EMBED_CACHE_TTL"),
+			EmbedFetchTimeout:  viper.GetInt("APP_EMBED_FETCH_TIMEOUT"),
+			EmbedFacebookToken: viper.GetString("APP_EMBED_FACEBOOK_TOKEN"),
+
+			CommentAutoApprove: viper.GetBool("APP_COMMENT_AUTO_APPROVE"),
+			CommentMaxLinks:    viper.GetInt("APP_COMMENT_MAX_LINKS"),
+			CommentBannedWords: viper.GetString("APP_COMMENT_BANNED_WORDS"),
+			CommentRateLimit:   viper.GetInt("APP_COMMENT_RATE_LIMIT"),
+			CommentRateWindow:  viper.GetInt("APP_COMMENT_RATE_WINDOW"),
+
+			ReaderVerifyURL: viper.GetString("APP_READER_VERIFY_URL"),
+			ReaderVerifyTTL: viper.GetInt("APP_READER_VERIFY_TTL"),
+
+			ViewFlushInterval: viper.GetInt("APP_VIEW_FLUSH_INTERVAL"),
+			ViewDedupeWindow:  viper.GetInt("APP_VIEW_DEDUPE_WINDOW"),
+
+			LivePollInterval: viper.GetInt("APP_LIVE_POLL_INTERVAL"),
+
+			WebhookPollInterval: viper.GetInt("APP_WEBHOOK_POLL_INTERVAL"),
+			WebhookTimeout:      viper.GetInt("APP_WEBHOOK_TIMEOUT"),
+			WebhookMaxAttempts:  viper.GetInt("APP_WEBHOOK_MAX_ATTEMPTS"),
 		},
 		Psql: PsqlDB{
 			Host:      viper.GetString("DATABASE_HOST"),
@@ -52,6 +139,8 @@ func NewConfig() *Config {
 			DBName:    viper.GetString("DATABASE_NAME"),
 			DBMaxOpen: viper.GetInt("DATABASE_MAX_OPEN_CONNECTIONS"),
 			DBMaxIdle: viper.GetInt("DATABASE_MAX_IDLE_CONNECTIONS"),
+
+			StatementTimeout: viper.GetInt("DATABASE_STATEMENT_TIMEOUT"),
 		},
 		R2: CloudflareR2{
 			Name:        viper.GetString("CLOUDFLARE_R2_BUCKET_NAME"),
@@ -62,5 +151,12 @@ func NewConfig() *Config {
 			PublicURL:   viper.GetString("CLOUDFLARE_R2_PUBLIC_URL"),
 			ExpiresTime: viper.GetInt("CLOUDFLARE_R2_EXPIRES_URL"),
 		},
+		Mail: Mail{
+			SMTPHost:     viper.GetString("MAIL_SMTP_HOST"),
+			SMTPPort:     viper.GetInt("MAIL_SMTP_PORT"),
+			SMTPUser:     viper.GetString("MAIL_SMTP_USER"),
+			SMTPPassword: viper.GetString("MAIL_SMTP_PASSWORD"),
+			From:         viper.GetString("MAIL_FROM"),
+		},
 	}
 }
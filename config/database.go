@@ -21,6 +21,12 @@ func (cfg Config) ConnectionPostgres() (*Postgres, error) {
 		cfg.Psql.Port,
 		cfg.Psql.DBName,
 	)
+
+	// statement_timeout is passed as a runtime parameter so Postgres aborts
+	// runaway queries even when the caller's context has no deadline.
+	if cfg.Psql.StatementTimeout > 0 {
+		dbConnString = fmt.Sprintf("%s?statement_timeout=%d", dbConnString, cfg.Psql.StatementTimeout)
+	}
 	db, err := gorm.Open(postgres.Open(dbConnString), &gorm.Config{})
 	if err != nil {
 		log.Error().Err(err).Msg("[ConnectionPostgres-1] Failed to connect to database" + cfg.Psql.Host)
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_contents_created_at_id;
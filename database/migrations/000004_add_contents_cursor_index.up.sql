@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_contents_created_at_id ON contents(created_at DESC, id DESC);
@@ -0,0 +1,3 @@
+-- existing accounts keep full access; new accounts default to journalist
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'admin';
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'journalist';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_contents_slug;
+ALTER TABLE contents DROP COLUMN IF EXISTS slug;
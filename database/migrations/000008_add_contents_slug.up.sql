@@ -0,0 +1,5 @@
+-- existing rows get a slug derived from the title, suffixed with the id to stay unique
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS slug VARCHAR(250);
+UPDATE contents SET slug = regexp_replace(lower(title), '\s+', '-', 'g') || '-' || id WHERE slug IS NULL;
+ALTER TABLE contents ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contents_slug ON contents(slug);
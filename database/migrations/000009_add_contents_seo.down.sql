@@ -0,0 +1,5 @@
+ALTER TABLE contents DROP COLUMN IF EXISTS noindex;
+ALTER TABLE contents DROP COLUMN IF EXISTS social_image;
+ALTER TABLE contents DROP COLUMN IF EXISTS canonical_url;
+ALTER TABLE contents DROP COLUMN IF EXISTS meta_description;
+ALTER TABLE contents DROP COLUMN IF EXISTS meta_title;
@@ -0,0 +1,6 @@
+-- empty values fall back to the title, excerpt, public URL and image when rendered
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS meta_title VARCHAR(200) NOT NULL DEFAULT '';
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS meta_description TEXT NOT NULL DEFAULT '';
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS canonical_url VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS social_image VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS noindex BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+-- body_html is the sanitized rendering of description; existing rows are
+-- rendered on read until they are next saved
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS body_format VARCHAR(20) NOT NULL DEFAULT 'html';
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS body_html TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE contents DROP COLUMN IF EXISTS blocks;
@@ -0,0 +1,2 @@
+-- optional structured body; description keeps its HTML rendering
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS blocks JSONB;
@@ -0,0 +1,19 @@
+-- oEmbed responses of third-party URLs found in article bodies
+CREATE TABLE IF NOT EXISTS embeds (
+    url TEXT PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    author_name TEXT NOT NULL DEFAULT '',
+    author_url TEXT NOT NULL DEFAULT '',
+    provider_name TEXT NOT NULL DEFAULT '',
+    provider_url TEXT NOT NULL DEFAULT '',
+    html TEXT NOT NULL DEFAULT '',
+    width INT NOT NULL DEFAULT 0,
+    height INT NOT NULL DEFAULT 0,
+    thumbnail_url TEXT NOT NULL DEFAULT '',
+    thumbnail_width INT NOT NULL DEFAULT 0,
+    thumbnail_height INT NOT NULL DEFAULT 0,
+    fetched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
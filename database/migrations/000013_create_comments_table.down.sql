@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS comments;
+
+ALTER TABLE contents DROP COLUMN IF EXISTS comments_closed;
+ALTER TABLE contents DROP COLUMN IF EXISTS comments_enabled;
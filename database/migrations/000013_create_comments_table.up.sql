@@ -0,0 +1,26 @@
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS comments_enabled BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS comments_closed BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- root_id is the top-level comment of a thread, NULL for top-level comments;
+-- reader_id is set for comments written by a signed-in reader
+CREATE TABLE IF NOT EXISTS "comments" (
+    id SERIAL PRIMARY KEY,
+    content_id INT NOT NULL REFERENCES contents(id) ON DELETE CASCADE,
+    parent_id INT REFERENCES comments(id) ON DELETE CASCADE,
+    root_id INT REFERENCES comments(id) ON DELETE CASCADE,
+    reader_id INT,
+    author_name VARCHAR(100) NOT NULL,
+    author_email VARCHAR(200) NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    moderated_by_id INT REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_content_status ON comments(content_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_root_id ON comments(root_id);
+CREATE INDEX IF NOT EXISTS idx_comments_status_created_at ON comments(status, created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_comments_reader_id_created_at;
+DROP INDEX IF EXISTS idx_comments_ip_address_created_at;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS spam_reasons;
+ALTER TABLE comments DROP COLUMN IF EXISTS spam_score;
@@ -0,0 +1,6 @@
+-- spam_reasons holds one reason per line, as reported by the spam filters
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS spam_score INT NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS spam_reasons TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_comments_ip_address_created_at ON comments(ip_address, created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_reader_id_created_at ON comments(reader_id, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS fk_comments_reader_id;
+
+DROP TABLE IF EXISTS readers;
@@ -0,0 +1,18 @@
+-- readers are front-end accounts, kept apart from newsroom staff in users;
+-- verification_token holds the SHA-256 hash of the emailed token
+CREATE TABLE IF NOT EXISTS "readers" (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(200) UNIQUE NOT NULL,
+    password VARCHAR(100) NOT NULL,
+    email_verified_at TIMESTAMP,
+    verification_token VARCHAR(64),
+    verification_expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_readers_verification_token ON readers(verification_token);
+
+UPDATE comments SET reader_id = NULL WHERE reader_id IS NOT NULL AND reader_id NOT IN (SELECT id FROM readers);
+ALTER TABLE comments ADD CONSTRAINT fk_comments_reader_id FOREIGN KEY (reader_id) REFERENCES readers(id) ON DELETE SET NULL;
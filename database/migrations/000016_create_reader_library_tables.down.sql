@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reading_history;
+DROP TABLE IF EXISTS bookmarks;
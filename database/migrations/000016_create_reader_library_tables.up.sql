@@ -0,0 +1,22 @@
+-- a reader's saved articles and how far they got in the ones they opened;
+-- rows go away with their reader or content
+CREATE TABLE IF NOT EXISTS "bookmarks" (
+    reader_id INT NOT NULL REFERENCES readers(id) ON DELETE CASCADE,
+    content_id INT NOT NULL REFERENCES contents(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (reader_id, content_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_reader_id_created_at ON bookmarks(reader_id, created_at);
+
+-- progress is the percentage of the article read, 0 to 100
+CREATE TABLE IF NOT EXISTS "reading_history" (
+    reader_id INT NOT NULL REFERENCES readers(id) ON DELETE CASCADE,
+    content_id INT NOT NULL REFERENCES contents(id) ON DELETE CASCADE,
+    progress SMALLINT NOT NULL DEFAULT 0 CHECK (progress BETWEEN 0 AND 100),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (reader_id, content_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reading_history_reader_id_updated_at ON reading_history(reader_id, updated_at);
@@ -0,0 +1,10 @@
+-- views per content in five-minute buckets, written in batches by the view
+-- counter; buckets older than the longest trending window are pruned
+CREATE TABLE IF NOT EXISTS "content_stats" (
+    content_id INT NOT NULL REFERENCES contents(id) ON DELETE CASCADE,
+    bucket_start TIMESTAMP NOT NULL,
+    views INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (content_id, bucket_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_stats_bucket_start ON content_stats(bucket_start);
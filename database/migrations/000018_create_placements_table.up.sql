@@ -0,0 +1,17 @@
+-- contents editors put in named front-end slots such as hero, breaking or
+-- pinned:<category-slug>; a placement is shown from starts_at until
+-- expires_at, either of which may be left open
+CREATE TABLE IF NOT EXISTS "placements" (
+    id SERIAL PRIMARY KEY,
+    slot VARCHAR(100) NOT NULL,
+    content_id INT NOT NULL REFERENCES contents(id) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    created_by_id INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    CONSTRAINT uq_placements_slot_content_id UNIQUE (slot, content_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_placements_slot_position ON placements(slot, position);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS live_entries;
+DROP SEQUENCE IF EXISTS live_entries_seq;
+
+ALTER TABLE contents DROP COLUMN IF EXISTS content_type;
@@ -0,0 +1,22 @@
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS content_type VARCHAR(20) NOT NULL DEFAULT 'article';
+
+-- entries of a live blog; seq is bumped from live_entries_seq on every
+-- change, so readers resume an event stream from the last seq they saw.
+-- Deleted entries are kept with deleted_at so a resume can report them.
+CREATE SEQUENCE IF NOT EXISTS live_entries_seq;
+
+CREATE TABLE IF NOT EXISTS "live_entries" (
+    id SERIAL PRIMARY KEY,
+    content_id INT NOT NULL REFERENCES contents(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    seq BIGINT NOT NULL DEFAULT nextval('live_entries_seq'),
+    created_by_id INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_live_entries_content_id_seq ON live_entries(content_id, seq);
+CREATE INDEX IF NOT EXISTS idx_live_entries_content_id_created_at ON live_entries(content_id, created_at);
@@ -0,0 +1 @@
+ALTER TABLE contents DROP CONSTRAINT IF EXISTS contents_version_positive;
@@ -0,0 +1,2 @@
+UPDATE contents SET version = 1 WHERE version IS NULL OR version < 1;
+ALTER TABLE contents ADD CONSTRAINT contents_version_positive CHECK (version >= 1);
@@ -1,6 +1,7 @@
 package seeds
 
 import (
+	"bwanews/internal/core/domain/entity"
 	"bwanews/internal/core/domain/model"
 	"bwanews/lib/conv"
 
@@ -18,6 +19,7 @@ func SeedRoles(db *gorm.DB) {
 		Name:     "Admin",
 		Email:    "admin@mail.com",
 		Password: string(bytes),
+		Role:     entity.RoleAdmin,
 	}
 
 	if err := db.FirstOrCreate(&admin, model.User{Email: admin.Email}).Error; err != nil {
@@ -17,8 +17,8 @@ var code string
 var err error
 
 type CloudflareR2Adapter interface {
-	UploadImage(req *entity.FileUploadEntity) (string, error)
-	GeneratePresignedURL(req *entity.FileUploadEntity) (string, error)
+	UploadImage(ctx context.Context, req *entity.FileUploadEntity) (string, error)
+	GeneratePresignedURL(ctx context.Context, req *entity.FileUploadEntity) (string, error)
 }
 
 type cloudflareR2Adapter struct {
@@ -29,9 +29,9 @@ type cloudflareR2Adapter struct {
 }
 
 // GeneratePresignedURL implements CloudflareR2Adapter.
-func (c *cloudflareR2Adapter) GeneratePresignedURL(req *entity.FileUploadEntity) (string, error) {
+func (c *cloudflareR2Adapter) GeneratePresignedURL(ctx context.Context, req *entity.FileUploadEntity) (string, error) {
 	presignClient := s3.NewPresignClient(c.Client)
-	presignResult, err := presignClient.PresignPutObject(context.TODO(), &s3.PutObjectInput{
+	presignResult, err := presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
 		Bucket: aws.String(c.Bucket),
 		Key:    aws.String(req.Name),
 	}, s3.WithPresignExpires(time.Duration(c.ExpiresAt*int(time.Hour))))
@@ -46,7 +46,7 @@ func (c *cloudflareR2Adapter) GeneratePresignedURL(req *entity.FileUploadEntity)
 }
 
 // UploadImage implements CloudflareR2Adapter.
-func (c *cloudflareR2Adapter) UploadImage(req *entity.FileUploadEntity) (string, error) {
+func (c *cloudflareR2Adapter) UploadImage(ctx context.Context, req *entity.FileUploadEntity) (string, error) {
 	openedFile, err := os.Open(req.Path)
 	if err != nil {
 		code = "[CLOUDFLARE R2] UploadImage = 1"
@@ -56,7 +56,7 @@ func (c *cloudflareR2Adapter) UploadImage(req *entity.FileUploadEntity) (string,
 
 	defer openedFile.Close()
 
-	_, err = c.Client.PutObject(context.TODO(), &s3.PutObjectInput{
+	_, err = c.Client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(c.Bucket),
 		Key:         aws.String(req.Name),
 		Body:        openedFile,
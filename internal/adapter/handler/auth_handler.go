@@ -50,7 +50,7 @@ func (a *authHandler) Login(c *fiber.Ctx) error {
 		Password: req.Password,
 	}
 
-	result, err := a.authService.GetUserByEmail(c.Context(), reqLogin)
+	result, err := a.authService.GetUserByEmail(c.UserContext(), reqLogin)
 	if err != nil {
 		code = "[HANDLER] Login = 3"
 		log.Errorw(code, err)
@@ -6,7 +6,9 @@ import (
 	"bwanews/internal/core/domain/entity"
 	"bwanews/internal/core/service"
 	"bwanews/lib/conv"
+	"bwanews/lib/pagination"
 	validatorLib "bwanews/lib/validator"
+	"encoding/json"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
@@ -19,6 +21,7 @@ type CategoryHandler interface {
 	GetCategoryByID(c *fiber.Ctx) error
 	CreateCategory(c *fiber.Ctx) error
 	EditCategoryByID(c *fiber.Ctx) error
+	PatchCategoryByID(c *fiber.Ctx) error
 	DeleteCategory(c *fiber.Ctx) error
 
 	GetCategoryFE(c *fiber.Ctx) error
@@ -26,11 +29,12 @@ type CategoryHandler interface {
 
 type categoryHandler struct {
 	categoryService service.CategoryService
+	pagination      pagination.PaginationInterface
 }
 
 // GetCategoryFE implements CategoryHandler.
 func (ch *categoryHandler) GetCategoryFE(c *fiber.Ctx) error {
-	results, err := ch.categoryService.GetCategories(c.Context())
+	results, _, err := ch.categoryService.GetCategories(c.UserContext(), entity.QueryString{})
 	if err != nil {
 		code = "[HANDLER] GetCategoryFE = 1"
 		log.Errorw(code, err)
@@ -53,6 +57,8 @@ func (ch *categoryHandler) GetCategoryFE(c *fiber.Ctx) error {
 	defaultSuccessResponse.Meta.Status = true
 	defaultSuccessResponse.Meta.Message = "Successfully retrieved categories"
 	defaultSuccessResponse.Data = categoryResponses
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
 
 	return c.JSON(defaultSuccessResponse)
 }
@@ -96,7 +102,7 @@ func (ch *categoryHandler) CreateCategory(c *fiber.Ctx) error {
 		},
 	}
 
-	err = ch.categoryService.CreateCategory(c.Context(), reqEntity)
+	err = ch.categoryService.CreateCategory(c.UserContext(), reqEntity)
 	if err != nil {
 		code = "[HANDLER] CreateCategory = 4"
 		log.Errorw(code, err)
@@ -138,7 +144,7 @@ func (ch *categoryHandler) DeleteCategory(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	err = ch.categoryService.DeleteCategory(c.Context(), id)
+	err = ch.categoryService.DeleteCategory(c.UserContext(), id)
 	if err != nil {
 		code = "[HANDLER] DeleteCategory = 3"
 		log.Errorw(code, err)
@@ -207,7 +213,7 @@ func (ch *categoryHandler) EditCategoryByID(c *fiber.Ctx) error {
 		},
 	}
 
-	err = ch.categoryService.EditCategoryByID(c.Context(), reqEntity)
+	err = ch.categoryService.EditCategoryByID(c.UserContext(), reqEntity)
 	if err != nil {
 		code = "[HANDLER] EditCategoryByID = 5"
 		log.Errorw(code, err)
@@ -225,6 +231,79 @@ func (ch *categoryHandler) EditCategoryByID(c *fiber.Ctx) error {
 	return c.JSON(defaultSuccessResponse)
 }
 
+// PatchCategoryByID implements CategoryHandler.
+func (ch *categoryHandler) PatchCategoryByID(c *fiber.Ctx) error {
+	var req request.CategoryPatchRequest
+	claims := c.Locals("user").(*entity.JwtData)
+	userId := claims.UserID
+	if userId == 0 {
+		code = "[HANDLER] PatchCategoryByID = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	if err = json.Unmarshal(c.Body(), &req); err != nil {
+		code = "[HANDLER] PatchCategoryByID = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = req.Validate(); err != nil {
+		code = "[HANDLER] PatchCategoryByID = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("categoryID"))
+	if err != nil {
+		code = "[HANDLER] PatchCategoryByID = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	// title is the only patchable member, so an empty patch just checks
+	// that the category exists.
+	if req.Title.Set {
+		err = ch.categoryService.EditCategoryByID(c.UserContext(), entity.CategoryEntity{
+			ID:    id,
+			Title: req.Title.Value,
+			User: entity.UserEntity{
+				ID: int64(userId),
+			},
+		})
+	} else {
+		_, err = ch.categoryService.GetCategoryByID(c.UserContext(), id)
+	}
+
+	if err != nil {
+		code = "[HANDLER] PatchCategoryByID = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Category edited successfully"
+
+	return c.JSON(defaultSuccessResponse)
+}
+
 // GetCategories implements CategoryHandler.
 func (ch *categoryHandler) GetCategories(c *fiber.Ctx) error {
 	claims := c.Locals("user").(*entity.JwtData)
@@ -238,16 +317,42 @@ func (ch *categoryHandler) GetCategories(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
 
-	results, err := ch.categoryService.GetCategories(c.Context())
+	page, limit, err := ch.pagination.ParsePage(c.Query("page"), c.Query("limit"), pagination.DefaultLimit)
 	if err != nil {
 		code = "[HANDLER] GetCategories = 2"
 		log.Errorw(code, err)
 		errorResp.Status = false
 		errorResp.Message = err.Error()
 
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	reqEntity := entity.QueryString{
+		Limit:  limit,
+		Page:   page,
+		Search: c.Query("search"),
+	}
+
+	results, totalData, err := ch.categoryService.GetCategories(c.UserContext(), reqEntity)
+	if err != nil {
+		code = "[HANDLER] GetCategories = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
 	}
 
+	paginationResp, err := paginationResponse(c, ch.pagination, int(totalData), page, limit)
+	if err != nil {
+		code = "[HANDLER] GetCategories = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
 	categoryResponses := []response.SuccessCategoryResponse{}
 	for _, category := range results {
 		categoryResponses = append(categoryResponses, response.SuccessCategoryResponse{
@@ -261,6 +366,8 @@ func (ch *categoryHandler) GetCategories(c *fiber.Ctx) error {
 	defaultSuccessResponse.Meta.Status = true
 	defaultSuccessResponse.Meta.Message = "Successfully retrieved categories"
 	defaultSuccessResponse.Data = categoryResponses
+	defaultSuccessResponse.Pagination = paginationResp
+	defaultSuccessResponse.Cursor = nil
 
 	return c.JSON(defaultSuccessResponse)
 }
@@ -289,7 +396,7 @@ func (ch *categoryHandler) GetCategoryByID(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	result, err := ch.categoryService.GetCategoryByID(c.Context(), id)
+	result, err := ch.categoryService.GetCategoryByID(c.UserContext(), id)
 	if err != nil {
 		code = "[HANDLER] GetCategoryByID = 3"
 		log.Errorw(code, err)
@@ -314,8 +421,9 @@ func (ch *categoryHandler) GetCategoryByID(c *fiber.Ctx) error {
 	return c.JSON(defaultSuccessResponse)
 }
 
-func NewCategoryHandler(categoryService service.CategoryService) CategoryHandler {
+func NewCategoryHandler(categoryService service.CategoryService, pagination pagination.PaginationInterface) CategoryHandler {
 	return &categoryHandler{
 		categoryService: categoryService,
+		pagination:      pagination,
 	}
 }
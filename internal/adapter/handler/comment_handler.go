@@ -0,0 +1,535 @@
+package handler
+
+import (
+	"bwanews/internal/adapter/handler/request"
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/conv"
+	"bwanews/lib/pagination"
+	validatorLib "bwanews/lib/validator"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type CommentHandler interface {
+	GetComments(c *fiber.Ctx) error
+	GetCommentByID(c *fiber.Ctx) error
+	ApproveComment(c *fiber.Ctx) error
+	RejectComment(c *fiber.Ctx) error
+	SpamComment(c *fiber.Ctx) error
+	DeleteComment(c *fiber.Ctx) error
+	UpdateCommentSettings(c *fiber.Ctx) error
+
+	GetContentComments(c *fiber.Ctx) error
+	CreateComment(c *fiber.Ctx) error
+}
+
+type commentHandler struct {
+	commentService service.CommentService
+	pagination     pagination.PaginationInterface
+}
+
+// GetComments implements CommentHandler.
+// Without ?status it lists the pending moderation queue; status=all lists
+// every comment.
+func (ch *commentHandler) GetComments(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetComments = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	page, limit, err := ch.pagination.ParsePage(c.Query("page"), c.Query("limit"), 20)
+	if err != nil {
+		code := "[HANDLER] GetComments = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	status := strings.ToUpper(c.Query("status", entity.CommentStatusPending))
+	switch status {
+	case "ALL":
+		status = ""
+	case entity.CommentStatusPending, entity.CommentStatusApproved, entity.CommentStatusRejected, entity.CommentStatusSpam:
+	default:
+		code := "[HANDLER] GetComments = 3"
+		log.Errorw(code, "invalid status "+status)
+		errorResp.Status = false
+		errorResp.Message = "status must be one of: pending, approved, rejected, spam, all"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var contentID int64
+	if c.Query("contentID") != "" {
+		contentID, err = conv.StringToInt64(c.Query("contentID"))
+		if err != nil {
+			code := "[HANDLER] GetComments = 4"
+			log.Errorw(code, err)
+			errorResp.Status = false
+			errorResp.Message = "Invalid contentID number"
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	results, totalData, err := ch.commentService.GetComments(c.UserContext(), entity.CommentQuery{
+		ContentID: contentID,
+		Status:    status,
+		Page:      page,
+		Limit:     limit,
+	})
+	if err != nil {
+		code := "[HANDLER] GetComments = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, ch.pagination, int(totalData), page, limit)
+	if err != nil {
+		code := "[HANDLER] GetComments = 6"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	respComments := []response.CommentResponse{}
+	for _, comment := range results {
+		respComments = append(respComments, toAdminCommentResponse(comment))
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = respComments
+	defaultSuccessResponse.Pagination = paginationResp
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// GetCommentByID implements CommentHandler.
+func (ch *commentHandler) GetCommentByID(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetCommentByID = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("commentID"))
+	if err != nil {
+		code := "[HANDLER] GetCommentByID = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := ch.commentService.GetCommentByID(c.UserContext(), id)
+	if err != nil {
+		code := "[HANDLER] GetCommentByID = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(commentErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = toAdminCommentResponse(*result)
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// ApproveComment implements CommentHandler.
+func (ch *commentHandler) ApproveComment(c *fiber.Ctx) error {
+	return ch.moderate(c, entity.CommentStatusApproved, "Comment approved")
+}
+
+// RejectComment implements CommentHandler.
+func (ch *commentHandler) RejectComment(c *fiber.Ctx) error {
+	return ch.moderate(c, entity.CommentStatusRejected, "Comment rejected")
+}
+
+// SpamComment implements CommentHandler.
+func (ch *commentHandler) SpamComment(c *fiber.Ctx) error {
+	return ch.moderate(c, entity.CommentStatusSpam, "Comment marked as spam")
+}
+
+func (ch *commentHandler) moderate(c *fiber.Ctx, status, message string) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] moderate = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("commentID"))
+	if err != nil {
+		code := "[HANDLER] moderate = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = ch.commentService.ModerateComment(c.UserContext(), id, status, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] moderate = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(commentErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = message
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// DeleteComment implements CommentHandler.
+func (ch *commentHandler) DeleteComment(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] DeleteComment = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("commentID"))
+	if err != nil {
+		code := "[HANDLER] DeleteComment = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = ch.commentService.DeleteComment(c.UserContext(), id, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] DeleteComment = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(commentErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Comment deleted successfully"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// UpdateCommentSettings implements CommentHandler.
+func (ch *commentHandler) UpdateCommentSettings(c *fiber.Ctx) error {
+	var req request.CommentSettingsRequest
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] UpdateCommentSettings = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] UpdateCommentSettings = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] UpdateCommentSettings = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] UpdateCommentSettings = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	settings := entity.CommentSettingsEntity{
+		Enabled: *req.Enabled,
+		Closed:  *req.Closed,
+	}
+
+	err = ch.commentService.UpdateCommentSettings(c.UserContext(), contentID, settings, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] UpdateCommentSettings = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(commentErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Comment settings updated"
+	defaultSuccessResponse.Data = response.CommentSettingsResponse{
+		Enabled: settings.Enabled,
+		Closed:  settings.Closed,
+	}
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// GetContentComments implements CommentHandler.
+// Pagination applies to top-level comments; each carries all its approved
+// replies.
+func (ch *commentHandler) GetContentComments(c *fiber.Ctx) error {
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] GetContentComments = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	page, limit, err := ch.pagination.ParsePage(c.Query("page"), c.Query("limit"), 10)
+	if err != nil {
+		code := "[HANDLER] GetContentComments = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	results, totalData, err := ch.commentService.GetContentComments(c.UserContext(), entity.CommentQuery{
+		ContentID: contentID,
+		Page:      page,
+		Limit:     limit,
+	})
+	if err != nil {
+		code := "[HANDLER] GetContentComments = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(commentErrorStatus(err)).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, ch.pagination, int(totalData), page, limit)
+	if err != nil {
+		code := "[HANDLER] GetContentComments = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	respComments := []response.CommentResponse{}
+	for _, comment := range results {
+		respComments = append(respComments, toCommentResponse(comment))
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = respComments
+	defaultSuccessResponse.Pagination = paginationResp
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// CreateComment implements CommentHandler.
+// A reader token is optional; with one, the comment is linked to the
+// reader's account.
+func (ch *commentHandler) CreateComment(c *fiber.Ctx) error {
+	var req request.CommentRequest
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] CreateComment = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] CreateComment = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] CreateComment = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var readerID *int64
+	if claims, ok := c.Locals("reader").(*entity.JwtData); ok && claims.UserID != 0 {
+		id := int64(claims.UserID)
+		readerID = &id
+	}
+
+	if readerID == nil && (req.AuthorName == "" || req.AuthorEmail == "") {
+		code := "[HANDLER] CreateComment = 4"
+		log.Errorw(code, "guest comment without author")
+		errorResp.Status = false
+		errorResp.Message = "author_name and author_email are required unless you are signed in"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := ch.commentService.CreateComment(c.UserContext(), entity.CommentEntity{
+		ContentID:   contentID,
+		ParentID:    req.ParentID,
+		ReaderID:    readerID,
+		AuthorName:  req.AuthorName,
+		AuthorEmail: req.AuthorEmail,
+		Body:        req.Body,
+		IPAddress:   c.IP(),
+		UserAgent:   c.Get(fiber.HeaderUserAgent),
+		Honeypot:    req.Website,
+	})
+	if err != nil {
+		code := "[HANDLER] CreateComment = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(commentErrorStatus(err)).JSON(errorResp)
+	}
+
+	// Spam is reported as pending so bots learn nothing from the answer.
+	respComment := toCommentResponse(*result)
+	respComment.Status = entity.CommentStatusPending
+	message := "Comment submitted and awaiting moderation"
+	if result.Status == entity.CommentStatusApproved {
+		respComment.Status = entity.CommentStatusApproved
+		message = "Comment published"
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = message
+	defaultSuccessResponse.Data = respComment
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.Status(fiber.StatusCreated).JSON(defaultSuccessResponse)
+}
+
+// commentErrorStatus maps comment service errors onto HTTP statuses.
+func commentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, entity.ErrForbidden), errors.Is(err, entity.ErrCommentsDisabled), errors.Is(err, entity.ErrCommentsClosed):
+		return fiber.StatusForbidden
+	case errors.Is(err, entity.ErrInvalidParent):
+		return fiber.StatusUnprocessableEntity
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// toCommentResponse maps a comment for readers, without the author's email
+// or anything about moderation.
+func toCommentResponse(comment entity.CommentEntity) response.CommentResponse {
+	resp := response.CommentResponse{
+		ID:         comment.ID,
+		ContentID:  comment.ContentID,
+		ParentID:   comment.ParentID,
+		AuthorName: comment.AuthorName,
+		Body:       comment.Body,
+		CreatedAt:  comment.CreatedAt.Format(time.RFC3339),
+	}
+
+	for _, reply := range comment.Replies {
+		resp.Replies = append(resp.Replies, toCommentResponse(reply))
+	}
+
+	return resp
+}
+
+func toAdminCommentResponse(comment entity.CommentEntity) response.CommentResponse {
+	resp := toCommentResponse(comment)
+	resp.ContentTitle = comment.ContentTitle
+	resp.AuthorEmail = comment.AuthorEmail
+	resp.Status = comment.Status
+	resp.IPAddress = comment.IPAddress
+	resp.UserAgent = comment.UserAgent
+	resp.ReaderID = comment.ReaderID
+	resp.SpamScore = comment.SpamScore
+	resp.SpamReasons = comment.SpamReasons
+	if comment.ModeratedAt != nil {
+		resp.ModeratedAt = comment.ModeratedAt.Format(time.RFC3339)
+	}
+
+	return resp
+}
+
+func NewCommentHandler(commentService service.CommentService, pagination pagination.PaginationInterface) CommentHandler {
+	return &commentHandler{
+		commentService: commentService,
+		pagination:     pagination,
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"bwanews/internal/adapter/handler/response"
 	"bwanews/internal/core/domain/entity"
 	"bwanews/internal/core/service"
+	"bwanews/lib/blocks"
 	"bwanews/lib/conv"
+	"bwanews/lib/pagination"
 	validatorLib "bwanews/lib/validator"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -21,18 +25,78 @@ type ContentHandler interface {
 	GetContentByID(c *fiber.Ctx) error
 	CreateContent(c *fiber.Ctx) error
 	EditContentByID(c *fiber.Ctx) error
+	PatchContentByID(c *fiber.Ctx) error
 	DeleteContent(c *fiber.Ctx) error
+	BulkContents(c *fiber.Ctx) error
 	UploadImageR2(c *fiber.Ctx) error
 
 	GetContentWithQuery(c *fiber.Ctx) error
 	GetContentDetail(c *fiber.Ctx) error
+	GetRelatedContents(c *fiber.Ctx) error
 }
 
+const (
+	defaultRelatedLimit = 5
+	maxRelatedLimit     = 20
+)
+
 type contentHandler struct {
 	contentService service.ContentService
+	pagination     pagination.PaginationInterface
+}
+
+// GetRelatedContents implements ContentHandler.
+// It lists the published contents to read after the given one, best first.
+func (ch *contentHandler) GetRelatedContents(c *fiber.Ctx) error {
+	id, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] GetRelatedContents = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	limit := c.QueryInt("limit", defaultRelatedLimit)
+	if limit < 1 || limit > maxRelatedLimit {
+		code := "[HANDLER] GetRelatedContents = 2"
+		log.Errorw(code, "invalid limit "+c.Query("limit"))
+		errorResp.Status = false
+		errorResp.Message = fmt.Sprintf("limit must be between 1 and %d", maxRelatedLimit)
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	results, err := ch.contentService.GetRelatedContents(c.UserContext(), id, limit)
+	if err != nil {
+		code := "[HANDLER] GetRelatedContents = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		if errors.Is(err, entity.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	respContents := []response.ContentResponse{}
+	for _, result := range results {
+		respContents = append(respContents, toContentListResponse(result.Content))
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = respContents
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
 }
 
 // GetContentDetail implements ContentHandler.
+// The block document of the body is only returned with ?include=blocks.
 func (ch *contentHandler) GetContentDetail(c *fiber.Ctx) error {
 	idParam := c.Params("contentID")
 	id, err := conv.StringToInt64(idParam)
@@ -45,7 +109,7 @@ func (ch *contentHandler) GetContentDetail(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	result, err := ch.contentService.GetContentByID(c.Context(), id)
+	result, err := ch.contentService.GetContentByID(c.UserContext(), id)
 	if err != nil {
 		code := "[HANDLER] GetContentDetail = 2"
 		log.Errorw(code, err)
@@ -55,22 +119,81 @@ func (ch *contentHandler) GetContentDetail(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
 	}
 
+	meta, err := ch.contentService.ContentMeta(*result)
+	if err != nil {
+		code := "[HANDLER] GetContentDetail = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	embeds, err := ch.contentService.ContentEmbeds(c.UserContext(), *result)
+	if err != nil {
+		code := "[HANDLER] GetContentDetail = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
 	defaultSuccessResponse.Meta.Status = true
 	defaultSuccessResponse.Meta.Message = "Success"
 
 	respContent := response.ContentResponse{
 		ID:           result.ID,
 		Title:        result.Title,
+		Slug:         result.Slug,
 		Excerpt:      result.Excerpt,
 		Description:  result.Description,
+		BodyFormat:   result.BodyFormat,
+		BodyHTML:     result.BodyHTML,
+		Blocks:       result.Blocks,
 		Image:        result.Image,
 		Tags:         result.Tags,
 		Status:       result.Status,
+		Type:         result.Type,
 		CategoryID:   result.CategoryID,
 		CreatedByID:  result.CreatedByID,
 		CreatedAt:    result.CreatedAt.Format(time.RFC3339),
 		CategoryName: result.Category.Title,
 		Author:       result.User.Name,
+		SEO: &response.ContentSEOResponse{
+			MetaTitle:       meta.Title,
+			MetaDescription: meta.Description,
+			CanonicalURL:    meta.CanonicalURL,
+			SocialImage:     meta.Image,
+			TwitterCard:     meta.TwitterCard,
+			NoIndex:         meta.NoIndex,
+		},
+		JSONLD: meta.JSONLD,
+		Comments: &response.CommentSettingsResponse{
+			Enabled: result.Comments.Enabled,
+			Closed:  result.Comments.Closed,
+		},
+	}
+
+	if !strings.Contains(","+c.Query("include")+",", ",blocks,") {
+		respContent.Blocks = nil
+	}
+
+	for _, embed := range embeds {
+		respContent.Embeds = append(respContent.Embeds, response.EmbedResponse{
+			URL:             embed.URL,
+			Provider:        embed.Provider,
+			Type:            embed.Type,
+			Title:           embed.Title,
+			AuthorName:      embed.AuthorName,
+			AuthorURL:       embed.AuthorURL,
+			HTML:            embed.HTML,
+			Width:           embed.Width,
+			Height:          embed.Height,
+			ThumbnailURL:    embed.ThumbnailURL,
+			ThumbnailWidth:  embed.ThumbnailWidth,
+			ThumbnailHeight: embed.ThumbnailHeight,
+		})
 	}
 
 	defaultSuccessResponse.Data = respContent
@@ -80,30 +203,14 @@ func (ch *contentHandler) GetContentDetail(c *fiber.Ctx) error {
 
 // GetContentWithQuery implements ContentHandler.
 func (ch *contentHandler) GetContentWithQuery(c *fiber.Ctx) error {
-	page := 1
-	if c.Query("page") != "" {
-		page, err = conv.StringToInt(c.Query("page"))
-		if err != nil {
-			code := "[HANDLER] GetContentWithQuery = 1"
-			log.Errorw(code, err)
-			errorResp.Status = false
-			errorResp.Message = "Invalid page number"
-
-			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
-		}
-	}
-
-	limit := 6
-	if c.Query("limit") != "" {
-		limit, err = conv.StringToInt(c.Query("limit"))
-		if err != nil {
-			code := "[HANDLER] GetContentWithQuery = 2"
-			log.Errorw(code, err)
-			errorResp.Status = false
-			errorResp.Message = "Invalid limit number"
+	page, limit, err := ch.pagination.ParsePage(c.Query("page"), c.Query("limit"), 6)
+	if err != nil {
+		code := "[HANDLER] GetContentWithQuery = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
 
-			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
-		}
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
 	orderBy := "created_at"
@@ -125,7 +232,7 @@ func (ch *contentHandler) GetContentWithQuery(c *fiber.Ctx) error {
 	if c.Query("categoryID") != "" {
 		categoryID, err = conv.StringToInt(c.Query("categoryID"))
 		if err != nil {
-			code := "[HANDLER] GetContentWithQuery = 3"
+			code := "[HANDLER] GetContentWithQuery = 2"
 			log.Errorw(code, err)
 			errorResp.Status = false
 			errorResp.Message = "Invalid categoryID number"
@@ -144,7 +251,21 @@ func (ch *contentHandler) GetContentWithQuery(c *fiber.Ctx) error {
 		CategoryID: int64(categoryID),
 	}
 
-	results, totalData, totalPages, err := ch.contentService.GetContents(c.Context(), reqEntity)
+	if c.Context().QueryArgs().Has("after") {
+		return ch.getContentsByCursor(c, reqEntity)
+	}
+
+	results, totalData, err := ch.contentService.GetContents(c.UserContext(), reqEntity)
+	if err != nil {
+		code := "[HANDLER] GetContentWithQuery = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, ch.pagination, int(totalData), page, limit)
 	if err != nil {
 		code := "[HANDLER] GetContentWithQuery = 4"
 		log.Errorw(code, err)
@@ -163,11 +284,13 @@ func (ch *contentHandler) GetContentWithQuery(c *fiber.Ctx) error {
 		respContents = append(respContents, response.ContentResponse{
 			ID:           content.ID,
 			Title:        content.Title,
+			Slug:         content.Slug,
 			Excerpt:      content.Excerpt,
 			Description:  content.Description,
 			Image:        content.Image,
 			Tags:         content.Tags,
 			Status:       content.Status,
+			Type:         content.Type,
 			CategoryID:   content.CategoryID,
 			CreatedByID:  content.CreatedByID,
 			CreatedAt:    content.CreatedAt.Format(time.RFC3339),
@@ -177,13 +300,74 @@ func (ch *contentHandler) GetContentWithQuery(c *fiber.Ctx) error {
 	}
 
 	defaultSuccessResponse.Data = respContents
-	defaultSuccessResponse.Pagination = &response.PaginationResponse{
-		TotalRecords: int(totalData),
-		Page:         page,
-		PerPage:      limit,
-		TotalPages:   int(totalPages),
+	defaultSuccessResponse.Cursor = nil
+	defaultSuccessResponse.Pagination = paginationResp
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// getContentsByCursor serves GetContentWithQuery in keyset mode, used when
+// the request carries an `after` cursor (empty for the first page).
+func (ch *contentHandler) getContentsByCursor(c *fiber.Ctx, reqEntity entity.QueryString) error {
+	if after := c.Query("after"); after != "" {
+		cursor, err := ch.pagination.DecodeCursor(after)
+		if err != nil {
+			code := "[HANDLER] GetContentWithQuery = 5"
+			log.Errorw(code, err)
+			errorResp.Status = false
+			errorResp.Message = err.Error()
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+
+		reqEntity.After = cursor
+	}
+
+	results, nextCursor, err := ch.contentService.GetContentsByCursor(c.UserContext(), reqEntity)
+	if err != nil {
+		code := "[HANDLER] GetContentWithQuery = 6"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+
+	respContents := []response.ContentResponse{}
+	for _, content := range results {
+		respContents = append(respContents, response.ContentResponse{
+			ID:           content.ID,
+			Title:        content.Title,
+			Slug:         content.Slug,
+			Excerpt:      content.Excerpt,
+			Description:  content.Description,
+			Image:        content.Image,
+			Tags:         content.Tags,
+			Status:       content.Status,
+			Type:         content.Type,
+			CategoryID:   content.CategoryID,
+			CreatedByID:  content.CreatedByID,
+			CreatedAt:    content.CreatedAt.Format(time.RFC3339),
+			CategoryName: content.Category.Title,
+			Author:       content.User.Name,
+		})
+	}
+
+	cursorResp := &response.CursorResponse{
+		PerPage: reqEntity.Limit,
+	}
+	if nextCursor != nil {
+		cursorResp.NextCursor = ch.pagination.EncodeCursor(*nextCursor)
+		cursorResp.HasMore = true
 	}
 
+	defaultSuccessResponse.Data = respContents
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = cursorResp
+
 	return c.JSON(defaultSuccessResponse)
 }
 
@@ -224,20 +408,33 @@ func (ch *contentHandler) CreateContent(c *fiber.Ctx) error {
 		Title:       req.Title,
 		Excerpt:     req.Excerpt,
 		Description: req.Description,
+		BodyFormat:  req.BodyFormat,
+		Blocks:      json.RawMessage(req.Blocks),
 		Image:       req.Image,
 		Tags:        tags,
 		Status:      req.Status,
+		Type:        req.Type,
+		SEO:         toContentSEOEntity(req.ContentSEORequest),
 		CategoryID:  req.CategoryID,
 		CreatedByID: int64(userID),
 	}
 
-	err = ch.contentService.CreateContent(c.Context(), reqEntity)
-	if err != nil {
+	err = ch.contentService.CreateContent(c.UserContext(), reqEntity)
+	if errors.Is(err, blocks.ErrInvalidDocument) {
 		code := "[HANDLER] CreateContent = 4"
 		log.Errorw(code, err)
 		errorResp.Status = false
 		errorResp.Message = err.Error()
 
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err != nil {
+		code := "[HANDLER] CreateContent = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
 	}
 
@@ -271,7 +468,7 @@ func (ch *contentHandler) DeleteContent(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	err = ch.contentService.DeleteContent(c.Context(), id)
+	err = ch.contentService.DeleteContent(c.UserContext(), id)
 	if err != nil {
 		code := "[HANDLER] DeleteContent = 3"
 		log.Errorw(code, err)
@@ -301,9 +498,29 @@ func (ch *contentHandler) EditContentByID(c *fiber.Ctx) error {
 	}
 
 	userID := claims.UserID
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if ifMatch == "" {
+		code := "[HANDLER] EditContentByID = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "If-Match header is required"
+
+		return c.Status(fiber.StatusPreconditionRequired).JSON(errorResp)
+	}
+
+	version, err := parseIfMatch(ifMatch)
+	if err != nil {
+		code := "[HANDLER] EditContentByID = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
 	var req request.ContentRequest
 	if err = c.BodyParser(&req); err != nil {
-		code := "[HANDLER] EditContentByID = 2"
+		code := "[HANDLER] EditContentByID = 4"
 		log.Errorw(code, err)
 		errorResp.Status = false
 		errorResp.Message = "Invalid request body"
@@ -312,7 +529,7 @@ func (ch *contentHandler) EditContentByID(c *fiber.Ctx) error {
 	}
 
 	if err = validatorLib.ValidateStruct(req); err != nil {
-		code := "[HANDLER] EditContentByID = 3"
+		code := "[HANDLER] EditContentByID = 5"
 		log.Errorw(code, err)
 		errorResp.Status = false
 		errorResp.Message = err.Error()
@@ -323,7 +540,7 @@ func (ch *contentHandler) EditContentByID(c *fiber.Ctx) error {
 	idParam := c.Params("contentID")
 	id, err := conv.StringToInt64(idParam)
 	if err != nil {
-		code := "[HANDLER] EditContentByID = 4"
+		code := "[HANDLER] EditContentByID = 6"
 		log.Errorw(code, err)
 		errorResp.Status = false
 		errorResp.Message = err.Error()
@@ -337,16 +554,38 @@ func (ch *contentHandler) EditContentByID(c *fiber.Ctx) error {
 		Title:       req.Title,
 		Excerpt:     req.Excerpt,
 		Description: req.Description,
+		BodyFormat:  req.BodyFormat,
+		Blocks:      json.RawMessage(req.Blocks),
 		Image:       req.Image,
 		Tags:        tags,
 		Status:      req.Status,
+		Type:        req.Type,
+		SEO:         toContentSEOEntity(req.ContentSEORequest),
 		CategoryID:  req.CategoryID,
 		CreatedByID: int64(userID),
+		Version:     version,
+	}
+
+	err = ch.contentService.EditContentByID(c.UserContext(), reqEntity)
+	if errors.Is(err, entity.ErrVersionConflict) {
+		return ch.contentConflict(c, id, fiber.StatusPreconditionFailed, err)
+	}
+
+	if errors.Is(err, entity.ErrContentLocked) {
+		return ch.contentConflict(c, id, fiber.StatusLocked, err)
+	}
+
+	if errors.Is(err, blocks.ErrInvalidDocument) {
+		code := "[HANDLER] EditContentByID = 7"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	err = ch.contentService.EditContentByID(c.Context(), reqEntity)
 	if err != nil {
-		code := "[HANDLER] EditContentByID = 5"
+		code := "[HANDLER] EditContentByID = 8"
 		log.Errorw(code, err)
 		errorResp.Status = false
 		errorResp.Message = err.Error()
@@ -358,9 +597,368 @@ func (ch *contentHandler) EditContentByID(c *fiber.Ctx) error {
 	defaultSuccessResponse.Meta.Message = "Content updated successfully"
 	defaultSuccessResponse.Data = nil
 
+	c.Set(fiber.HeaderETag, contentETag(version+1))
+
 	return c.JSON(defaultSuccessResponse)
 }
 
+// PatchContentByID implements ContentHandler.
+// The body is a JSON Merge Patch: absent members are kept, null clears image
+// and tags. As for PUT, If-Match is required.
+func (ch *contentHandler) PatchContentByID(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] PatchContentByID = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if ifMatch == "" {
+		code := "[HANDLER] PatchContentByID = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "If-Match header is required"
+
+		return c.Status(fiber.StatusPreconditionRequired).JSON(errorResp)
+	}
+
+	version, err := parseIfMatch(ifMatch)
+	if err != nil {
+		code := "[HANDLER] PatchContentByID = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var req request.ContentPatchRequest
+	if err = json.Unmarshal(c.Body(), &req); err != nil {
+		code := "[HANDLER] PatchContentByID = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = req.Validate(); err != nil {
+		code := "[HANDLER] PatchContentByID = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] PatchContentByID = 6"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	patch := entity.ContentPatch{
+		ID:          id,
+		UpdatedByID: int64(claims.UserID),
+		Version:     version,
+	}
+
+	if req.Title.Set {
+		patch.Title = &req.Title.Value
+	}
+
+	if req.Excerpt.Set {
+		patch.Excerpt = &req.Excerpt.Value
+	}
+
+	if req.Description.Set {
+		patch.Description = &req.Description.Value
+	}
+
+	if req.BodyFormat.Set {
+		patch.BodyFormat = &req.BodyFormat.Value
+	}
+
+	if req.Blocks.Set {
+		patch.Blocks = &req.Blocks.Value
+	}
+
+	if req.Image.Set {
+		patch.Image = &req.Image.Value
+	}
+
+	if req.Tags.Set {
+		tags := []string{}
+		if req.Tags.Value != "" {
+			tags = strings.Split(req.Tags.Value, ",")
+		}
+		patch.Tags = &tags
+	}
+
+	if req.Status.Set {
+		patch.Status = &req.Status.Value
+	}
+
+	if req.Type.Set {
+		patch.Type = &req.Type.Value
+	}
+
+	if req.CategoryID.Set {
+		patch.CategoryID = &req.CategoryID.Value
+	}
+
+	if req.MetaTitle.Set {
+		patch.SEO.MetaTitle = &req.MetaTitle.Value
+	}
+
+	if req.MetaDescription.Set {
+		patch.SEO.MetaDescription = &req.MetaDescription.Value
+	}
+
+	if req.CanonicalURL.Set {
+		patch.SEO.CanonicalURL = &req.CanonicalURL.Value
+	}
+
+	if req.SocialImage.Set {
+		patch.SEO.SocialImage = &req.SocialImage.Value
+	}
+
+	if req.NoIndex.Set {
+		patch.SEO.NoIndex = &req.NoIndex.Value
+	}
+
+	err = ch.contentService.PatchContentByID(c.UserContext(), patch)
+	if errors.Is(err, entity.ErrVersionConflict) {
+		return ch.contentConflict(c, id, fiber.StatusPreconditionFailed, err)
+	}
+
+	if errors.Is(err, entity.ErrContentLocked) {
+		return ch.contentConflict(c, id, fiber.StatusLocked, err)
+	}
+
+	if errors.Is(err, entity.ErrNotFound) {
+		code := "[HANDLER] PatchContentByID = 7"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusNotFound).JSON(errorResp)
+	}
+
+	if errors.Is(err, blocks.ErrInvalidDocument) {
+		code := "[HANDLER] PatchContentByID = 8"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err != nil {
+		code := "[HANDLER] PatchContentByID = 9"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Content updated successfully"
+	defaultSuccessResponse.Data = nil
+
+	c.Set(fiber.HeaderETag, contentETag(version+1))
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// BulkContents implements ContentHandler.
+func (ch *contentHandler) BulkContents(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] BulkContents = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	var req request.BulkContentRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] BulkContents = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] BulkContents = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		code := "[HANDLER] BulkContents = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Exactly one of ids or filter is required"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	reqEntity := entity.BulkContentEntity{
+		IDs:        req.IDs,
+		Action:     req.Action,
+		CategoryID: req.CategoryID,
+		Atomic:     req.Atomic,
+		UserID:     int64(claims.UserID),
+	}
+
+	if req.Filter != nil {
+		reqEntity.Filter = &entity.QueryString{
+			Search:     strings.TrimSpace(req.Filter.Search),
+			Status:     req.Filter.Status,
+			CategoryID: req.Filter.CategoryID,
+		}
+	}
+
+	if req.Action == entity.BulkActionRetag {
+		reqEntity.Tags = []string{}
+		if req.Tags != "" {
+			reqEntity.Tags = strings.Split(req.Tags, ",")
+		}
+	}
+
+	result, err := ch.contentService.BulkContents(c.UserContext(), reqEntity)
+	if errors.Is(err, entity.ErrBulkRolledBack) {
+		conflictResp := response.ConflictResponse{
+			Meta: response.Meta{
+				Status:  false,
+				Message: err.Error(),
+			},
+			Data: toBulkContentResponse(result),
+		}
+
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(conflictResp)
+	}
+
+	if err != nil {
+		code := "[HANDLER] BulkContents = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		status := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, entity.ErrForbidden):
+			status = fiber.StatusForbidden
+		case errors.Is(err, entity.ErrBulkTooLarge), errors.Is(err, entity.ErrEmptyBulkFilter), errors.Is(err, entity.ErrNotFound):
+			status = fiber.StatusBadRequest
+		}
+
+		return c.Status(status).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = toBulkContentResponse(result)
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+func toBulkContentResponse(result *entity.BulkContentResult) response.BulkContentResponse {
+	resp := response.BulkContentResponse{
+		Action:    result.Action,
+		Atomic:    result.Atomic,
+		Total:     result.Total,
+		Succeeded: result.Succeeded,
+		Failed:    result.Failed,
+		Items:     []response.BulkItemResponse{},
+	}
+
+	for _, item := range result.Items {
+		resp.Items = append(resp.Items, response.BulkItemResponse{
+			ID:     item.ID,
+			Status: item.Status,
+			Error:  item.Error,
+		})
+	}
+
+	return resp
+}
+
+// contentConflict answers a rejected edit with status and the content as
+// currently stored, including its version and edit lock, so the editor can
+// merge or wait before saving again.
+func (ch *contentHandler) contentConflict(c *fiber.Ctx, id int64, status int, cause error) error {
+	result, err := ch.contentService.GetContentByID(c.UserContext(), id)
+	if err != nil {
+		code := "[HANDLER] EditContentByID = 8"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusNotFound).JSON(errorResp)
+	}
+
+	respContent := response.ContentResponse{
+		ID:           result.ID,
+		Title:        result.Title,
+		Slug:         result.Slug,
+		Excerpt:      result.Excerpt,
+		Description:  result.Description,
+		BodyFormat:   result.BodyFormat,
+		BodyHTML:     result.BodyHTML,
+		Blocks:       result.Blocks,
+		Image:        result.Image,
+		Tags:         result.Tags,
+		Status:       result.Status,
+		Type:         result.Type,
+		CategoryID:   result.CategoryID,
+		CreatedByID:  result.CreatedByID,
+		CreatedAt:    result.CreatedAt.Format(time.RFC3339),
+		CategoryName: result.Category.Title,
+		Author:       result.User.Name,
+		Version:      result.Version,
+		Lock:         toContentLockResponse(result.Lock),
+		SEO: &response.ContentSEOResponse{
+			MetaTitle:       result.SEO.MetaTitle,
+			MetaDescription: result.SEO.MetaDescription,
+			CanonicalURL:    result.SEO.CanonicalURL,
+			SocialImage:     result.SEO.SocialImage,
+			NoIndex:         result.SEO.NoIndex,
+		},
+	}
+
+	conflictResp := response.ConflictResponse{
+		Meta: response.Meta{
+			Status:  false,
+			Message: cause.Error(),
+		},
+		Data: respContent,
+	}
+
+	c.Set(fiber.HeaderETag, contentETag(result.Version))
+
+	return c.Status(status).JSON(conflictResp)
+}
+
 // GetContentByID implements ContentHandler.
 func (ch *contentHandler) GetContentByID(c *fiber.Ctx) error {
 	claims := c.Locals("user").(*entity.JwtData)
@@ -384,7 +982,7 @@ func (ch *contentHandler) GetContentByID(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	result, err := ch.contentService.GetContentByID(c.Context(), id)
+	result, err := ch.contentService.GetContentByID(c.UserContext(), id)
 	if err != nil {
 		code := "[HANDLER] GetContentByID = 3"
 		log.Errorw(code, err)
@@ -400,20 +998,33 @@ func (ch *contentHandler) GetContentByID(c *fiber.Ctx) error {
 	respContent := response.ContentResponse{
 		ID:           result.ID,
 		Title:        result.Title,
+		Slug:         result.Slug,
 		Excerpt:      result.Excerpt,
 		Description:  result.Description,
+		BodyFormat:   result.BodyFormat,
+		BodyHTML:     result.BodyHTML,
+		Blocks:       result.Blocks,
 		Image:        result.Image,
 		Tags:         result.Tags,
 		Status:       result.Status,
+		Type:         result.Type,
 		CategoryID:   result.CategoryID,
 		CreatedByID:  result.CreatedByID,
 		CreatedAt:    result.CreatedAt.Format(time.RFC3339),
 		CategoryName: result.Category.Title,
 		Author:       result.User.Name,
+		Version:      result.Version,
+		Lock:         toContentLockResponse(result.Lock),
+		Comments: &response.CommentSettingsResponse{
+			Enabled: result.Comments.Enabled,
+			Closed:  result.Comments.Closed,
+		},
 	}
 
 	defaultSuccessResponse.Data = respContent
 
+	c.Set(fiber.HeaderETag, contentETag(result.Version))
+
 	return c.Status(fiber.StatusOK).JSON(defaultSuccessResponse)
 }
 
@@ -428,30 +1039,14 @@ func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
 
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
-	page := 1
-	if c.Query("page") != "" {
-		page, err = conv.StringToInt(c.Query("page"))
-		if err != nil {
-			code := "[HANDLER] GetContents = 2"
-			log.Errorw(code, err)
-			errorResp.Status = false
-			errorResp.Message = "Invalid page number"
-
-			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
-		}
-	}
-
-	limit := 10
-	if c.Query("limit") != "" {
-		limit, err = conv.StringToInt(c.Query("limit"))
-		if err != nil {
-			code := "[HANDLER] GetContents = 3"
-			log.Errorw(code, err)
-			errorResp.Status = false
-			errorResp.Message = "Invalid limit number"
+	page, limit, err := ch.pagination.ParsePage(c.Query("page"), c.Query("limit"), 10)
+	if err != nil {
+		code := "[HANDLER] GetContents = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
 
-			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
-		}
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
 	orderBy := "created_at"
@@ -473,7 +1068,7 @@ func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
 	if c.Query("categoryID") != "" {
 		categoryID, err = conv.StringToInt(c.Query("categoryID"))
 		if err != nil {
-			code := "[HANDLER] GetContents = 4"
+			code := "[HANDLER] GetContents = 3"
 			log.Errorw(code, err)
 			errorResp.Status = false
 			errorResp.Message = "Invalid categoryID number"
@@ -491,7 +1086,17 @@ func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
 		CategoryID: int64(categoryID),
 	}
 
-	results, totalData, totalPages, err := ch.contentService.GetContents(c.Context(), reqEntity)
+	results, totalData, err := ch.contentService.GetContents(c.UserContext(), reqEntity)
+	if err != nil {
+		code := "[HANDLER] GetContents = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, ch.pagination, int(totalData), page, limit)
 	if err != nil {
 		code := "[HANDLER] GetContents = 5"
 		log.Errorw(code, err)
@@ -510,11 +1115,13 @@ func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
 		respContents = append(respContents, response.ContentResponse{
 			ID:           content.ID,
 			Title:        content.Title,
+			Slug:         content.Slug,
 			Excerpt:      content.Excerpt,
 			Description:  content.Description,
 			Image:        content.Image,
 			Tags:         content.Tags,
 			Status:       content.Status,
+			Type:         content.Type,
 			CategoryID:   content.CategoryID,
 			CreatedByID:  content.CreatedByID,
 			CreatedAt:    content.CreatedAt.Format(time.RFC3339),
@@ -524,13 +1131,8 @@ func (ch *contentHandler) GetContents(c *fiber.Ctx) error {
 	}
 
 	defaultSuccessResponse.Data = respContents
-
-	defaultSuccessResponse.Pagination = &response.PaginationResponse{
-		TotalRecords: int(totalData),
-		Page:         1,
-		PerPage:      len(respContents),
-		TotalPages:   int(totalPages),
-	}
+	defaultSuccessResponse.Cursor = nil
+	defaultSuccessResponse.Pagination = paginationResp
 
 	return c.JSON(defaultSuccessResponse)
 }
@@ -573,7 +1175,7 @@ func (ch *contentHandler) UploadImageR2(c *fiber.Ctx) error {
 		Path: req.Image,
 	}
 
-	imageUrl, err := ch.contentService.UploadImageR2(c.Context(), reqEntity)
+	imageUrl, err := ch.contentService.UploadImageR2(c.UserContext(), reqEntity)
 	if err != nil {
 		code := "[HANDLER] UploadImageR2 = 4"
 		log.Errorw(code, err)
@@ -606,6 +1208,19 @@ func (ch *contentHandler) UploadImageR2(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(defaultSuccessResponse)
 }
 
-func NewContentHandler(contentService service.ContentService) ContentHandler {
-	return &contentHandler{contentService: contentService}
+func toContentSEOEntity(req request.ContentSEORequest) entity.ContentSEOEntity {
+	return entity.ContentSEOEntity{
+		MetaTitle:       req.MetaTitle,
+		MetaDescription: req.MetaDescription,
+		CanonicalURL:    req.CanonicalURL,
+		SocialImage:     req.SocialImage,
+		NoIndex:         req.NoIndex,
+	}
+}
+
+func NewContentHandler(contentService service.ContentService, pagination pagination.PaginationInterface) ContentHandler {
+	return &contentHandler{
+		contentService: contentService,
+		pagination:     pagination,
+	}
 }
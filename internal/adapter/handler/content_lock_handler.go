@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/conv"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type ContentLockHandler interface {
+	AcquireLock(c *fiber.Ctx) error
+	HeartbeatLock(c *fiber.Ctx) error
+	ReleaseLock(c *fiber.Ctx) error
+	ForceLock(c *fiber.Ctx) error
+}
+
+type contentLockHandler struct {
+	contentLockService service.ContentLockService
+}
+
+// AcquireLock implements ContentLockHandler.
+func (cl *contentLockHandler) AcquireLock(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] AcquireLock = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] AcquireLock = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	lock, err := cl.contentLockService.AcquireLock(c.UserContext(), id, int64(claims.UserID))
+	if errors.Is(err, entity.ErrContentLocked) {
+		conflictResp := response.ConflictResponse{
+			Meta: response.Meta{
+				Status:  false,
+				Message: err.Error(),
+			},
+			Data: toContentLockResponse(lock),
+		}
+
+		return c.Status(fiber.StatusConflict).JSON(conflictResp)
+	}
+
+	if err != nil {
+		code := "[HANDLER] AcquireLock = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Lock acquired"
+	defaultSuccessResponse.Data = toContentLockResponse(lock)
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// ForceLock implements ContentLockHandler.
+func (cl *contentLockHandler) ForceLock(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] ForceLock = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] ForceLock = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	lock, err := cl.contentLockService.ForceLock(c.UserContext(), id, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] ForceLock = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		if errors.Is(err, entity.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(errorResp)
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Lock taken over"
+	defaultSuccessResponse.Data = toContentLockResponse(lock)
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// HeartbeatLock implements ContentLockHandler.
+func (cl *contentLockHandler) HeartbeatLock(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] HeartbeatLock = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] HeartbeatLock = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	lock, err := cl.contentLockService.HeartbeatLock(c.UserContext(), id, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] HeartbeatLock = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		if errors.Is(err, entity.ErrLockNotHeld) {
+			return c.Status(fiber.StatusConflict).JSON(errorResp)
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Lock extended"
+	defaultSuccessResponse.Data = toContentLockResponse(lock)
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// ReleaseLock implements ContentLockHandler.
+func (cl *contentLockHandler) ReleaseLock(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] ReleaseLock = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] ReleaseLock = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = cl.contentLockService.ReleaseLock(c.UserContext(), id, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] ReleaseLock = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Lock released"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// toContentLockResponse returns nil for an unlocked content so the field is
+// left out of the JSON.
+func toContentLockResponse(lock *entity.ContentLockEntity) *response.ContentLockResponse {
+	if lock == nil {
+		return nil
+	}
+
+	return &response.ContentLockResponse{
+		ContentID:   lock.ContentID,
+		UserID:      lock.UserID,
+		LockedBy:    lock.UserName,
+		LockedSince: lock.AcquiredAt.Format(time.RFC3339),
+		HeartbeatAt: lock.HeartbeatAt.Format(time.RFC3339),
+		ExpiresAt:   lock.ExpiresAt.Format(time.RFC3339),
+		Message:     fmt.Sprintf("locked by %s since %s", lock.UserName, lock.AcquiredAt.Format(time.RFC3339)),
+	}
+}
+
+func NewContentLockHandler(contentLockService service.ContentLockService) ContentLockHandler {
+	return &contentLockHandler{
+		contentLockService: contentLockService,
+	}
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bwanews/config"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/conv"
+	"bwanews/lib/feed"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const feedLimit = 20
+
+type FeedHandler interface {
+	RSS(c *fiber.Ctx) error
+	Atom(c *fiber.Ctx) error
+	JSONFeed(c *fiber.Ctx) error
+}
+
+type feedHandler struct {
+	contentService  service.ContentService
+	categoryService service.CategoryService
+	cfg             *config.Config
+}
+
+// RSS implements FeedHandler.
+func (fh *feedHandler) RSS(c *fiber.Ctx) error {
+	return fh.serveFeed(c, "application/rss+xml; charset=utf-8", feed.RSS)
+}
+
+// Atom implements FeedHandler.
+func (fh *feedHandler) Atom(c *fiber.Ctx) error {
+	return fh.serveFeed(c, "application/atom+xml; charset=utf-8", feed.Atom)
+}
+
+// JSONFeed implements FeedHandler.
+func (fh *feedHandler) JSONFeed(c *fiber.Ctx) error {
+	return fh.serveFeed(c, "application/feed+json; charset=utf-8", feed.JSON)
+}
+
+// serveFeed answers conditional requests with 304 using an ETag over the
+// rendered body and Last-Modified from the newest item.
+func (fh *feedHandler) serveFeed(c *fiber.Ctx, contentType string, render func(feed.Feed) ([]byte, error)) error {
+	f, err := fh.buildFeed(c)
+	if errors.Is(err, entity.ErrNotFound) {
+		code := "[HANDLER] serveFeed = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Category not found"
+
+		return c.Status(fiber.StatusNotFound).JSON(errorResp)
+	}
+
+	if err != nil {
+		code := "[HANDLER] serveFeed = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	body, err := render(*f)
+	if err != nil {
+		code := "[HANDLER] serveFeed = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	sum := sha256.Sum256(body)
+	etag := fmt.Sprintf(`"%x"`, sum[:16])
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	if !f.Updated.IsZero() {
+		c.Set(fiber.HeaderLastModified, f.Updated.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, etag, f.Updated) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Send(body)
+}
+
+// buildFeed loads the latest published contents, narrowed by the
+// :categorySlug or :tag route parameter when present. ?mode=full adds the
+// article body to every item.
+func (fh *feedHandler) buildFeed(c *fiber.Ctx) (*feed.Feed, error) {
+	app := fh.cfg.App
+	siteName := app.SiteName
+	if siteName == "" {
+		siteName = "Bwanews"
+	}
+
+	language := app.SiteLanguage
+	if language == "" {
+		language = "id"
+	}
+
+	f := &feed.Feed{
+		Title:       siteName,
+		Description: "Latest news from " + siteName,
+		Link:        app.PublicBaseURL + "/",
+		FeedURL:     c.BaseURL() + c.OriginalURL(),
+		Language:    language,
+	}
+
+	query := entity.QueryString{
+		Limit:     feedLimit,
+		Page:      1,
+		OrderBy:   "created_at",
+		OrderType: "DESC",
+		Status:    entity.ContentStatusPublish,
+	}
+
+	if categorySlug := c.Params("categorySlug"); categorySlug != "" {
+		category, err := fh.categoryService.GetCategoryBySlug(c.UserContext(), categorySlug)
+		if err != nil {
+			return nil, err
+		}
+
+		query.CategoryID = category.ID
+		f.Title = siteName + " - " + category.Title
+		f.Description = "Latest " + category.Title + " news from " + siteName
+		f.Link = app.PublicBaseURL + conv.CategoryPath(app.CategoryPath, category.Slug)
+	}
+
+	if tag, _ := url.PathUnescape(c.Params("tag")); tag != "" {
+		query.Tag = tag
+		f.Title = siteName + " - #" + tag
+		f.Description = "Latest news tagged " + tag + " from " + siteName
+		f.Link = app.PublicBaseURL + conv.TagPath(app.TagPath, tag)
+	}
+
+	results, _, err := fh.contentService.GetContents(c.UserContext(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	full := c.Query("mode") == "full"
+	for _, content := range results {
+		link := app.PublicBaseURL + conv.ContentPath(app.ContentPath, content.Category.Slug, content.Slug)
+		item := feed.Item{
+			ID:         link,
+			Title:      content.Title,
+			Link:       link,
+			Summary:    content.Excerpt,
+			Author:     content.User.Name,
+			Image:      content.Image,
+			Categories: []string{content.Category.Title},
+			Published:  content.CreatedAt,
+			Updated:    content.UpdatedAt,
+		}
+
+		for _, tag := range content.Tags {
+			if tag != "" {
+				item.Categories = append(item.Categories, tag)
+			}
+		}
+
+		if full {
+			item.Content = content.BodyHTML
+		}
+
+		if content.UpdatedAt.After(f.Updated) {
+			f.Updated = content.UpdatedAt
+		}
+
+		f.Items = append(f.Items, item)
+	}
+
+	return f, nil
+}
+
+func NewFeedHandler(contentService service.ContentService, categoryService service.CategoryService, cfg *config.Config) FeedHandler {
+	return &feedHandler{
+		contentService:  contentService,
+		categoryService: categoryService,
+		cfg:             cfg,
+	}
+}
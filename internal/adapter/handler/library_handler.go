@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"bwanews/internal/adapter/handler/request"
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/conv"
+	"bwanews/lib/pagination"
+	validatorLib "bwanews/lib/validator"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type LibraryHandler interface {
+	GetBookmarks(c *fiber.Ctx) error
+	CreateBookmark(c *fiber.Ctx) error
+	DeleteBookmark(c *fiber.Ctx) error
+	GetReadingHistory(c *fiber.Ctx) error
+	SaveReadingProgress(c *fiber.Ctx) error
+}
+
+type libraryHandler struct {
+	libraryService service.LibraryService
+	pagination     pagination.PaginationInterface
+}
+
+// GetBookmarks implements LibraryHandler.
+func (lh *libraryHandler) GetBookmarks(c *fiber.Ctx) error {
+	claims := c.Locals("reader").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetBookmarks = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	page, limit, err := lh.pagination.ParsePage(c.Query("page"), c.Query("limit"), pagination.DefaultLimit)
+	if err != nil {
+		code := "[HANDLER] GetBookmarks = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	results, totalData, err := lh.libraryService.GetBookmarks(c.UserContext(), entity.LibraryQuery{
+		ReaderID: int64(claims.UserID),
+		Page:     page,
+		Limit:    limit,
+	})
+	if err != nil {
+		code := "[HANDLER] GetBookmarks = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, lh.pagination, int(totalData), page, limit)
+	if err != nil {
+		code := "[HANDLER] GetBookmarks = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	respBookmarks := []response.BookmarkResponse{}
+	for _, bookmark := range results {
+		respBookmarks = append(respBookmarks, response.BookmarkResponse{
+			ContentID:    bookmark.ContentID,
+			BookmarkedAt: bookmark.CreatedAt.Format(time.RFC3339),
+			Content:      toContentListResponse(bookmark.Content),
+		})
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = respBookmarks
+	defaultSuccessResponse.Pagination = paginationResp
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// CreateBookmark implements LibraryHandler.
+// It is idempotent: bookmarking a content twice keeps one bookmark.
+func (lh *libraryHandler) CreateBookmark(c *fiber.Ctx) error {
+	claims := c.Locals("reader").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] CreateBookmark = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] CreateBookmark = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = lh.libraryService.Bookmark(c.UserContext(), int64(claims.UserID), contentID)
+	if err != nil {
+		code := "[HANDLER] CreateBookmark = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(libraryErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Bookmark saved"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// DeleteBookmark implements LibraryHandler.
+func (lh *libraryHandler) DeleteBookmark(c *fiber.Ctx) error {
+	claims := c.Locals("reader").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] DeleteBookmark = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] DeleteBookmark = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = lh.libraryService.RemoveBookmark(c.UserContext(), int64(claims.UserID), contentID)
+	if err != nil {
+		code := "[HANDLER] DeleteBookmark = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(libraryErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Bookmark removed"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// GetReadingHistory implements LibraryHandler.
+// With ?unfinished=true it only lists contents the reader can resume.
+func (lh *libraryHandler) GetReadingHistory(c *fiber.Ctx) error {
+	claims := c.Locals("reader").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetReadingHistory = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	page, limit, err := lh.pagination.ParsePage(c.Query("page"), c.Query("limit"), pagination.DefaultLimit)
+	if err != nil {
+		code := "[HANDLER] GetReadingHistory = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	results, totalData, err := lh.libraryService.GetReadingHistory(c.UserContext(), entity.LibraryQuery{
+		ReaderID:   int64(claims.UserID),
+		Unfinished: c.QueryBool("unfinished"),
+		Page:       page,
+		Limit:      limit,
+	})
+	if err != nil {
+		code := "[HANDLER] GetReadingHistory = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, lh.pagination, int(totalData), page, limit)
+	if err != nil {
+		code := "[HANDLER] GetReadingHistory = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	respHistory := []response.ReadingHistoryResponse{}
+	for _, history := range results {
+		respHistory = append(respHistory, response.ReadingHistoryResponse{
+			ContentID:  history.ContentID,
+			Progress:   history.Progress,
+			LastReadAt: history.UpdatedAt.Format(time.RFC3339),
+			Content:    toContentListResponse(history.Content),
+		})
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = respHistory
+	defaultSuccessResponse.Pagination = paginationResp
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// SaveReadingProgress implements LibraryHandler.
+func (lh *libraryHandler) SaveReadingProgress(c *fiber.Ctx) error {
+	claims := c.Locals("reader").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] SaveReadingProgress = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] SaveReadingProgress = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var req request.ReadingProgressRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] SaveReadingProgress = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] SaveReadingProgress = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = lh.libraryService.SaveReadingProgress(c.UserContext(), int64(claims.UserID), contentID, *req.Progress)
+	if err != nil {
+		code := "[HANDLER] SaveReadingProgress = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(libraryErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Reading progress saved"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// libraryErrorStatus maps library service errors onto HTTP statuses.
+func libraryErrorStatus(err error) int {
+	if errors.Is(err, entity.ErrNotFound) {
+		return fiber.StatusNotFound
+	}
+
+	return fiber.StatusInternalServerError
+}
+
+// toContentListResponse maps a content with the fields of the FE
+// content list.
+func toContentListResponse(content entity.ContentEntity) response.ContentResponse {
+	return response.ContentResponse{
+		ID:           content.ID,
+		Title:        content.Title,
+		Slug:         content.Slug,
+		Excerpt:      content.Excerpt,
+		Description:  content.Description,
+		Image:        content.Image,
+		Tags:         content.Tags,
+		Status:       content.Status,
+		Type:         content.Type,
+		CategoryID:   content.CategoryID,
+		CreatedByID:  content.CreatedByID,
+		CreatedAt:    content.CreatedAt.Format(time.RFC3339),
+		CategoryName: content.Category.Title,
+		Author:       content.User.Name,
+	}
+}
+
+func NewLibraryHandler(libraryService service.LibraryService, pagination pagination.PaginationInterface) LibraryHandler {
+	return &libraryHandler{
+		libraryService: libraryService,
+		pagination:     pagination,
+	}
+}
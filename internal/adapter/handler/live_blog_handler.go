@@ -0,0 +1,556 @@
+package handler
+
+import (
+	"bufio"
+	"bwanews/config"
+	"bwanews/internal/adapter/handler/request"
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/conv"
+	"bwanews/lib/live"
+	"bwanews/lib/pagination"
+	validatorLib "bwanews/lib/validator"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const (
+	defaultLivePollInterval = 5
+
+	// liveHeartbeat keeps idle streams open through proxies that close
+	// silent connections.
+	liveHeartbeat = 15 * time.Second
+	// liveRetry is how long, in milliseconds, browsers wait before they
+	// reconnect a dropped stream.
+	liveRetry = 3000
+	// liveQueryTimeout bounds each read of a stream, which has no request
+	// deadline of its own.
+	liveQueryTimeout = 10 * time.Second
+)
+
+type LiveBlogHandler interface {
+	GetEntries(c *fiber.Ctx) error
+	CreateEntry(c *fiber.Ctx) error
+	UpdateEntry(c *fiber.Ctx) error
+	PinEntry(c *fiber.Ctx) error
+	DeleteEntry(c *fiber.Ctx) error
+
+	GetEntriesFE(c *fiber.Ctx) error
+	StreamEntries(c *fiber.Ctx) error
+}
+
+type liveBlogHandler struct {
+	liveBlogService service.LiveBlogService
+	pagination      pagination.PaginationInterface
+	// streamCtx outlives the requests streams are opened by, which are
+	// cancelled as soon as their handler returns; it is cancelled on
+	// shutdown.
+	streamCtx    context.Context
+	pollInterval time.Duration
+}
+
+// GetEntries implements LiveBlogHandler.
+func (lh *liveBlogHandler) GetEntries(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetEntries = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	return lh.getEntries(c, false)
+}
+
+// GetEntriesFE implements LiveBlogHandler.
+func (lh *liveBlogHandler) GetEntriesFE(c *fiber.Ctx) error {
+	return lh.getEntries(c, true)
+}
+
+// getEntries answers a page of the entries of a live blog, along with the
+// event id to resume its stream from.
+func (lh *liveBlogHandler) getEntries(c *fiber.Ctx, publishedOnly bool) error {
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] getEntries = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid contentID number"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	page, limit, err := lh.pagination.ParsePage(c.Query("page"), c.Query("limit"), 20)
+	if err != nil {
+		code := "[HANDLER] getEntries = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	results, totalData, lastSeq, err := lh.liveBlogService.GetEntries(c.UserContext(), entity.LiveEntryQuery{
+		ContentID:     contentID,
+		Page:          page,
+		Limit:         limit,
+		PublishedOnly: publishedOnly,
+	})
+	if err != nil {
+		code := "[HANDLER] getEntries = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(liveBlogErrorStatus(err)).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, lh.pagination, int(totalData), page, limit)
+	if err != nil {
+		code := "[HANDLER] getEntries = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	respEntries := []response.LiveEntryResponse{}
+	for _, entry := range results {
+		respEntries = append(respEntries, toLiveEntryResponse(entry))
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = response.LiveBlogResponse{
+		LastEventID: lastSeq,
+		Entries:     respEntries,
+	}
+	defaultSuccessResponse.Pagination = paginationResp
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// CreateEntry implements LiveBlogHandler.
+func (lh *liveBlogHandler) CreateEntry(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] CreateEntry = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] CreateEntry = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid contentID number"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var req request.LiveEntryRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] CreateEntry = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] CreateEntry = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := lh.liveBlogService.CreateEntry(c.UserContext(), entity.LiveEntryEntity{
+		ContentID: contentID,
+		Title:     req.Title,
+		Body:      req.Body,
+	}, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] CreateEntry = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(liveBlogErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Entry posted"
+	defaultSuccessResponse.Data = toLiveEntryResponse(*result)
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.Status(fiber.StatusCreated).JSON(defaultSuccessResponse)
+}
+
+// UpdateEntry implements LiveBlogHandler.
+func (lh *liveBlogHandler) UpdateEntry(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] UpdateEntry = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	contentID, entryID, err := liveEntryParams(c)
+	if err != nil {
+		code := "[HANDLER] UpdateEntry = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var req request.LiveEntryRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] UpdateEntry = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] UpdateEntry = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = lh.liveBlogService.UpdateEntry(c.UserContext(), entity.LiveEntryEntity{
+		ID:        entryID,
+		ContentID: contentID,
+		Title:     req.Title,
+		Body:      req.Body,
+	}, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] UpdateEntry = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(liveBlogErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Entry updated"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// PinEntry implements LiveBlogHandler.
+func (lh *liveBlogHandler) PinEntry(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] PinEntry = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	contentID, entryID, err := liveEntryParams(c)
+	if err != nil {
+		code := "[HANDLER] PinEntry = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var req request.LiveEntryPinRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] PinEntry = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = lh.liveBlogService.PinEntry(c.UserContext(), contentID, entryID, req.Pinned, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] PinEntry = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(liveBlogErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Entry updated"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// DeleteEntry implements LiveBlogHandler.
+func (lh *liveBlogHandler) DeleteEntry(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] DeleteEntry = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	contentID, entryID, err := liveEntryParams(c)
+	if err != nil {
+		code := "[HANDLER] DeleteEntry = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = lh.liveBlogService.DeleteEntry(c.UserContext(), contentID, entryID, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] DeleteEntry = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(liveBlogErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Entry deleted"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// StreamEntries implements LiveBlogHandler.
+// It streams the changes to the entries of a published live blog as
+// Server-Sent Events: an entry event carries a new or changed entry, a
+// delete event the id of a removed one. Event ids are the entries' seq, so
+// a reconnecting browser resumes after the last event it saw through
+// Last-Event-ID; clients that cannot set the header pass ?last_event_id=.
+// Without either the stream starts with the next change.
+func (lh *liveBlogHandler) StreamEntries(c *fiber.Ctx) error {
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		code := "[HANDLER] StreamEntries = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid contentID number"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	lastEventID := c.Get("Last-Event-ID", c.Query("last_event_id"))
+	var seq int64
+	if lastEventID != "" {
+		seq, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || seq < 0 {
+			code := "[HANDLER] StreamEntries = 2"
+			log.Errorw(code, err)
+			errorResp.Status = false
+			errorResp.Message = "Invalid last event id"
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+	}
+
+	lastSeq, err := lh.liveBlogService.OpenStream(c.UserContext(), contentID)
+	if err != nil {
+		code := "[HANDLER] StreamEntries = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(liveBlogErrorStatus(err)).JSON(errorResp)
+	}
+
+	if lastEventID == "" {
+		seq = lastSeq
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	// The writer runs after the handler has returned, so it must not touch
+	// c.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		changed, unsubscribe := lh.liveBlogService.Subscribe(contentID)
+		defer unsubscribe()
+
+		poll := time.NewTicker(lh.pollInterval)
+		defer poll.Stop()
+
+		heartbeat := time.NewTicker(liveHeartbeat)
+		defer heartbeat.Stop()
+
+		if _, err := fmt.Fprintf(w, "retry: %d\n\n", liveRetry); err != nil || w.Flush() != nil {
+			return
+		}
+
+		for {
+			seq, err = lh.sendChanges(w, contentID, seq)
+			if err != nil {
+				return
+			}
+
+			select {
+			case <-lh.streamCtx.Done():
+				return
+			case <-changed:
+			case <-poll.C:
+			case <-heartbeat.C:
+				if live.WriteComment(w, "heartbeat") != nil || w.Flush() != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+// sendChanges writes the changes after seq as events and returns the seq
+// of the last one sent. It only fails when the reader went away; a failed
+// query is retried on the next wake-up.
+func (lh *liveBlogHandler) sendChanges(w *bufio.Writer, contentID, seq int64) (int64, error) {
+	for {
+		ctx, cancel := context.WithTimeout(lh.streamCtx, liveQueryTimeout)
+		changes, err := lh.liveBlogService.GetChanges(ctx, contentID, seq)
+		cancel()
+		if err != nil {
+			code := "[HANDLER] sendChanges = 1"
+			log.Errorw(code, err)
+			return seq, nil
+		}
+
+		if len(changes) == 0 {
+			return seq, nil
+		}
+
+		for _, change := range changes {
+			event, data := "entry", interface{}(toLiveEntryResponse(change))
+			if change.DeletedAt != nil {
+				event, data = "delete", response.LiveEntryDeletedResponse{ID: change.ID}
+			}
+
+			payload, err := json.Marshal(data)
+			if err != nil {
+				return seq, err
+			}
+
+			if err = live.WriteEvent(w, change.Seq, event, payload); err != nil {
+				return seq, err
+			}
+
+			seq = change.Seq
+		}
+
+		if err = w.Flush(); err != nil {
+			return seq, err
+		}
+	}
+}
+
+// liveEntryParams reads the content and entry ids of a live entry route.
+func liveEntryParams(c *fiber.Ctx) (int64, int64, error) {
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil {
+		return 0, 0, errors.New("Invalid contentID number")
+	}
+
+	entryID, err := conv.StringToInt64(c.Params("entryID"))
+	if err != nil {
+		return 0, 0, errors.New("Invalid entryID number")
+	}
+
+	return contentID, entryID, nil
+}
+
+// liveBlogErrorStatus maps live blog service errors onto HTTP statuses.
+func liveBlogErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, entity.ErrForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, entity.ErrNotLiveBlog):
+		return fiber.StatusUnprocessableEntity
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func toLiveEntryResponse(entry entity.LiveEntryEntity) response.LiveEntryResponse {
+	resp := response.LiveEntryResponse{
+		ID:         entry.ID,
+		ContentID:  entry.ContentID,
+		Title:      entry.Title,
+		Body:       entry.Body,
+		Pinned:     entry.Pinned,
+		AuthorName: entry.AuthorName,
+		CreatedAt:  entry.CreatedAt.Format(time.RFC3339),
+	}
+
+	if entry.UpdatedAt != nil {
+		resp.UpdatedAt = entry.UpdatedAt.Format(time.RFC3339)
+	}
+
+	return resp
+}
+
+func NewLiveBlogHandler(liveBlogService service.LiveBlogService, pagination pagination.PaginationInterface, streamCtx context.Context, cfg *config.Config) LiveBlogHandler {
+	interval := cfg.App.LivePollInterval
+	if interval <= 0 {
+		interval = defaultLivePollInterval
+	}
+
+	return &liveBlogHandler{
+		liveBlogService: liveBlogService,
+		pagination:      pagination,
+		streamCtx:       streamCtx,
+		pollInterval:    time.Duration(interval) * time.Second,
+	}
+}
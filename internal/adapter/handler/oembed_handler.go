@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/conv"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// oembedCacheAge is how long, in seconds, consumers may cache our oEmbed
+// responses.
+const oembedCacheAge = 3600
+
+type OEmbedHandler interface {
+	OEmbed(c *fiber.Ctx) error
+}
+
+type oembedHandler struct {
+	embedService service.EmbedService
+}
+
+// OEmbed implements OEmbedHandler.
+// It lets partners embed our published articles; only the json format is
+// supported, as most providers do.
+func (oh *oembedHandler) OEmbed(c *fiber.Ctx) error {
+	rawURL := c.Query("url")
+	if rawURL == "" {
+		code := "[HANDLER] OEmbed = 1"
+		log.Errorw(code, "url is required")
+		errorResp.Status = false
+		errorResp.Message = "url is required"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if format := c.Query("format", "json"); format != "json" {
+		code := "[HANDLER] OEmbed = 2"
+		log.Errorw(code, "unsupported format "+format)
+		errorResp.Status = false
+		errorResp.Message = "Only the json format is supported"
+
+		return c.Status(fiber.StatusNotImplemented).JSON(errorResp)
+	}
+
+	var maxSize [2]int
+	for i, name := range []string{"maxwidth", "maxheight"} {
+		if c.Query(name) == "" {
+			continue
+		}
+
+		value, err := conv.StringToInt(c.Query(name))
+		if err != nil || value < 0 {
+			code := "[HANDLER] OEmbed = 3"
+			log.Errorw(code, err)
+			errorResp.Status = false
+			errorResp.Message = "Invalid " + name + " number"
+
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+
+		maxSize[i] = value
+	}
+
+	result, err := oh.embedService.ContentOEmbed(c.UserContext(), rawURL, maxSize[0], maxSize[1])
+	if errors.Is(err, entity.ErrNotFound) {
+		code := "[HANDLER] OEmbed = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Content not found"
+
+		return c.Status(fiber.StatusNotFound).JSON(errorResp)
+	}
+
+	if err != nil {
+		code := "[HANDLER] OEmbed = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", oembedCacheAge))
+	return c.Status(fiber.StatusOK).JSON(response.OEmbedResponse{
+		Type:         result.Type,
+		Version:      "1.0",
+		Title:        result.Title,
+		AuthorName:   result.AuthorName,
+		ProviderName: result.ProviderName,
+		ProviderURL:  result.ProviderURL,
+		CacheAge:     oembedCacheAge,
+		HTML:         result.HTML,
+		Width:        result.Width,
+		Height:       result.Height,
+	})
+}
+
+func NewOEmbedHandler(embedService service.EmbedService) OEmbedHandler {
+	return &oembedHandler{embedService: embedService}
+}
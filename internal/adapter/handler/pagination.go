@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/lib/pagination"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// paginationResponse checks page against totalData through lib/pagination,
+// sets the RFC 8288 Link header and builds the pagination block of a list.
+func paginationResponse(c *fiber.Ctx, paginate pagination.PaginationInterface, totalData, page, limit int) (*response.PaginationResponse, error) {
+	pageData, err := paginate.AddPagination(totalData, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if uri, err := url.Parse(c.BaseURL() + c.OriginalURL()); err == nil {
+		c.Set(fiber.HeaderLink, paginate.LinkHeader(uri, pageData))
+	}
+
+	return &response.PaginationResponse{
+		TotalRecords: pageData.TotalCount,
+		Page:         pageData.Page,
+		PerPage:      pageData.Perpage,
+		TotalPages:   pageData.PageCount,
+	}, nil
+}
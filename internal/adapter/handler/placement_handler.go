@@ -0,0 +1,399 @@
+package handler
+
+import (
+	"bwanews/internal/adapter/handler/request"
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/conv"
+	"bwanews/lib/pagination"
+	validatorLib "bwanews/lib/validator"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const maxSlotLimit = 50
+
+type PlacementHandler interface {
+	GetPlacements(c *fiber.Ctx) error
+	GetPlacementByID(c *fiber.Ctx) error
+	CreatePlacement(c *fiber.Ctx) error
+	UpdatePlacement(c *fiber.Ctx) error
+	DeletePlacement(c *fiber.Ctx) error
+
+	GetSlotContents(c *fiber.Ctx) error
+}
+
+type placementHandler struct {
+	placementService service.PlacementService
+	pagination       pagination.PaginationInterface
+}
+
+// GetPlacements implements PlacementHandler.
+// ?slot= narrows the list down to one slot.
+func (ph *placementHandler) GetPlacements(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetPlacements = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	page, limit, err := ph.pagination.ParsePage(c.Query("page"), c.Query("limit"), 20)
+	if err != nil {
+		code := "[HANDLER] GetPlacements = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	results, totalData, err := ph.placementService.GetPlacements(c.UserContext(), entity.PlacementQuery{
+		Slot:  c.Query("slot"),
+		Page:  page,
+		Limit: limit,
+	})
+	if err != nil {
+		code := "[HANDLER] GetPlacements = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, ph.pagination, int(totalData), page, limit)
+	if err != nil {
+		code := "[HANDLER] GetPlacements = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	now := time.Now()
+	respPlacements := []response.PlacementResponse{}
+	for _, placement := range results {
+		respPlacements = append(respPlacements, toPlacementResponse(placement, now))
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = respPlacements
+	defaultSuccessResponse.Pagination = paginationResp
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// GetPlacementByID implements PlacementHandler.
+func (ph *placementHandler) GetPlacementByID(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetPlacementByID = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("placementID"))
+	if err != nil {
+		code := "[HANDLER] GetPlacementByID = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := ph.placementService.GetPlacementByID(c.UserContext(), id)
+	if err != nil {
+		code := "[HANDLER] GetPlacementByID = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(placementErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = toPlacementResponse(*result, time.Now())
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// CreatePlacement implements PlacementHandler.
+func (ph *placementHandler) CreatePlacement(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] CreatePlacement = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	var req request.PlacementRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] CreatePlacement = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] CreatePlacement = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := ph.placementService.CreatePlacement(c.UserContext(), entity.PlacementEntity{
+		Slot:      req.Slot,
+		ContentID: req.ContentID,
+		Position:  req.Position,
+		StartsAt:  req.StartsAt,
+		ExpiresAt: req.ExpiresAt,
+	}, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] CreatePlacement = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(placementErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Placement saved"
+	defaultSuccessResponse.Data = toPlacementResponse(*result, time.Now())
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.Status(fiber.StatusCreated).JSON(defaultSuccessResponse)
+}
+
+// UpdatePlacement implements PlacementHandler.
+func (ph *placementHandler) UpdatePlacement(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] UpdatePlacement = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("placementID"))
+	if err != nil {
+		code := "[HANDLER] UpdatePlacement = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var req request.PlacementScheduleRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] UpdatePlacement = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] UpdatePlacement = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = ph.placementService.UpdatePlacement(c.UserContext(), id, entity.PlacementSchedule{
+		Position:  req.Position,
+		StartsAt:  req.StartsAt,
+		ExpiresAt: req.ExpiresAt,
+	}, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] UpdatePlacement = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(placementErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Placement updated"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// DeletePlacement implements PlacementHandler.
+func (ph *placementHandler) DeletePlacement(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] DeletePlacement = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("placementID"))
+	if err != nil {
+		code := "[HANDLER] DeletePlacement = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = ph.placementService.DeletePlacement(c.UserContext(), id, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] DeletePlacement = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(placementErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Placement removed"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// GetSlotContents implements PlacementHandler.
+// It returns the published contents live in the slot, in display order.
+func (ph *placementHandler) GetSlotContents(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 10)
+	if limit < 1 || limit > maxSlotLimit {
+		code := "[HANDLER] GetSlotContents = 1"
+		log.Errorw(code, "invalid limit "+c.Query("limit"))
+		errorResp.Status = false
+		errorResp.Message = fmt.Sprintf("limit must be between 1 and %d", maxSlotLimit)
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	// Clients may escape the colon of pinned:<category-slug>.
+	slot, err := url.PathUnescape(c.Params("slot"))
+	if err != nil {
+		code := "[HANDLER] GetSlotContents = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = entity.ErrInvalidSlot.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	results, err := ph.placementService.GetSlotContents(c.UserContext(), slot, limit)
+	if err != nil {
+		code := "[HANDLER] GetSlotContents = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(placementErrorStatus(err)).JSON(errorResp)
+	}
+
+	respContents := []response.SlotContentResponse{}
+	for _, placement := range results {
+		respContent := response.SlotContentResponse{
+			Position: placement.Position,
+			Content:  toContentListResponse(placement.Content),
+		}
+		if placement.ExpiresAt != nil {
+			respContent.ExpiresAt = placement.ExpiresAt.Format(time.RFC3339)
+		}
+
+		respContents = append(respContents, respContent)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = respContents
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// placementErrorStatus maps placement service errors onto HTTP statuses.
+func placementErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, entity.ErrForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, entity.ErrInvalidSlot), errors.Is(err, entity.ErrInvalidSchedule):
+		return fiber.StatusUnprocessableEntity
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func toPlacementResponse(placement entity.PlacementEntity, now time.Time) response.PlacementResponse {
+	resp := response.PlacementResponse{
+		ID:            placement.ID,
+		Slot:          placement.Slot,
+		ContentID:     placement.ContentID,
+		ContentTitle:  placement.Content.Title,
+		ContentStatus: placement.Content.Status,
+		Position:      placement.Position,
+		Live:          placement.Live(now) && placement.Content.Status == entity.ContentStatusPublish,
+		CreatedAt:     placement.CreatedAt.Format(time.RFC3339),
+	}
+
+	if placement.StartsAt != nil {
+		resp.StartsAt = placement.StartsAt.Format(time.RFC3339)
+	}
+
+	if placement.ExpiresAt != nil {
+		resp.ExpiresAt = placement.ExpiresAt.Format(time.RFC3339)
+	}
+
+	return resp
+}
+
+func NewPlacementHandler(placementService service.PlacementService, pagination pagination.PaginationInterface) PlacementHandler {
+	return &placementHandler{
+		placementService: placementService,
+		pagination:       pagination,
+	}
+}
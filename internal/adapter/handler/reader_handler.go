@@ -0,0 +1,371 @@
+package handler
+
+import (
+	"bwanews/internal/adapter/handler/request"
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	validatorLib "bwanews/lib/validator"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type ReaderHandler interface {
+	Register(c *fiber.Ctx) error
+	VerifyEmail(c *fiber.Ctx) error
+	ResendVerification(c *fiber.Ctx) error
+	Login(c *fiber.Ctx) error
+	GetProfile(c *fiber.Ctx) error
+	UpdateProfile(c *fiber.Ctx) error
+	UpdatePassword(c *fiber.Ctx) error
+}
+
+type readerHandler struct {
+	readerService service.ReaderService
+}
+
+// Register implements ReaderHandler.
+func (rh *readerHandler) Register(c *fiber.Ctx) error {
+	var req request.ReaderRegisterRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] Register = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] Register = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := rh.readerService.Register(c.UserContext(), entity.ReaderEntity{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		code := "[HANDLER] Register = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(readerErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Registration successful, check your email to verify your address"
+	defaultSuccessResponse.Data = toReaderResponse(*result)
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.Status(fiber.StatusCreated).JSON(defaultSuccessResponse)
+}
+
+// VerifyEmail implements ReaderHandler.
+func (rh *readerHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req request.ReaderVerifyEmailRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] VerifyEmail = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] VerifyEmail = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = rh.readerService.VerifyEmail(c.UserContext(), req.Token)
+	if err != nil {
+		code := "[HANDLER] VerifyEmail = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(readerErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Email verified, you can now sign in"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// ResendVerification implements ReaderHandler.
+// It answers the same whether or not the email belongs to a reader.
+func (rh *readerHandler) ResendVerification(c *fiber.Ctx) error {
+	var req request.ReaderResendVerificationRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] ResendVerification = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] ResendVerification = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = rh.readerService.ResendVerification(c.UserContext(), req.Email)
+	if err != nil {
+		code := "[HANDLER] ResendVerification = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "If the address waits for verification, a new link is on its way"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// Login implements ReaderHandler.
+func (rh *readerHandler) Login(c *fiber.Ctx) error {
+	req := request.LoginRequest{}
+	resp := response.SuccessAuthResponse{}
+
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] ReaderLogin = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] ReaderLogin = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := rh.readerService.Login(c.UserContext(), entity.LoginRequest{
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		code := "[HANDLER] ReaderLogin = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(readerErrorStatus(err)).JSON(errorResp)
+	}
+
+	resp.Meta.Status = true
+	resp.Meta.Message = "Login successful"
+	resp.AccessToken = result.AccessToken
+	resp.ExpiresAt = result.ExpiresAt
+
+	return c.JSON(resp)
+}
+
+// GetProfile implements ReaderHandler.
+func (rh *readerHandler) GetProfile(c *fiber.Ctx) error {
+	claims := c.Locals("reader").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetProfile = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	result, err := rh.readerService.GetReaderByID(c.UserContext(), int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] GetProfile = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(readerErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = toReaderResponse(*result)
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// UpdateProfile implements ReaderHandler.
+func (rh *readerHandler) UpdateProfile(c *fiber.Ctx) error {
+	claims := c.Locals("reader").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] UpdateProfile = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	var req request.ReaderProfileRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] UpdateProfile = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] UpdateProfile = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = rh.readerService.UpdateProfile(c.UserContext(), int64(claims.UserID), req.Name)
+	if err != nil {
+		code := "[HANDLER] UpdateProfile = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(readerErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Profile updated"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// UpdatePassword implements ReaderHandler.
+func (rh *readerHandler) UpdatePassword(c *fiber.Ctx) error {
+	claims := c.Locals("reader").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] ReaderUpdatePassword = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	var req request.UpdatePasswordRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] ReaderUpdatePassword = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] ReaderUpdatePassword = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if req.ConfirmPassword != req.NewPassword {
+		code := "[HANDLER] ReaderUpdatePassword = 4"
+		log.Errorw(code, "passwords do not match")
+		errorResp.Status = false
+		errorResp.Message = "Password do not match"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = rh.readerService.UpdatePassword(c.UserContext(), int64(claims.UserID), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		code := "[HANDLER] ReaderUpdatePassword = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(readerErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success Update Password"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// readerErrorStatus maps reader service errors onto HTTP statuses.
+func readerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, entity.ErrEmailTaken):
+		return fiber.StatusConflict
+	case errors.Is(err, entity.ErrInvalidCredentials):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, entity.ErrEmailNotVerified):
+		return fiber.StatusForbidden
+	case errors.Is(err, entity.ErrInvalidToken), errors.Is(err, entity.ErrCurrentPassword):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func toReaderResponse(reader entity.ReaderEntity) response.ReaderResponse {
+	return response.ReaderResponse{
+		ID:            reader.ID,
+		Name:          reader.Name,
+		Email:         reader.Email,
+		EmailVerified: reader.Verified(),
+		CreatedAt:     reader.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func NewReaderHandler(readerService service.ReaderService) ReaderHandler {
+	return &readerHandler{
+		readerService: readerService,
+	}
+}
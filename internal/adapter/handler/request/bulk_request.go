@@ -0,0 +1,16 @@
+package request
+
+type BulkContentFilterRequest struct {
+	Search     string `json:"search"`
+	Status     string `json:"status"`
+	CategoryID int64  `json:"category_id"`
+}
+
+type BulkContentRequest struct {
+	IDs        []int64                   `json:"ids"`
+	Filter     *BulkContentFilterRequest `json:"filter"`
+	Action     string                    `json:"action" validate:"required,oneof=publish draft archive recategorize retag delete"`
+	CategoryID int64                     `json:"category_id" validate:"required_if=Action recategorize"`
+	Tags       string                    `json:"tags"`
+	Atomic     bool                      `json:"atomic"`
+}
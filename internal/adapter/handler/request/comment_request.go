@@ -0,0 +1,18 @@
+package request
+
+// CommentRequest is a new comment. Guests must give a name and an email;
+// signed-in readers comment under their account and may leave them out.
+type CommentRequest struct {
+	ParentID    *int64 `json:"parent_id"`
+	AuthorName  string `json:"author_name" validate:"omitempty,max=100"`
+	AuthorEmail string `json:"author_email" validate:"omitempty,email,max=200"`
+	Body        string `json:"body" validate:"required,max=5000"`
+
+	// Website is a honeypot: the form hides it, so only bots fill it in.
+	Website string `json:"website"`
+}
+
+type CommentSettingsRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+	Closed  *bool `json:"closed" validate:"required"`
+}
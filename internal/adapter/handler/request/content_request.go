@@ -1,11 +1,44 @@
 package request
 
+import "encoding/json"
+
 type ContentRequest struct {
 	Title       string `json:"title" validate:"required"`
 	Excerpt     string `json:"excerpt" validate:"required"`
-	Description string `json:"description" validate:"required"`
+	Description string `json:"description" validate:"required_without=Blocks"`
+	BodyFormat  string `json:"body_format" validate:"omitempty,oneof=html markdown"`
 	Image       string `json:"image" validate:"required"`
 	Tags        string `json:"tags"`
 	CategoryID  int64  `json:"category_id" validate:"required"`
 	Status      string `json:"status" validate:"required"`
+	Type        string `json:"type" validate:"omitempty,oneof=article liveblog"`
+
+	// Blocks replaces Description with its rendering when sent.
+	Blocks OptionalJSON `json:"blocks"`
+
+	ContentSEORequest
+}
+
+// ContentSEORequest holds the optional SEO overrides of a content; empty
+// members fall back to the regular content fields.
+type ContentSEORequest struct {
+	MetaTitle       string `json:"meta_title" validate:"max=200"`
+	MetaDescription string `json:"meta_description" validate:"max=500"`
+	CanonicalURL    string `json:"canonical_url" validate:"omitempty,http_url,max=500"`
+	SocialImage     string `json:"social_image" validate:"omitempty,http_url,max=500"`
+	NoIndex         bool   `json:"noindex"`
+}
+
+// OptionalJSON is a raw JSON member that stays empty when sent as null, so
+// a null counts as absent for required_without.
+type OptionalJSON json.RawMessage
+
+func (o *OptionalJSON) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*o = nil
+		return nil
+	}
+
+	*o = append((*o)[:0], data...)
+	return nil
 }
@@ -0,0 +1,5 @@
+package request
+
+type ReadingProgressRequest struct {
+	Progress *int `json:"progress" validate:"required,min=0,max=100"`
+}
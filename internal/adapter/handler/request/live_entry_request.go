@@ -0,0 +1,13 @@
+package request
+
+// LiveEntryRequest posts or rewrites an entry of a live blog. The body is
+// HTML and is sanitized before it is stored.
+type LiveEntryRequest struct {
+	Title string `json:"title" validate:"max=200"`
+	Body  string `json:"body" validate:"required"`
+}
+
+// LiveEntryPinRequest pins an entry to the top of a live blog or unpins it.
+type LiveEntryPinRequest struct {
+	Pinned bool `json:"pinned"`
+}
@@ -0,0 +1,136 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PatchField records whether a JSON Merge Patch (RFC 7396) member was sent
+// and whether it was null, which plain Go fields cannot tell apart.
+type PatchField[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (p *PatchField[T]) UnmarshalJSON(data []byte) error {
+	p.Set = true
+	if string(data) == "null" {
+		p.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &p.Value)
+}
+
+type ContentPatchRequest struct {
+	Title       PatchField[string] `json:"title"`
+	Excerpt     PatchField[string] `json:"excerpt"`
+	Description PatchField[string] `json:"description"`
+	BodyFormat  PatchField[string] `json:"body_format"`
+	Image       PatchField[string] `json:"image"`
+	Tags        PatchField[string] `json:"tags"`
+	CategoryID  PatchField[int64]  `json:"category_id"`
+	Status      PatchField[string] `json:"status"`
+	Type        PatchField[string] `json:"type"`
+
+	Blocks PatchField[json.RawMessage] `json:"blocks"`
+
+	MetaTitle       PatchField[string] `json:"meta_title"`
+	MetaDescription PatchField[string] `json:"meta_description"`
+	CanonicalURL    PatchField[string] `json:"canonical_url"`
+	SocialImage     PatchField[string] `json:"social_image"`
+	NoIndex         PatchField[bool]   `json:"noindex"`
+}
+
+// Validate checks every member that was sent. Image, tags and the SEO
+// members may be null or empty to clear them, and a null body_format resets
+// it to html; the other members can only be replaced.
+func (r ContentPatchRequest) Validate() error {
+	var errorMessages []string
+	required := []struct {
+		name  string
+		field PatchField[string]
+	}{
+		{"title", r.Title},
+		{"excerpt", r.Excerpt},
+		{"description", r.Description},
+		{"status", r.Status},
+	}
+
+	for _, member := range required {
+		if member.field.Set && (member.field.Null || strings.TrimSpace(member.field.Value) == "") {
+			errorMessages = append(errorMessages, member.name+" cannot be empty")
+		}
+	}
+
+	if r.CategoryID.Set && (r.CategoryID.Null || r.CategoryID.Value <= 0) {
+		errorMessages = append(errorMessages, "category_id must be a valid category")
+	}
+
+	if r.BodyFormat.Set && r.BodyFormat.Value != "" && r.BodyFormat.Value != "html" && r.BodyFormat.Value != "markdown" {
+		errorMessages = append(errorMessages, "body_format must be one of: html markdown")
+	}
+
+	if r.Status.Set && r.Status.Value != "" && r.Status.Value != "PUBLISH" && r.Status.Value != "DRAFT" && r.Status.Value != "ARCHIVE" {
+		errorMessages = append(errorMessages, "status must be one of: PUBLISH DRAFT ARCHIVE")
+	}
+
+	if r.Type.Set && r.Type.Value != "" && r.Type.Value != "article" && r.Type.Value != "liveblog" {
+		errorMessages = append(errorMessages, "type must be one of: article liveblog")
+	}
+
+	limits := []struct {
+		name  string
+		field PatchField[string]
+		max   int
+		url   bool
+	}{
+		{"meta_title", r.MetaTitle, 200, false},
+		{"meta_description", r.MetaDescription, 500, false},
+		{"canonical_url", r.CanonicalURL, 500, true},
+		{"social_image", r.SocialImage, 500, true},
+	}
+
+	for _, member := range limits {
+		if member.field.Null || member.field.Value == "" {
+			continue
+		}
+
+		if utf8.RuneCountInString(member.field.Value) > member.max {
+			errorMessages = append(errorMessages, member.name+" must be at most "+strconv.Itoa(member.max)+" characters long")
+		}
+
+		if member.url && !isAbsoluteURL(member.field.Value) {
+			errorMessages = append(errorMessages, member.name+" must be a valid URL")
+		}
+	}
+
+	if len(errorMessages) > 0 {
+		return errors.New("validation error: " + strings.Join(errorMessages, "; "))
+	}
+
+	return nil
+}
+
+func isAbsoluteURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+type CategoryPatchRequest struct {
+	Title PatchField[string] `json:"title"`
+}
+
+// Validate implements the same rules as ContentPatchRequest.Validate.
+func (r CategoryPatchRequest) Validate() error {
+	if r.Title.Set && (r.Title.Null || strings.TrimSpace(r.Title.Value) == "") {
+		return errors.New("validation error: title cannot be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package request
+
+import "time"
+
+// PlacementRequest puts a content in a slot. Placing a content again in a
+// slot it is already in reschedules that placement.
+type PlacementRequest struct {
+	Slot      string     `json:"slot" validate:"required,max=100"`
+	ContentID int64      `json:"content_id" validate:"required"`
+	Position  int        `json:"position" validate:"min=0"`
+	StartsAt  *time.Time `json:"starts_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// PlacementScheduleRequest replaces the ordering and the time bounds of a
+// placement; a missing bound leaves that side open.
+type PlacementScheduleRequest struct {
+	Position  int        `json:"position" validate:"min=0"`
+	StartsAt  *time.Time `json:"starts_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
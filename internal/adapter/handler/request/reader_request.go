@@ -0,0 +1,19 @@
+package request
+
+type ReaderRegisterRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email,max=200"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+type ReaderVerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ReaderResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ReaderProfileRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
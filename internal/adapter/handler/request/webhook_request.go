@@ -0,0 +1,11 @@
+package request
+
+// WebhookRequest adds or replaces a webhook. A secret is generated when a
+// new webhook has none; on an update an empty secret keeps the current one.
+// Active defaults to true.
+type WebhookRequest struct {
+	URL    string   `json:"url" validate:"required,url,max=500"`
+	Secret string   `json:"secret" validate:"omitempty,min=16,max=255"`
+	Events []string `json:"events" validate:"required,min=1"`
+	Active *bool    `json:"active"`
+}
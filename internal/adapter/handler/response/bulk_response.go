@@ -0,0 +1,16 @@
+package response
+
+type BulkItemResponse struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkContentResponse struct {
+	Action    string             `json:"action"`
+	Atomic    bool               `json:"atomic"`
+	Total     int                `json:"total"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Items     []BulkItemResponse `json:"items"`
+}
@@ -0,0 +1,28 @@
+package response
+
+// CommentResponse is shared by the moderation queue and the FE; the author
+// email, IP address and moderation fields are left out on the FE.
+type CommentResponse struct {
+	ID           int64             `json:"id"`
+	ContentID    int64             `json:"content_id"`
+	ContentTitle string            `json:"content_title,omitempty"`
+	ParentID     *int64            `json:"parent_id,omitempty"`
+	AuthorName   string            `json:"author_name"`
+	AuthorEmail  string            `json:"author_email,omitempty"`
+	Body         string            `json:"body"`
+	Status       string            `json:"status,omitempty"`
+	IPAddress    string            `json:"ip_address,omitempty"`
+	UserAgent    string            `json:"user_agent,omitempty"`
+	ModeratedAt  string            `json:"moderated_at,omitempty"`
+	CreatedAt    string            `json:"created_at"`
+	Replies      []CommentResponse `json:"replies,omitempty"`
+
+	ReaderID    *int64   `json:"reader_id,omitempty"`
+	SpamScore   int      `json:"spam_score,omitempty"`
+	SpamReasons []string `json:"spam_reasons,omitempty"`
+}
+
+type CommentSettingsResponse struct {
+	Enabled bool `json:"enabled"`
+	Closed  bool `json:"closed"`
+}
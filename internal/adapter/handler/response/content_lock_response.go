@@ -0,0 +1,11 @@
+package response
+
+type ContentLockResponse struct {
+	ContentID   int64  `json:"content_id"`
+	UserID      int64  `json:"user_id"`
+	LockedBy    string `json:"locked_by"`
+	LockedSince string `json:"locked_since"`
+	HeartbeatAt string `json:"heartbeat_at"`
+	ExpiresAt   string `json:"expires_at"`
+	Message     string `json:"message"`
+}
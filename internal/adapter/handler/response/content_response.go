@@ -1,16 +1,42 @@
 package response
 
+import "encoding/json"
+
 type ContentResponse struct {
 	ID           int64    `json:"id"`
 	Title        string   `json:"title"`
+	Slug         string   `json:"slug"`
 	Excerpt      string   `json:"excerpt"`
 	Description  string   `json:"description,omitempty"`
+	BodyFormat   string   `json:"body_format,omitempty"`
+	BodyHTML     string   `json:"body_html,omitempty"`
 	Image        string   `json:"image"`
 	Tags         []string `json:"tags,omitempty"`
 	Status       string   `json:"status"`
+	Type         string   `json:"type"`
 	CategoryID   int64    `json:"category_id,omitempty"`
 	CreatedByID  int64    `json:"created_by_id,omitempty"`
 	CreatedAt    string   `json:"created_at"`
 	CategoryName string   `json:"category_name"`
 	Author       string   `json:"author"`
+	Version      int64    `json:"version,omitempty"`
+
+	Blocks json.RawMessage      `json:"blocks,omitempty"`
+	Lock   *ContentLockResponse `json:"lock,omitempty"`
+	SEO    *ContentSEOResponse  `json:"seo,omitempty"`
+	JSONLD json.RawMessage      `json:"json_ld,omitempty"`
+
+	Embeds   []EmbedResponse          `json:"embeds,omitempty"`
+	Comments *CommentSettingsResponse `json:"comments,omitempty"`
+}
+
+// ContentSEOResponse carries the stored overrides in the admin API and the
+// resolved values, with fallbacks applied, in the FE API.
+type ContentSEOResponse struct {
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	CanonicalURL    string `json:"canonical_url"`
+	SocialImage     string `json:"social_image"`
+	TwitterCard     string `json:"twitter_card,omitempty"`
+	NoIndex         bool   `json:"noindex"`
 }
@@ -9,10 +9,16 @@ type Meta struct {
 	Message string `json:"message"`
 }
 
+type ConflictResponse struct {
+	Meta Meta        `json:"meta"`
+	Data interface{} `json:"data,omitempty"`
+}
+
 type DefaultSuccessResponse struct {
 	Meta       Meta                `json:"meta"`
 	Data       interface{}         `json:"data,omitempty"`
 	Pagination *PaginationResponse `json:"pagination,omitempty"`
+	Cursor     *CursorResponse     `json:"cursor,omitempty"`
 }
 
 type PaginationResponse struct {
@@ -21,3 +27,9 @@ type PaginationResponse struct {
 	PerPage      int `json:"per_page"`
 	TotalPages   int `json:"total_pages"`
 }
+
+type CursorResponse struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PerPage    int    `json:"per_page"`
+	HasMore    bool   `json:"has_more"`
+}
@@ -0,0 +1,14 @@
+package response
+
+type BookmarkResponse struct {
+	ContentID    int64           `json:"content_id"`
+	BookmarkedAt string          `json:"bookmarked_at"`
+	Content      ContentResponse `json:"content"`
+}
+
+type ReadingHistoryResponse struct {
+	ContentID  int64           `json:"content_id"`
+	Progress   int             `json:"progress"`
+	LastReadAt string          `json:"last_read_at"`
+	Content    ContentResponse `json:"content"`
+}
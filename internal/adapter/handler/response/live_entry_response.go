@@ -0,0 +1,27 @@
+package response
+
+// LiveEntryResponse is an entry of a live blog. It is also the data of the
+// entry events of the live blog stream.
+type LiveEntryResponse struct {
+	ID         int64  `json:"id"`
+	ContentID  int64  `json:"content_id"`
+	Title      string `json:"title,omitempty"`
+	Body       string `json:"body"`
+	Pinned     bool   `json:"pinned"`
+	AuthorName string `json:"author_name,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+}
+
+// LiveBlogResponse is a page of live blog entries. LastEventID is where the
+// stream resumes for a reader that rendered the page.
+type LiveBlogResponse struct {
+	LastEventID int64               `json:"last_event_id"`
+	Entries     []LiveEntryResponse `json:"entries"`
+}
+
+// LiveEntryDeletedResponse is the data of the delete events of the live
+// blog stream.
+type LiveEntryDeletedResponse struct {
+	ID int64 `json:"id"`
+}
@@ -0,0 +1,23 @@
+package response
+
+// PlacementResponse is a placement as editors manage it; Live tells whether
+// the front end shows it right now.
+type PlacementResponse struct {
+	ID            int64  `json:"id"`
+	Slot          string `json:"slot"`
+	ContentID     int64  `json:"content_id"`
+	ContentTitle  string `json:"content_title"`
+	ContentStatus string `json:"content_status"`
+	Position      int    `json:"position"`
+	StartsAt      string `json:"starts_at,omitempty"`
+	ExpiresAt     string `json:"expires_at,omitempty"`
+	Live          bool   `json:"live"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// SlotContentResponse is a content shown in a front-end slot.
+type SlotContentResponse struct {
+	Position  int             `json:"position"`
+	ExpiresAt string          `json:"expires_at,omitempty"`
+	Content   ContentResponse `json:"content"`
+}
@@ -0,0 +1,9 @@
+package response
+
+// TrendingContentResponse is a trending content with its decayed score and
+// its views within the requested window.
+type TrendingContentResponse struct {
+	Score   float64         `json:"score"`
+	Views   int64           `json:"views"`
+	Content ContentResponse `json:"content"`
+}
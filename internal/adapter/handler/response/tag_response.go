@@ -0,0 +1,6 @@
+package response
+
+type TagResponse struct {
+	Name  string `json:"name"`
+	Total int64  `json:"total"`
+}
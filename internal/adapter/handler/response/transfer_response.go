@@ -0,0 +1,18 @@
+package response
+
+type ImportItemResponse struct {
+	Line   int    `json:"line"`
+	Type   string `json:"type"`
+	Slug   string `json:"slug"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ImportReportResponse struct {
+	DryRun  bool                 `json:"dry_run"`
+	Total   int                  `json:"total"`
+	Created int                  `json:"created"`
+	Updated int                  `json:"updated"`
+	Failed  int                  `json:"failed"`
+	Items   []ImportItemResponse `json:"items"`
+}
@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"bwanews/config"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/cache"
+	"bwanews/lib/conv"
+	"bwanews/lib/sitemap"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// sitemapPageSize keeps every content and tag sitemap well under the
+// protocol limit so a single page stays cheap to generate.
+const (
+	sitemapPageSize   = 5000
+	newsSitemapWindow = 48 * time.Hour
+)
+
+var errSitemapPage = errors.New("sitemap page not found")
+
+type SitemapHandler interface {
+	Index(c *fiber.Ctx) error
+	Contents(c *fiber.Ctx) error
+	Categories(c *fiber.Ctx) error
+	Tags(c *fiber.Ctx) error
+	News(c *fiber.Ctx) error
+}
+
+type sitemapHandler struct {
+	contentService  service.ContentService
+	categoryService service.CategoryService
+	cfg             *config.Config
+	cache           *cache.Cache[sitemapDocument]
+}
+
+// sitemapDocument is a rendered sitemap kept in the cache together with
+// its validators.
+type sitemapDocument struct {
+	body     []byte
+	etag     string
+	modified time.Time
+}
+
+// Index implements SitemapHandler.
+func (sh *sitemapHandler) Index(c *fiber.Ctx) error {
+	return sh.serveSitemap(c, func() ([]byte, time.Time, error) {
+		ctx := c.UserContext()
+		_, totalContents, err := sh.contentService.GetSitemapContents(ctx, entity.QueryString{
+			Limit:  1,
+			Page:   1,
+			Status: entity.ContentStatusPublish,
+		})
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		_, totalTags, err := sh.contentService.GetTags(ctx, entity.QueryString{
+			Limit:  1,
+			Page:   1,
+			Status: entity.ContentStatusPublish,
+		})
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		base := c.BaseURL() + "/sitemaps"
+		sitemaps := []sitemap.URL{{Loc: base + "/news.xml"}, {Loc: base + "/categories.xml"}}
+		for page := 1; page <= sitemapPages(totalContents); page++ {
+			sitemaps = append(sitemaps, sitemap.URL{Loc: fmt.Sprintf("%s/contents-%d.xml", base, page)})
+		}
+
+		for page := 1; page <= sitemapPages(totalTags); page++ {
+			sitemaps = append(sitemaps, sitemap.URL{Loc: fmt.Sprintf("%s/tags-%d.xml", base, page)})
+		}
+
+		body, err := sitemap.Index(sitemaps)
+		return body, time.Time{}, err
+	})
+}
+
+// Contents implements SitemapHandler.
+func (sh *sitemapHandler) Contents(c *fiber.Ctx) error {
+	return sh.serveSitemap(c, func() ([]byte, time.Time, error) {
+		page, err := sitemapPage(c)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		results, totalData, err := sh.contentService.GetSitemapContents(c.UserContext(), entity.QueryString{
+			Limit:  sitemapPageSize,
+			Page:   page,
+			Status: entity.ContentStatusPublish,
+		})
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		if page > sitemapPages(totalData) {
+			return nil, time.Time{}, errSitemapPage
+		}
+
+		app := sh.cfg.App
+		var modified time.Time
+		urls := []sitemap.URL{}
+		for _, content := range results {
+			urls = append(urls, sitemap.URL{
+				Loc:     app.PublicBaseURL + conv.ContentPath(app.ContentPath, content.Category.Slug, content.Slug),
+				LastMod: content.UpdatedAt,
+			})
+
+			if content.UpdatedAt.After(modified) {
+				modified = content.UpdatedAt
+			}
+		}
+
+		body, err := sitemap.URLSet(urls)
+		return body, modified, err
+	})
+}
+
+// Categories implements SitemapHandler.
+func (sh *sitemapHandler) Categories(c *fiber.Ctx) error {
+	return sh.serveSitemap(c, func() ([]byte, time.Time, error) {
+		results, _, err := sh.categoryService.GetCategories(c.UserContext(), entity.QueryString{})
+		if err != nil && !errors.Is(err, entity.ErrNotFound) {
+			return nil, time.Time{}, err
+		}
+
+		app := sh.cfg.App
+		var modified time.Time
+		urls := []sitemap.URL{}
+		for _, category := range results {
+			urls = append(urls, sitemap.URL{
+				Loc:     app.PublicBaseURL + conv.CategoryPath(app.CategoryPath, category.Slug),
+				LastMod: category.UpdatedAt,
+			})
+
+			if category.UpdatedAt.After(modified) {
+				modified = category.UpdatedAt
+			}
+		}
+
+		body, err := sitemap.URLSet(urls)
+		return body, modified, err
+	})
+}
+
+// Tags implements SitemapHandler.
+func (sh *sitemapHandler) Tags(c *fiber.Ctx) error {
+	return sh.serveSitemap(c, func() ([]byte, time.Time, error) {
+		page, err := sitemapPage(c)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		results, totalData, err := sh.contentService.GetTags(c.UserContext(), entity.QueryString{
+			Limit:  sitemapPageSize,
+			Page:   page,
+			Status: entity.ContentStatusPublish,
+		})
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		if page > sitemapPages(totalData) {
+			return nil, time.Time{}, errSitemapPage
+		}
+
+		app := sh.cfg.App
+		urls := []sitemap.URL{}
+		for _, tag := range results {
+			urls = append(urls, sitemap.URL{Loc: app.PublicBaseURL + conv.TagPath(app.TagPath, tag.Name)})
+		}
+
+		body, err := sitemap.URLSet(urls)
+		return body, time.Time{}, err
+	})
+}
+
+// News implements SitemapHandler.
+// Google only accepts articles published in the last two days, at most
+// sitemap.MaxNewsURLs of them.
+func (sh *sitemapHandler) News(c *fiber.Ctx) error {
+	return sh.serveSitemap(c, func() ([]byte, time.Time, error) {
+		results, _, err := sh.contentService.GetContents(c.UserContext(), entity.QueryString{
+			Limit:     sitemap.MaxNewsURLs,
+			Page:      1,
+			OrderBy:   "created_at",
+			OrderType: "DESC",
+			Status:    entity.ContentStatusPublish,
+			Since:     time.Now().Add(-newsSitemapWindow),
+		})
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		app := sh.cfg.App
+		publication := sitemap.Publication{Name: app.SiteName, Language: app.SiteLanguage}
+		if publication.Name == "" {
+			publication.Name = "Bwanews"
+		}
+
+		if publication.Language == "" {
+			publication.Language = "id"
+		}
+
+		var modified time.Time
+		urls := []sitemap.NewsURL{}
+		for _, content := range results {
+			urls = append(urls, sitemap.NewsURL{
+				Loc:             app.PublicBaseURL + conv.ContentPath(app.ContentPath, content.Category.Slug, content.Slug),
+				Title:           content.Title,
+				PublicationDate: content.CreatedAt,
+			})
+
+			if content.UpdatedAt.After(modified) {
+				modified = content.UpdatedAt
+			}
+		}
+
+		body, err := sitemap.News(publication, urls)
+		return body, modified, err
+	})
+}
+
+// serveSitemap renders a sitemap through build at most once per cache TTL
+// and answers conditional requests with 304.
+func (sh *sitemapHandler) serveSitemap(c *fiber.Ctx, build func() ([]byte, time.Time, error)) error {
+	key := c.Path()
+	doc, ok := sh.cache.Get(key)
+	if !ok {
+		body, modified, err := build()
+		if errors.Is(err, errSitemapPage) {
+			code := "[HANDLER] serveSitemap = 1"
+			log.Errorw(code, err)
+			errorResp.Status = false
+			errorResp.Message = "Sitemap not found"
+
+			return c.Status(fiber.StatusNotFound).JSON(errorResp)
+		}
+
+		if err != nil {
+			code := "[HANDLER] serveSitemap = 2"
+			log.Errorw(code, err)
+			errorResp.Status = false
+			errorResp.Message = err.Error()
+
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+		}
+
+		sum := sha256.Sum256(body)
+		doc = sitemapDocument{
+			body:     body,
+			etag:     fmt.Sprintf(`"%x"`, sum[:16]),
+			modified: modified,
+		}
+		sh.cache.Set(key, doc)
+	}
+
+	c.Set(fiber.HeaderETag, doc.etag)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	if !doc.modified.IsZero() {
+		c.Set(fiber.HeaderLastModified, doc.modified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, doc.etag, doc.modified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+	return c.Send(doc.body)
+}
+
+// sitemapPage reads the :page route parameter, which starts at 1.
+func sitemapPage(c *fiber.Ctx) (int, error) {
+	page, err := strconv.Atoi(c.Params("page"))
+	if err != nil || page < 1 {
+		return 0, errSitemapPage
+	}
+
+	return page, nil
+}
+
+// sitemapPages returns how many sitemap pages are needed for total URLs.
+// An empty listing still gets one, empty, page.
+func sitemapPages(total int64) int {
+	if total == 0 {
+		return 1
+	}
+
+	return int((total + sitemapPageSize - 1) / sitemapPageSize)
+}
+
+func NewSitemapHandler(contentService service.ContentService, categoryService service.CategoryService, cfg *config.Config) SitemapHandler {
+	ttl := time.Duration(cfg.App.SitemapCacheTTL) * time.Second
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+
+	return &sitemapHandler{
+		contentService:  contentService,
+		categoryService: categoryService,
+		cfg:             cfg,
+		cache:           cache.New[sitemapDocument](ttl),
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/conv"
+	"bwanews/lib/views"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const maxTrendingLimit = 50
+
+type StatsHandler interface {
+	TrackView(c *fiber.Ctx) error
+	GetTrending(c *fiber.Ctx) error
+}
+
+type statsHandler struct {
+	statsService service.StatsService
+}
+
+// TrackView implements StatsHandler.
+// It always answers 204 so clients cannot tell counted views from bots,
+// prefetches and repeat views, which are ignored.
+func (sh *statsHandler) TrackView(c *fiber.Ctx) error {
+	contentID, err := conv.StringToInt64(c.Params("contentID"))
+	if err != nil || contentID <= 0 {
+		code := "[HANDLER] TrackView = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid contentID number"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	prefetch := strings.Contains(c.Get("Sec-Purpose")+c.Get("Purpose"), "prefetch")
+	if prefetch || views.IsBot(c.Get(fiber.HeaderUserAgent)) {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	sh.statsService.TrackView(contentID, visitorID(c))
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// visitorID identifies the visitor for view dedupe: the reader when signed
+// in, otherwise a hash of the IP address and user agent so neither is kept.
+func visitorID(c *fiber.Ctx) string {
+	if claims, ok := c.Locals("reader").(*entity.JwtData); ok && claims.UserID != 0 {
+		return "reader:" + strconv.FormatInt(int64(claims.UserID), 10)
+	}
+
+	sum := sha256.Sum256([]byte(c.IP() + "|" + c.Get(fiber.HeaderUserAgent)))
+	return "guest:" + hex.EncodeToString(sum[:16])
+}
+
+// GetTrending implements StatsHandler.
+func (sh *statsHandler) GetTrending(c *fiber.Ctx) error {
+	window := c.Query("window", "24h")
+	limit := c.QueryInt("limit", 10)
+	if limit < 1 || limit > maxTrendingLimit {
+		code := "[HANDLER] GetTrending = 1"
+		log.Errorw(code, "invalid limit "+c.Query("limit"))
+		errorResp.Status = false
+		errorResp.Message = "limit must be between 1 and " + strconv.Itoa(maxTrendingLimit)
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	results, err := sh.statsService.GetTrending(c.UserContext(), window, limit)
+	if err != nil {
+		code := "[HANDLER] GetTrending = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		if errors.Is(err, entity.ErrUnknownWindow) {
+			return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	respTrending := []response.TrendingContentResponse{}
+	for _, trending := range results {
+		respTrending = append(respTrending, response.TrendingContentResponse{
+			Score:   trending.Score,
+			Views:   trending.Views,
+			Content: toContentListResponse(trending.Content),
+		})
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = respTrending
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+func NewStatsHandler(statsService service.StatsService) StatsHandler {
+	return &statsHandler{
+		statsService: statsService,
+	}
+}
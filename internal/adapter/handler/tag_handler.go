@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/pagination"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type TagHandler interface {
+	GetTagFE(c *fiber.Ctx) error
+}
+
+type tagHandler struct {
+	contentService service.ContentService
+	pagination     pagination.PaginationInterface
+}
+
+// GetTagFE implements TagHandler.
+func (th *tagHandler) GetTagFE(c *fiber.Ctx) error {
+	page, limit, err := th.pagination.ParsePage(c.Query("page"), c.Query("limit"), pagination.DefaultLimit)
+	if err != nil {
+		code := "[HANDLER] GetTagFE = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	reqEntity := entity.QueryString{
+		Limit:  limit,
+		Page:   page,
+		Search: c.Query("search"),
+		Status: "PUBLISH",
+	}
+
+	results, totalData, err := th.contentService.GetTags(c.UserContext(), reqEntity)
+	if err != nil {
+		code := "[HANDLER] GetTagFE = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, th.pagination, int(totalData), page, limit)
+	if err != nil {
+		code := "[HANDLER] GetTagFE = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	tagResponses := []response.TagResponse{}
+	for _, tag := range results {
+		tagResponses = append(tagResponses, response.TagResponse{
+			Name:  tag.Name,
+			Total: tag.Total,
+		})
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Successfully retrieved tags"
+	defaultSuccessResponse.Data = tagResponses
+	defaultSuccessResponse.Pagination = paginationResp
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+func NewTagHandler(contentService service.ContentService, pagination pagination.PaginationInterface) TagHandler {
+	return &tagHandler{
+		contentService: contentService,
+		pagination:     pagination,
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"bufio"
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/transfer"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type TransferHandler interface {
+	Export(c *fiber.Ctx) error
+	Import(c *fiber.Ctx) error
+}
+
+type transferHandler struct {
+	transferService service.TransferService
+	// streamCtx outlives the export requests, which are cancelled as soon
+	// as their handler returns; it is cancelled on shutdown.
+	streamCtx context.Context
+}
+
+// Export implements TransferHandler.
+// The file is streamed as it is read from the database. Errors found once
+// streaming has begun can no longer change the status, so they cut the file
+// short and are logged.
+func (th *transferHandler) Export(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] Export = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	format := c.Query("format", entity.TransferFormatJSONL)
+	contentType := "application/x-ndjson"
+	if format == entity.TransferFormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+
+	reqEntity := entity.ExportOptions{
+		Format: format,
+		UserID: int64(claims.UserID),
+	}
+
+	err = th.transferService.CheckExport(c.UserContext(), reqEntity)
+	if err != nil {
+		code := "[HANDLER] Export = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(transferErrorStatus(err)).JSON(errorResp)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="bwanews-%s.%s"`, time.Now().Format("20060102"), format))
+
+	// The writer runs after the handler has returned, so it must not touch
+	// c.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := th.transferService.Export(th.streamCtx, w, reqEntity); err != nil {
+			code := "[HANDLER] Export = 3"
+			log.Errorw(code, err)
+		}
+	})
+
+	return nil
+}
+
+// Import implements TransferHandler.
+// The file comes from the multipart field "file"; its extension picks the
+// format unless ?format= is given, and ?dry_run=true only reports.
+func (th *transferHandler) Import(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] Import = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		code := "[HANDLER] Import = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		code := "[HANDLER] Import = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+	defer src.Close()
+
+	reqEntity := entity.ImportOptions{
+		Format: c.Query("format", transfer.FormatFromFilename(file.Filename)),
+		DryRun: c.QueryBool("dry_run"),
+		UserID: int64(claims.UserID),
+	}
+
+	result, err := th.transferService.Import(c.UserContext(), src, reqEntity)
+	if err != nil {
+		code := "[HANDLER] Import = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(transferErrorStatus(err)).JSON(errorResp)
+	}
+
+	resp := response.ImportReportResponse{
+		DryRun:  result.DryRun,
+		Total:   result.Total,
+		Created: result.Created,
+		Updated: result.Updated,
+		Failed:  result.Failed,
+		Items:   []response.ImportItemResponse{},
+	}
+
+	for _, item := range result.Items {
+		resp.Items = append(resp.Items, response.ImportItemResponse{
+			Line:   item.Line,
+			Type:   item.Type,
+			Slug:   item.Slug,
+			Action: item.Action,
+			Error:  item.Error,
+		})
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = resp
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+func transferErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, entity.ErrUnknownFormat), errors.Is(err, entity.ErrMalformedImport):
+		return fiber.StatusBadRequest
+	}
+
+	return fiber.StatusInternalServerError
+}
+
+func NewTransferHandler(transferService service.TransferService, streamCtx context.Context) TransferHandler {
+	return &transferHandler{
+		transferService: transferService,
+		streamCtx:       streamCtx,
+	}
+}
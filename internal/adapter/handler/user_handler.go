@@ -5,6 +5,7 @@ import (
 	"bwanews/internal/adapter/handler/response"
 	"bwanews/internal/core/domain/entity"
 	"bwanews/internal/core/service"
+	"bwanews/lib/pagination"
 	validatorLib "bwanews/lib/validator"
 	"errors"
 
@@ -15,10 +16,12 @@ import (
 type UserHandler interface {
 	UpdatePassword(c *fiber.Ctx) error
 	GetUserByID(c *fiber.Ctx) error
+	GetUsers(c *fiber.Ctx) error
 }
 
 type userHandler struct {
 	userService service.UserService
+	pagination  pagination.PaginationInterface
 }
 
 // GetUserByID implements UserHandler.
@@ -33,7 +36,7 @@ func (u *userHandler) GetUserByID(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
 	}
 
-	user, err := u.userService.GetUserByID(c.Context(), int64(claims.UserID))
+	user, err := u.userService.GetUserByID(c.UserContext(), int64(claims.UserID))
 	if err != nil {
 		code := "[HANDLER] GetUserByID-2"
 		log.Errorw(code, err)
@@ -50,6 +53,7 @@ func (u *userHandler) GetUserByID(c *fiber.Ctx) error {
 		ID:    user.ID,
 		Name:  user.Name,
 		Email: user.Email,
+		Role:  user.Role,
 	}
 	defaultSuccessResponse.Data = resp
 
@@ -57,6 +61,78 @@ func (u *userHandler) GetUserByID(c *fiber.Ctx) error {
 
 }
 
+// GetUsers implements UserHandler.
+func (u *userHandler) GetUsers(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetUsers-1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	page, limit, err := u.pagination.ParsePage(c.Query("page"), c.Query("limit"), pagination.DefaultLimit)
+	if err != nil {
+		code := "[HANDLER] GetUsers-2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	reqEntity := entity.QueryString{
+		Limit:  limit,
+		Page:   page,
+		Search: c.Query("search"),
+	}
+
+	results, totalData, err := u.userService.GetUsers(c.UserContext(), reqEntity, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] GetUsers-3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, entity.ErrForbidden) {
+			status = fiber.StatusForbidden
+		}
+
+		return c.Status(status).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, u.pagination, int(totalData), page, limit)
+	if err != nil {
+		code := "[HANDLER] GetUsers-4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	resps := []response.UserResponse{}
+	for _, user := range results {
+		resps = append(resps, response.UserResponse{
+			ID:    user.ID,
+			Name:  user.Name,
+			Email: user.Email,
+			Role:  user.Role,
+		})
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success Get Users"
+	defaultSuccessResponse.Data = resps
+	defaultSuccessResponse.Pagination = paginationResp
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
 // UpdatePassword implements UserHandler.
 func (u *userHandler) UpdatePassword(c *fiber.Ctx) error {
 	claims := c.Locals("user").(*entity.JwtData)
@@ -98,7 +174,7 @@ func (u *userHandler) UpdatePassword(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
 	}
 
-	err = u.userService.UpdatePassword(c.Context(), int64(claims.UserID), req.NewPassword)
+	err = u.userService.UpdatePassword(c.UserContext(), int64(claims.UserID), req.NewPassword)
 	if err != nil {
 		code := "[HANDLER] UpdatePassword-4"
 		log.Errorw(code, err)
@@ -115,8 +191,9 @@ func (u *userHandler) UpdatePassword(c *fiber.Ctx) error {
 	return c.JSON(defaultSuccessResponse)
 }
 
-func NewUserHandler(userService service.UserService) UserHandler {
+func NewUserHandler(userService service.UserService, pagination pagination.PaginationInterface) UserHandler {
 	return &userHandler{
 		userService: userService,
+		pagination:  pagination,
 	}
 }
@@ -0,0 +1,501 @@
+package handler
+
+import (
+	"bwanews/internal/adapter/handler/request"
+	"bwanews/internal/adapter/handler/response"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"bwanews/lib/conv"
+	"bwanews/lib/pagination"
+	validatorLib "bwanews/lib/validator"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type WebhookHandler interface {
+	GetWebhooks(c *fiber.Ctx) error
+	GetWebhookByID(c *fiber.Ctx) error
+	CreateWebhook(c *fiber.Ctx) error
+	UpdateWebhook(c *fiber.Ctx) error
+	DeleteWebhook(c *fiber.Ctx) error
+
+	GetDeliveries(c *fiber.Ctx) error
+	Redeliver(c *fiber.Ctx) error
+}
+
+type webhookHandler struct {
+	webhookService service.WebhookService
+	pagination     pagination.PaginationInterface
+}
+
+// GetWebhooks implements WebhookHandler.
+func (wh *webhookHandler) GetWebhooks(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetWebhooks = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	page, limit, err := wh.pagination.ParsePage(c.Query("page"), c.Query("limit"), 20)
+	if err != nil {
+		code := "[HANDLER] GetWebhooks = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	results, totalData, err := wh.webhookService.GetWebhooks(c.UserContext(), entity.WebhookQuery{
+		Page:  page,
+		Limit: limit,
+	}, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] GetWebhooks = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(webhookErrorStatus(err)).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, wh.pagination, int(totalData), page, limit)
+	if err != nil {
+		code := "[HANDLER] GetWebhooks = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	respWebhooks := []response.WebhookResponse{}
+	for _, hook := range results {
+		respWebhooks = append(respWebhooks, toWebhookResponse(hook))
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = respWebhooks
+	defaultSuccessResponse.Pagination = paginationResp
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// GetWebhookByID implements WebhookHandler.
+func (wh *webhookHandler) GetWebhookByID(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetWebhookByID = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("webhookID"))
+	if err != nil {
+		code := "[HANDLER] GetWebhookByID = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := wh.webhookService.GetWebhookByID(c.UserContext(), id, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] GetWebhookByID = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(webhookErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = toWebhookResponse(*result)
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// CreateWebhook implements WebhookHandler.
+// The response is the only one carrying the secret.
+func (wh *webhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] CreateWebhook = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	var req request.WebhookRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] CreateWebhook = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] CreateWebhook = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := wh.webhookService.CreateWebhook(c.UserContext(), toWebhookEntity(req, 0), int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] CreateWebhook = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(webhookErrorStatus(err)).JSON(errorResp)
+	}
+
+	respWebhook := toWebhookResponse(*result)
+	respWebhook.Secret = result.Secret
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Webhook created"
+	defaultSuccessResponse.Data = respWebhook
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.Status(fiber.StatusCreated).JSON(defaultSuccessResponse)
+}
+
+// UpdateWebhook implements WebhookHandler.
+func (wh *webhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] UpdateWebhook = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("webhookID"))
+	if err != nil {
+		code := "[HANDLER] UpdateWebhook = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	var req request.WebhookRequest
+	if err = c.BodyParser(&req); err != nil {
+		code := "[HANDLER] UpdateWebhook = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Invalid request body"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	if err = validatorLib.ValidateStruct(req); err != nil {
+		code := "[HANDLER] UpdateWebhook = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = wh.webhookService.UpdateWebhook(c.UserContext(), toWebhookEntity(req, id), int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] UpdateWebhook = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(webhookErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Webhook updated"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// DeleteWebhook implements WebhookHandler.
+func (wh *webhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] DeleteWebhook = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("webhookID"))
+	if err != nil {
+		code := "[HANDLER] DeleteWebhook = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	err = wh.webhookService.DeleteWebhook(c.UserContext(), id, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] DeleteWebhook = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(webhookErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Webhook deleted"
+	defaultSuccessResponse.Data = nil
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// GetDeliveries implements WebhookHandler.
+// ?status= narrows the log down to pending, succeeded or failed deliveries.
+func (wh *webhookHandler) GetDeliveries(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] GetDeliveries = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	id, err := conv.StringToInt64(c.Params("webhookID"))
+	if err != nil {
+		code := "[HANDLER] GetDeliveries = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", entity.DeliveryPending, entity.DeliverySucceeded, entity.DeliveryFailed:
+	default:
+		code := "[HANDLER] GetDeliveries = 3"
+		log.Errorw(code, "invalid status "+status)
+		errorResp.Status = false
+		errorResp.Message = "status must be pending, succeeded or failed"
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	page, limit, err := wh.pagination.ParsePage(c.Query("page"), c.Query("limit"), 20)
+	if err != nil {
+		code := "[HANDLER] GetDeliveries = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	results, totalData, err := wh.webhookService.GetDeliveries(c.UserContext(), entity.WebhookDeliveryQuery{
+		WebhookID: id,
+		Status:    status,
+		Page:      page,
+		Limit:     limit,
+	}, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] GetDeliveries = 5"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(webhookErrorStatus(err)).JSON(errorResp)
+	}
+
+	paginationResp, err := paginationResponse(c, wh.pagination, int(totalData), page, limit)
+	if err != nil {
+		code := "[HANDLER] GetDeliveries = 6"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	respDeliveries := []response.WebhookDeliveryResponse{}
+	for _, delivery := range results {
+		respDeliveries = append(respDeliveries, toWebhookDeliveryResponse(delivery))
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Success"
+	defaultSuccessResponse.Data = respDeliveries
+	defaultSuccessResponse.Pagination = paginationResp
+	defaultSuccessResponse.Cursor = nil
+
+	return c.JSON(defaultSuccessResponse)
+}
+
+// Redeliver implements WebhookHandler.
+// It answers with the new delivery, which is sent in the background.
+func (wh *webhookHandler) Redeliver(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*entity.JwtData)
+	if claims.UserID == 0 {
+		code := "[HANDLER] Redeliver = 1"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = "Unauthorized"
+
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResp)
+	}
+
+	webhookID, err := conv.StringToInt64(c.Params("webhookID"))
+	if err != nil {
+		code := "[HANDLER] Redeliver = 2"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	deliveryID, err := conv.StringToInt64(c.Params("deliveryID"))
+	if err != nil {
+		code := "[HANDLER] Redeliver = 3"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(fiber.StatusBadRequest).JSON(errorResp)
+	}
+
+	result, err := wh.webhookService.Redeliver(c.UserContext(), webhookID, deliveryID, int64(claims.UserID))
+	if err != nil {
+		code := "[HANDLER] Redeliver = 4"
+		log.Errorw(code, err)
+		errorResp.Status = false
+		errorResp.Message = err.Error()
+
+		return c.Status(webhookErrorStatus(err)).JSON(errorResp)
+	}
+
+	defaultSuccessResponse.Meta.Status = true
+	defaultSuccessResponse.Meta.Message = "Redelivery queued"
+	defaultSuccessResponse.Data = toWebhookDeliveryResponse(*result)
+	defaultSuccessResponse.Pagination = nil
+	defaultSuccessResponse.Cursor = nil
+
+	return c.Status(fiber.StatusAccepted).JSON(defaultSuccessResponse)
+}
+
+// webhookErrorStatus maps webhook service errors onto HTTP statuses.
+func webhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, entity.ErrForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, entity.ErrInvalidWebhookURL), errors.Is(err, entity.ErrUnknownEvent):
+		return fiber.StatusUnprocessableEntity
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func toWebhookEntity(req request.WebhookRequest, id int64) entity.WebhookEntity {
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	return entity.WebhookEntity{
+		ID:     id,
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+		Active: active,
+	}
+}
+
+func toWebhookResponse(hook entity.WebhookEntity) response.WebhookResponse {
+	resp := response.WebhookResponse{
+		ID:        hook.ID,
+		URL:       hook.URL,
+		Events:    hook.Events,
+		Active:    hook.Active,
+		CreatedAt: hook.CreatedAt.Format(time.RFC3339),
+	}
+
+	if hook.UpdatedAt != nil {
+		resp.UpdatedAt = hook.UpdatedAt.Format(time.RFC3339)
+	}
+
+	return resp
+}
+
+func toWebhookDeliveryResponse(delivery entity.WebhookDeliveryEntity) response.WebhookDeliveryResponse {
+	resp := response.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Event:          delivery.Event,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		RedeliveryOfID: delivery.RedeliveryOfID,
+		CreatedAt:      delivery.CreatedAt.Format(time.RFC3339),
+	}
+
+	if delivery.Status == entity.DeliveryPending && delivery.NextAttemptAt != nil {
+		resp.NextAttemptAt = delivery.NextAttemptAt.Format(time.RFC3339)
+	}
+
+	if delivery.DeliveredAt != nil {
+		resp.DeliveredAt = delivery.DeliveredAt.Format(time.RFC3339)
+	}
+
+	return resp
+}
+
+func NewWebhookHandler(webhookService service.WebhookService, pagination pagination.PaginationInterface) WebhookHandler {
+	return &webhookHandler{
+		webhookService: webhookService,
+		pagination:     pagination,
+	}
+}
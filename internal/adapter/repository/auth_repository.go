@@ -24,7 +24,7 @@ type authRepository struct {
 func (a *authRepository) GetUserByEmail(ctx context.Context, req entity.LoginRequest) (*entity.UserEntity, error) {
 	var modelUser model.User
 
-	err = a.db.Where("email = ?", req.Email).First(&modelUser).Error
+	err = conn(ctx, a.db).Where("email = ?", req.Email).First(&modelUser).Error
 	if err != nil {
 		code = "[REPOSITORY] GetUserByEmail = 1"
 		log.Errorw(code, err)
@@ -4,19 +4,23 @@ import (
 	"bwanews/internal/core/domain/entity"
 	"bwanews/internal/core/domain/model"
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CategoryRepository interface {
-	GetCategories(ctx context.Context) ([]entity.CategoryEntity, error)
+	GetCategories(ctx context.Context, query entity.QueryString) ([]entity.CategoryEntity, int64, error)
 	GetCategoryByID(ctx context.Context, id int64) (*entity.CategoryEntity, error)
-	CreateCategory(ctx context.Context, req entity.CategoryEntity) error
+	GetCategoryBySlug(ctx context.Context, slug string) (*entity.CategoryEntity, error)
+	ExportCategories(ctx context.Context, fn func([]entity.CategoryEntity) error) error
+	ImportCategory(ctx context.Context, req entity.CategoryEntity) error
+	CreateCategory(ctx context.Context, req entity.CategoryEntity) (int64, error)
 	EditCategoryByID(ctx context.Context, req entity.CategoryEntity) error
 	DeleteCategory(ctx context.Context, id int64) error
+	LockCategoryByID(ctx context.Context, id int64) error
 }
 
 type categoryRepository struct {
@@ -24,13 +28,13 @@ type categoryRepository struct {
 }
 
 // CreateCategory implements CategoryRepository.
-func (c *categoryRepository) CreateCategory(ctx context.Context, req entity.CategoryEntity) error {
+func (c *categoryRepository) CreateCategory(ctx context.Context, req entity.CategoryEntity) (int64, error) {
 	var countSlug int64
-	err = c.db.Table("categories").Where("slug = ?", req.Slug).Count(&countSlug).Error
+	err = conn(ctx, c.db).Table("categories").Where("slug = ?", req.Slug).Count(&countSlug).Error
 	if err != nil {
 		code = "[REPOSITORY] CreateCategory = 1"
 		log.Errorw(code, err)
-		return err
+		return 0, err
 	}
 
 	countSlug = countSlug + 1
@@ -42,33 +46,36 @@ func (c *categoryRepository) CreateCategory(ctx context.Context, req entity.Cate
 		CreatedByID: req.User.ID,
 	}
 
-	err = c.db.Create(&modelCategory).Error
+	err = conn(ctx, c.db).Create(&modelCategory).Error
 	if err != nil {
 		code = "[REPOSITORY] CreateCategory = 2"
 		log.Errorw(code, err)
-		return err
+		return 0, err
 	}
 
-	return nil
+	return modelCategory.ID, nil
 }
 
 // DeleteCategory implements CategoryRepository.
 func (c *categoryRepository) DeleteCategory(ctx context.Context, id int64) error {
-	var count int64
-	err = c.db.Table("contents").Where("category_id = ?", id).Count(&count).Error
+	err = conn(ctx, c.db).Where("id = ?", id).Delete(&model.Category{}).Error
 	if err != nil {
 		code = "[REPOSITORY] DeleteCategory = 1"
 		log.Errorw(code, err)
 		return err
 	}
 
-	if count > 0 {
-		return errors.New("cannot delete a category that has associated contents")
-	}
+	return nil
+}
 
-	err = c.db.Where("id = ?", id).Delete(&model.Category{}).Error
+// LockCategoryByID implements CategoryRepository.
+// The row lock only lasts inside a UnitOfWork; it blocks contents being
+// inserted into the category until the transaction ends.
+func (c *categoryRepository) LockCategoryByID(ctx context.Context, id int64) error {
+	var modelCategory model.Category
+	err = conn(ctx, c.db).Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", id).First(&modelCategory).Error
 	if err != nil {
-		code = "[REPOSITORY] DeleteCategory = 2"
+		code = "[REPOSITORY] LockCategoryByID = 1"
 		log.Errorw(code, err)
 		return err
 	}
@@ -79,7 +86,7 @@ func (c *categoryRepository) DeleteCategory(ctx context.Context, id int64) error
 // EditCategoryByID implements CategoryRepository.
 func (c *categoryRepository) EditCategoryByID(ctx context.Context, req entity.CategoryEntity) error {
 	var countSlug int64
-	err = c.db.Table("categories").Where("slug = ?", req.Slug).Count(&countSlug).Error
+	err = conn(ctx, c.db).Table("categories").Where("slug = ?", req.Slug).Count(&countSlug).Error
 	if err != nil {
 		code = "[REPOSITORY] EditCategoryByID = 1"
 		log.Errorw(code, err)
@@ -98,7 +105,7 @@ func (c *categoryRepository) EditCategoryByID(ctx context.Context, req entity.Ca
 		CreatedByID: req.User.ID,
 	}
 
-	err = c.db.Where("id = ?", req.ID).Updates(&modelCategory).Error
+	err = conn(ctx, c.db).Where("id = ?", req.ID).Updates(&modelCategory).Error
 	if err != nil {
 		code = "[REPOSITORY] EditCategoryByID = 2"
 		log.Errorw(code, err)
@@ -109,29 +116,48 @@ func (c *categoryRepository) EditCategoryByID(ctx context.Context, req entity.Ca
 }
 
 // GetCategories implements CategoryRepository.
-func (c *categoryRepository) GetCategories(ctx context.Context) ([]entity.CategoryEntity, error) {
+// A zero query.Limit returns every category, which the FE menu relies on.
+// No match, or a page past the end, is an empty list rather than an error.
+func (c *categoryRepository) GetCategories(ctx context.Context, query entity.QueryString) ([]entity.CategoryEntity, int64, error) {
 	var modelCategories []model.Category
+	var countData int64
 
-	err = c.db.Order("created_at desc").Preload("User").Find(&modelCategories).Error
+	sqlMain := conn(ctx, c.db).Model(&model.Category{})
+	if query.Search != "" {
+		sqlMain = sqlMain.Where("title ilike ?", "%"+query.Search+"%")
+	}
+
+	err = sqlMain.Count(&countData).Error
 	if err != nil {
 		code = "[REPOSITORY] GetCategories = 1"
 		log.Errorw(code, err)
-		return nil, err
+		return nil, 0, err
 	}
 
-	if len(modelCategories) == 0 {
+	sqlMain = sqlMain.Order("created_at desc").Preload("User")
+	if query.Limit > 0 {
+		if query.Page <= 0 {
+			query.Page = 1
+		}
+
+		sqlMain = sqlMain.Limit(query.Limit).Offset((query.Page - 1) * query.Limit)
+	}
+
+	err = sqlMain.Find(&modelCategories).Error
+	if err != nil {
 		code = "[REPOSITORY] GetCategories = 2"
-		err = errors.New("no categories found")
 		log.Errorw(code, err)
-		return nil, err
+		return nil, 0, err
 	}
 
-	var resp []entity.CategoryEntity
+	resp := []entity.CategoryEntity{}
 	for _, category := range modelCategories {
 		resp = append(resp, entity.CategoryEntity{
-			ID:    category.ID,
-			Title: category.Title,
-			Slug:  category.Slug,
+			ID:        category.ID,
+			Title:     category.Title,
+			Slug:      category.Slug,
+			CreatedAt: category.CreatedAt,
+			UpdatedAt: updatedAt(category.CreatedAt, category.UpdatedAt),
 			User: entity.UserEntity{
 				ID:       category.User.ID,
 				Name:     category.User.Name,
@@ -142,14 +168,93 @@ func (c *categoryRepository) GetCategories(ctx context.Context) ([]entity.Catego
 
 	}
 
-	return resp, nil
+	return resp, countData, nil
+
+}
+
+// GetCategoryBySlug implements CategoryRepository.
+// It returns nil without an error when no category has the slug.
+func (c *categoryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*entity.CategoryEntity, error) {
+	var modelCategories []model.Category
+	err = conn(ctx, c.db).Where("slug = ?", slug).Limit(1).Find(&modelCategories).Error
+	if err != nil {
+		code = "[REPOSITORY] GetCategoryBySlug = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if len(modelCategories) == 0 {
+		return nil, nil
+	}
 
+	return &entity.CategoryEntity{
+		ID:    modelCategories[0].ID,
+		Title: modelCategories[0].Title,
+		Slug:  modelCategories[0].Slug,
+	}, nil
+}
+
+// ExportCategories implements CategoryRepository.
+func (c *categoryRepository) ExportCategories(ctx context.Context, fn func([]entity.CategoryEntity) error) error {
+	var modelCategories []model.Category
+
+	err = conn(ctx, c.db).Preload("User").
+		FindInBatches(&modelCategories, 500, func(tx *gorm.DB, batch int) error {
+			resps := make([]entity.CategoryEntity, 0, len(modelCategories))
+			for _, category := range modelCategories {
+				resps = append(resps, entity.CategoryEntity{
+					ID:    category.ID,
+					Title: category.Title,
+					Slug:  category.Slug,
+					User: entity.UserEntity{
+						ID:    category.User.ID,
+						Name:  category.User.Name,
+						Email: category.User.Email,
+					},
+					CreatedAt: category.CreatedAt,
+					UpdatedAt: updatedAt(category.CreatedAt, category.UpdatedAt),
+				})
+			}
+
+			return fn(resps)
+		}).Error
+	if err != nil {
+		code = "[REPOSITORY] ExportCategories = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// ImportCategory implements CategoryRepository.
+// Unlike CreateCategory it keeps req.Slug as is and upserts on it.
+func (c *categoryRepository) ImportCategory(ctx context.Context, req entity.CategoryEntity) error {
+	modelCategory := model.Category{
+		Title:       req.Title,
+		Slug:        req.Slug,
+		CreatedByID: req.User.ID,
+		CreatedAt:   req.CreatedAt,
+		UpdatedAt:   &req.UpdatedAt,
+	}
+
+	err = conn(ctx, c.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "created_by_id", "created_at", "updated_at"}),
+	}).Create(&modelCategory).Error
+	if err != nil {
+		code = "[REPOSITORY] ImportCategory = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
 }
 
 // GetCategoryByID implements CategoryRepository.
 func (c *categoryRepository) GetCategoryByID(ctx context.Context, id int64) (*entity.CategoryEntity, error) {
 	var modelCategory model.Category
-	err = c.db.Where("id = ?", id).Preload("User").First(&modelCategory).Error
+	err = conn(ctx, c.db).Where("id = ?", id).Preload("User").First(&modelCategory).Error
 	if err != nil {
 		code = "[REPOSITORY] GetCategoryByID = 1"
 		log.Errorw(code, err)
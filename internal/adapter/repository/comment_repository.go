@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/domain/model"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	GetComments(ctx context.Context, query entity.CommentQuery) ([]entity.CommentEntity, int64, error)
+	GetRootComments(ctx context.Context, query entity.CommentQuery) ([]entity.CommentEntity, int64, error)
+	GetReplies(ctx context.Context, rootIDs []int64, status string) ([]entity.CommentEntity, error)
+	GetCommentByID(ctx context.Context, id int64) (*entity.CommentEntity, error)
+	CountRecentComments(ctx context.Context, query entity.CommentCountQuery) (int64, error)
+	CreateComment(ctx context.Context, req entity.CommentEntity) (int64, error)
+	UpdateCommentStatus(ctx context.Context, id int64, status string, moderatorID int64) error
+	DeleteComment(ctx context.Context, id int64) error
+	GetCommentSettings(ctx context.Context, contentID int64) (*entity.CommentSettingsEntity, string, error)
+	UpdateCommentSettings(ctx context.Context, contentID int64, settings entity.CommentSettingsEntity) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+// GetComments implements CommentRepository.
+// The pending queue is served oldest first so moderators work through it
+// in order; every other listing is newest first.
+func (c *commentRepository) GetComments(ctx context.Context, query entity.CommentQuery) ([]entity.CommentEntity, int64, error) {
+	var modelComments []model.Comment
+	var countData int64
+
+	sqlMain := conn(ctx, c.db).Model(&model.Comment{})
+	if query.ContentID > 0 {
+		sqlMain = sqlMain.Where("content_id = ?", query.ContentID)
+	}
+
+	if query.Status != "" {
+		sqlMain = sqlMain.Where("status = ?", query.Status)
+	}
+
+	err = sqlMain.Count(&countData).Error
+	if err != nil {
+		code = "[REPOSITORY] GetComments = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	order := "created_at DESC, id DESC"
+	if query.Status == entity.CommentStatusPending {
+		order = "created_at ASC, id ASC"
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	err = sqlMain.Preload("Content", contentTitleOnly).Order(order).Limit(query.Limit).Offset(offset).Find(&modelComments).Error
+	if err != nil {
+		code = "[REPOSITORY] GetComments = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	resps := []entity.CommentEntity{}
+	for _, val := range modelComments {
+		resps = append(resps, toCommentEntity(val))
+	}
+
+	return resps, countData, nil
+}
+
+// GetRootComments implements CommentRepository.
+// It pages through the top-level comments of a content, oldest first.
+func (c *commentRepository) GetRootComments(ctx context.Context, query entity.CommentQuery) ([]entity.CommentEntity, int64, error) {
+	var modelComments []model.Comment
+	var countData int64
+
+	sqlMain := conn(ctx, c.db).Model(&model.Comment{}).
+		Where("content_id = ? AND status = ? AND root_id IS NULL", query.ContentID, query.Status)
+
+	err = sqlMain.Count(&countData).Error
+	if err != nil {
+		code = "[REPOSITORY] GetRootComments = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	err = sqlMain.Order("created_at ASC, id ASC").Limit(query.Limit).Offset(offset).Find(&modelComments).Error
+	if err != nil {
+		code = "[REPOSITORY] GetRootComments = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	resps := []entity.CommentEntity{}
+	for _, val := range modelComments {
+		resps = append(resps, toCommentEntity(val))
+	}
+
+	return resps, countData, nil
+}
+
+// GetReplies implements CommentRepository.
+// It loads every reply of the given threads in a single query.
+func (c *commentRepository) GetReplies(ctx context.Context, rootIDs []int64, status string) ([]entity.CommentEntity, error) {
+	if len(rootIDs) == 0 {
+		return nil, nil
+	}
+
+	var modelComments []model.Comment
+	err = conn(ctx, c.db).Where("root_id IN ? AND status = ?", rootIDs, status).
+		Order("created_at ASC, id ASC").Find(&modelComments).Error
+	if err != nil {
+		code = "[REPOSITORY] GetReplies = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := []entity.CommentEntity{}
+	for _, val := range modelComments {
+		resps = append(resps, toCommentEntity(val))
+	}
+
+	return resps, nil
+}
+
+// GetCommentByID implements CommentRepository.
+func (c *commentRepository) GetCommentByID(ctx context.Context, id int64) (*entity.CommentEntity, error) {
+	var modelComment model.Comment
+
+	err = conn(ctx, c.db).Where("id = ?", id).Preload("Content", contentTitleOnly).First(&modelComment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		code = "[REPOSITORY] GetCommentByID = 1"
+		log.Errorw(code, err)
+		return nil, entity.ErrNotFound
+	}
+
+	if err != nil {
+		code = "[REPOSITORY] GetCommentByID = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resp := toCommentEntity(modelComment)
+
+	return &resp, nil
+}
+
+// CountRecentComments implements CommentRepository.
+func (c *commentRepository) CountRecentComments(ctx context.Context, query entity.CommentCountQuery) (int64, error) {
+	var countData int64
+
+	sqlMain := conn(ctx, c.db).Model(&model.Comment{}).Where("created_at >= ?", query.Since)
+	if query.Body != "" {
+		sqlMain = sqlMain.Where("body = ?", query.Body)
+	}
+
+	if query.IPAddress != "" {
+		sqlMain = sqlMain.Where("ip_address = ?", query.IPAddress)
+	}
+
+	if query.ReaderID != nil {
+		sqlMain = sqlMain.Where("reader_id = ?", *query.ReaderID)
+	}
+
+	err = sqlMain.Count(&countData).Error
+	if err != nil {
+		code = "[REPOSITORY] CountRecentComments = 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return countData, nil
+}
+
+// CreateComment implements CommentRepository.
+func (c *commentRepository) CreateComment(ctx context.Context, req entity.CommentEntity) (int64, error) {
+	modelComment := model.Comment{
+		ContentID:   req.ContentID,
+		ParentID:    req.ParentID,
+		RootID:      req.RootID,
+		ReaderID:    req.ReaderID,
+		AuthorName:  req.AuthorName,
+		AuthorEmail: req.AuthorEmail,
+		Body:        req.Body,
+		Status:      req.Status,
+		IPAddress:   req.IPAddress,
+		UserAgent:   req.UserAgent,
+		CreatedAt:   req.CreatedAt,
+		SpamScore:   req.SpamScore,
+		SpamReasons: strings.Join(req.SpamReasons, "\n"),
+	}
+
+	err = conn(ctx, c.db).Create(&modelComment).Error
+	if err != nil {
+		code = "[REPOSITORY] CreateComment = 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return modelComment.ID, nil
+}
+
+// UpdateCommentStatus implements CommentRepository.
+func (c *commentRepository) UpdateCommentStatus(ctx context.Context, id int64, status string, moderatorID int64) error {
+	now := time.Now()
+	result := conn(ctx, c.db).Model(&model.Comment{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          status,
+		"moderated_by_id": moderatorID,
+		"moderated_at":    now,
+		"updated_at":      now,
+	})
+	if result.Error != nil {
+		code = "[REPOSITORY] UpdateCommentStatus = 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		code = "[REPOSITORY] UpdateCommentStatus = 2"
+		log.Errorw(code, entity.ErrNotFound)
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteComment implements CommentRepository.
+// Replies are removed with their parent by the foreign key.
+func (c *commentRepository) DeleteComment(ctx context.Context, id int64) error {
+	result := conn(ctx, c.db).Where("id = ?", id).Delete(&model.Comment{})
+	if result.Error != nil {
+		code = "[REPOSITORY] DeleteComment = 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		code = "[REPOSITORY] DeleteComment = 2"
+		log.Errorw(code, entity.ErrNotFound)
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetCommentSettings implements CommentRepository.
+// It also returns the status of the content, and nil settings without an
+// error when the content does not exist.
+func (c *commentRepository) GetCommentSettings(ctx context.Context, contentID int64) (*entity.CommentSettingsEntity, string, error) {
+	var modelContents []model.Content
+
+	err = conn(ctx, c.db).Select("id", "status", "comments_enabled", "comments_closed").
+		Where("id = ?", contentID).Limit(1).Find(&modelContents).Error
+	if err != nil {
+		code = "[REPOSITORY] GetCommentSettings = 1"
+		log.Errorw(code, err)
+		return nil, "", err
+	}
+
+	if len(modelContents) == 0 {
+		return nil, "", nil
+	}
+
+	return &entity.CommentSettingsEntity{
+		Enabled: modelContents[0].CommentsEnabled,
+		Closed:  modelContents[0].CommentsClosed,
+	}, modelContents[0].Status, nil
+}
+
+// UpdateCommentSettings implements CommentRepository.
+func (c *commentRepository) UpdateCommentSettings(ctx context.Context, contentID int64, settings entity.CommentSettingsEntity) error {
+	result := conn(ctx, c.db).Model(&model.Content{}).Where("id = ?", contentID).UpdateColumns(map[string]interface{}{
+		"comments_enabled": settings.Enabled,
+		"comments_closed":  settings.Closed,
+	})
+	if result.Error != nil {
+		code = "[REPOSITORY] UpdateCommentSettings = 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		code = "[REPOSITORY] UpdateCommentSettings = 2"
+		log.Errorw(code, entity.ErrNotFound)
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// contentTitleOnly keeps the preloaded content of a comment down to what
+// the moderation queue shows.
+func contentTitleOnly(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "title")
+}
+
+func toCommentEntity(comment model.Comment) entity.CommentEntity {
+	return entity.CommentEntity{
+		ID:            comment.ID,
+		ContentID:     comment.ContentID,
+		ContentTitle:  comment.Content.Title,
+		ParentID:      comment.ParentID,
+		RootID:        comment.RootID,
+		ReaderID:      comment.ReaderID,
+		AuthorName:    comment.AuthorName,
+		AuthorEmail:   comment.AuthorEmail,
+		Body:          comment.Body,
+		Status:        comment.Status,
+		IPAddress:     comment.IPAddress,
+		UserAgent:     comment.UserAgent,
+		ModeratedByID: comment.ModeratedByID,
+		ModeratedAt:   comment.ModeratedAt,
+		CreatedAt:     comment.CreatedAt,
+		SpamScore:     comment.SpamScore,
+		SpamReasons:   spamReasons(comment.SpamReasons),
+	}
+}
+
+func spamReasons(column string) []string {
+	if column == "" {
+		return nil
+	}
+
+	return strings.Split(column, "\n")
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/domain/model"
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type ContentLockRepository interface {
+	GetActiveLock(ctx context.Context, contentID int64) (*entity.ContentLockEntity, error)
+	GetActiveLockForWrite(ctx context.Context, contentID int64) (*entity.ContentLockEntity, error)
+	AcquireLock(ctx context.Context, contentID, userID int64, ttlSeconds int) (bool, error)
+	ForceLock(ctx context.Context, contentID, userID int64, ttlSeconds int) error
+	HeartbeatLock(ctx context.Context, contentID, userID int64, ttlSeconds int) error
+	ReleaseLock(ctx context.Context, contentID, userID int64) error
+}
+
+type contentLockRepository struct {
+	db *gorm.DB
+}
+
+// AcquireLock implements ContentLockRepository.
+// The lock is taken when it is free, expired or already held by userID; it
+// reports false when another user holds a live lock. Times come from the
+// database clock so every API instance agrees on expiry. It waits for
+// writes holding the content row, see GetActiveLockForWrite.
+func (c *contentLockRepository) AcquireLock(ctx context.Context, contentID int64, userID int64, ttlSeconds int) (bool, error) {
+	var result *gorm.DB
+	err = conn(ctx, c.db).Transaction(func(tx *gorm.DB) error {
+		if err := shareContentRow(tx, contentID); err != nil {
+			return err
+		}
+
+		result = tx.Exec(`
+			INSERT INTO content_locks (content_id, user_id, acquired_at, heartbeat_at, expires_at)
+			VALUES (?, ?, NOW(), NOW(), NOW() + make_interval(secs => ?))
+			ON CONFLICT (content_id) DO UPDATE SET
+				user_id = EXCLUDED.user_id,
+				acquired_at = CASE WHEN content_locks.user_id = EXCLUDED.user_id AND content_locks.expires_at > NOW()
+					THEN content_locks.acquired_at ELSE EXCLUDED.acquired_at END,
+				heartbeat_at = EXCLUDED.heartbeat_at,
+				expires_at = EXCLUDED.expires_at
+			WHERE content_locks.user_id = EXCLUDED.user_id OR content_locks.expires_at <= NOW()`,
+			contentID, userID, ttlSeconds)
+		return result.Error
+	})
+	if err != nil {
+		code = "[REPOSITORY] AcquireLock = 1"
+		log.Errorw(code, err)
+		return false, err
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// ForceLock implements ContentLockRepository.
+// Like AcquireLock, it waits for writes holding the content row.
+func (c *contentLockRepository) ForceLock(ctx context.Context, contentID int64, userID int64, ttlSeconds int) error {
+	err = conn(ctx, c.db).Transaction(func(tx *gorm.DB) error {
+		if err := shareContentRow(tx, contentID); err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			INSERT INTO content_locks (content_id, user_id, acquired_at, heartbeat_at, expires_at)
+			VALUES (?, ?, NOW(), NOW(), NOW() + make_interval(secs => ?))
+			ON CONFLICT (content_id) DO UPDATE SET
+				user_id = EXCLUDED.user_id,
+				acquired_at = EXCLUDED.acquired_at,
+				heartbeat_at = EXCLUDED.heartbeat_at,
+				expires_at = EXCLUDED.expires_at`,
+			contentID, userID, ttlSeconds).Error
+	})
+	if err != nil {
+		code = "[REPOSITORY] ForceLock = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetActiveLock implements ContentLockRepository.
+// It returns nil without error when the content is not locked.
+func (c *contentLockRepository) GetActiveLock(ctx context.Context, contentID int64) (*entity.ContentLockEntity, error) {
+	var modelLock model.ContentLock
+
+	err = conn(ctx, c.db).Where("content_id = ? AND expires_at > NOW()", contentID).Preload("User").First(&modelLock).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		code = "[REPOSITORY] GetActiveLock = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return &entity.ContentLockEntity{
+		ContentID:   modelLock.ContentID,
+		UserID:      modelLock.UserID,
+		UserName:    modelLock.User.Name,
+		AcquiredAt:  modelLock.AcquiredAt,
+		HeartbeatAt: modelLock.HeartbeatAt,
+		ExpiresAt:   modelLock.ExpiresAt,
+	}, nil
+}
+
+// GetActiveLockForWrite implements ContentLockRepository.
+// It is GetActiveLock for a unit of work about to write the content: the
+// content row is locked first, so AcquireLock and ForceLock wait until the
+// write commits and the lock checked here cannot change hands meanwhile.
+func (c *contentLockRepository) GetActiveLockForWrite(ctx context.Context, contentID int64) (*entity.ContentLockEntity, error) {
+	err = conn(ctx, c.db).Exec("SELECT 1 FROM contents WHERE id = ? FOR UPDATE", contentID).Error
+	if err != nil {
+		code = "[REPOSITORY] GetActiveLockForWrite = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return c.GetActiveLock(ctx, contentID)
+}
+
+// HeartbeatLock implements ContentLockRepository.
+func (c *contentLockRepository) HeartbeatLock(ctx context.Context, contentID int64, userID int64, ttlSeconds int) error {
+	result := conn(ctx, c.db).Exec(`
+		UPDATE content_locks SET heartbeat_at = NOW(), expires_at = NOW() + make_interval(secs => ?)
+		WHERE content_id = ? AND user_id = ? AND expires_at > NOW()`,
+		ttlSeconds, contentID, userID)
+	if result.Error != nil {
+		code = "[REPOSITORY] HeartbeatLock = 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		code = "[REPOSITORY] HeartbeatLock = 2"
+		log.Errorw(code, entity.ErrLockNotHeld)
+		return entity.ErrLockNotHeld
+	}
+
+	return nil
+}
+
+// ReleaseLock implements ContentLockRepository.
+func (c *contentLockRepository) ReleaseLock(ctx context.Context, contentID int64, userID int64) error {
+	err = conn(ctx, c.db).Where("content_id = ? AND user_id = ?", contentID, userID).Delete(&model.ContentLock{}).Error
+	if err != nil {
+		code = "[REPOSITORY] ReleaseLock = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// shareContentRow locks the content row against writers for the rest of
+// the transaction of tx.
+func shareContentRow(tx *gorm.DB, contentID int64) error {
+	return tx.Exec("SELECT 1 FROM contents WHERE id = ? FOR SHARE", contentID).Error
+}
+
+func NewContentLockRepository(db *gorm.DB) ContentLockRepository {
+	return &contentLockRepository{db: db}
+}
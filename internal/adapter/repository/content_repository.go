@@ -3,10 +3,12 @@ package repository
 import (
 	"bwanews/internal/core/domain/entity"
 	"bwanews/internal/core/domain/model"
+	"bwanews/lib/related"
 	"context"
+	"encoding/json"
 	"fmt"
-	"math"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2/log"
 	"gorm.io/gorm"
@@ -14,10 +16,21 @@ import (
 )
 
 type ContentRepository interface {
-	GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, int64, error)
+	GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, error)
+	GetContentsByCursor(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, *entity.Cursor, error)
+	GetTags(ctx context.Context, query entity.QueryString) ([]entity.TagEntity, int64, error)
+	GetSitemapContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, error)
+	GetContentIDs(ctx context.Context, query entity.QueryString, limit int) ([]int64, error)
+	GetContentsByIDs(ctx context.Context, ids []int64) ([]entity.ContentEntity, error)
+	GetRelatedCandidates(ctx context.Context, content entity.ContentEntity, limit int) ([]entity.ContentEntity, error)
+	CountContentsByCategoryID(ctx context.Context, categoryID int64) (int64, error)
 	GetContentByID(ctx context.Context, id int64) (*entity.ContentEntity, error)
-	CreateContent(ctx context.Context, req entity.ContentEntity) error
+	GetContentBySlug(ctx context.Context, slug string) (*entity.ContentEntity, error)
+	ExportContents(ctx context.Context, batchSize int, fn func([]entity.ContentEntity) error) error
+	ImportContent(ctx context.Context, req entity.ContentEntity) error
+	CreateContent(ctx context.Context, req entity.ContentEntity) (int64, error)
 	EditContentByID(ctx context.Context, req entity.ContentEntity) error
+	PatchContentByID(ctx context.Context, patch entity.ContentPatch) error
 	DeleteContent(ctx context.Context, id int64) error
 }
 
@@ -26,32 +39,64 @@ type contentRepository struct {
 }
 
 // CreateContent implements ContentRepository.
-func (c *contentRepository) CreateContent(ctx context.Context, req entity.ContentEntity) error {
+// A slug taken in the meantime is entity.ErrSlugTaken.
+func (c *contentRepository) CreateContent(ctx context.Context, req entity.ContentEntity) (int64, error) {
 	tags := strings.Join(req.Tags, ",")
 	modelContent := model.Content{
 		Title:       req.Title,
+		Slug:        req.Slug,
 		Excerpt:     req.Excerpt,
 		Description: req.Description,
+		BodyFormat:  req.BodyFormat,
+		BodyHTML:    req.BodyHTML,
+		Blocks:      blocksColumn(req.Blocks),
 		Image:       req.Image,
 		Tags:        tags,
 		Status:      req.Status,
+		Type:        contentType(req.Type),
 		CategoryID:  req.CategoryID,
 		CreatedByID: req.CreatedByID,
+		Version:     1,
+
+		MetaTitle:       req.SEO.MetaTitle,
+		MetaDescription: req.SEO.MetaDescription,
+		CanonicalURL:    req.SEO.CanonicalURL,
+		SocialImage:     req.SEO.SocialImage,
+		NoIndex:         req.SEO.NoIndex,
 	}
 
-	err = c.db.Create(&modelContent).Error
-	if err != nil {
+	err = conn(ctx, c.db).Create(&modelContent).Error
+	if isUniqueViolation(err, "idx_contents_slug") {
 		code = "[REPOSITORY] CreateContent = 1"
 		log.Errorw(code, err)
-		return err
+		return 0, entity.ErrSlugTaken
 	}
 
-	return nil
+	if err != nil {
+		code = "[REPOSITORY] CreateContent = 2"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return modelContent.ID, nil
+}
+
+// CountContentsByCategoryID implements ContentRepository.
+func (c *contentRepository) CountContentsByCategoryID(ctx context.Context, categoryID int64) (int64, error) {
+	var count int64
+	err = conn(ctx, c.db).Model(&model.Content{}).Where("category_id = ?", categoryID).Count(&count).Error
+	if err != nil {
+		code = "[REPOSITORY] CountContentsByCategoryID = 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return count, nil
 }
 
 // DeleteContent implements ContentRepository.
 func (c *contentRepository) DeleteContent(ctx context.Context, id int64) error {
-	err = c.db.Where("id = ?", id).Delete(&model.Content{}).Error
+	err = conn(ctx, c.db).Where("id = ?", id).Delete(&model.Content{}).Error
 	if err != nil {
 		code = "[REPOSITORY] DeleteContent = 1"
 		log.Errorw(code, err)
@@ -62,22 +107,155 @@ func (c *contentRepository) DeleteContent(ctx context.Context, id int64) error {
 }
 
 // EditContentByID implements ContentRepository.
+// The update only applies while the row still has req.Version, and bumps
+// it, so a writer holding a stale copy gets entity.ErrVersionConflict.
 func (c *contentRepository) EditContentByID(ctx context.Context, req entity.ContentEntity) error {
 	tags := strings.Join(req.Tags, ",")
 	modelContent := model.Content{
 		Title:       req.Title,
 		Excerpt:     req.Excerpt,
 		Description: req.Description,
+		BodyFormat:  req.BodyFormat,
+		BodyHTML:    req.BodyHTML,
+		Blocks:      blocksColumn(req.Blocks),
 		Image:       req.Image,
 		Tags:        tags,
 		Status:      req.Status,
+		Type:        contentType(req.Type),
 		CategoryID:  req.CategoryID,
 		CreatedByID: req.CreatedByID,
+		Version:     req.Version + 1,
+
+		MetaTitle:       req.SEO.MetaTitle,
+		MetaDescription: req.SEO.MetaDescription,
+		CanonicalURL:    req.SEO.CanonicalURL,
+		SocialImage:     req.SEO.SocialImage,
+		NoIndex:         req.SEO.NoIndex,
 	}
 
-	err = c.db.Where("id = ?", req.ID).Updates(&modelContent).Error
-	if err != nil {
+	// The SEO columns are selected explicitly so a full update can clear
+	// them; struct updates skip zero values otherwise.
+	result := conn(ctx, c.db).
+		Where("id = ? AND version = ?", req.ID, req.Version).
+		Select("title", "excerpt", "description", "body_format", "body_html", "blocks", "image", "tags", "status", "content_type", "category_id", "created_by_id", "version",
+			"meta_title", "meta_description", "canonical_url", "social_image", "noindex").
+		Updates(&modelContent)
+	if result.Error != nil {
 		code = "[REPOSITORY] EditContentByID = 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		code = "[REPOSITORY] EditContentByID = 2"
+		log.Errorw(code, entity.ErrVersionConflict)
+		return entity.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// PatchContentByID implements ContentRepository.
+// Columns are written through a map so empty values are stored instead of
+// being skipped as they are for struct updates. A non-zero patch.Version
+// makes the update conditional, as in EditContentByID. A missing content
+// is entity.ErrNotFound.
+func (c *contentRepository) PatchContentByID(ctx context.Context, patch entity.ContentPatch) error {
+	updates := map[string]interface{}{
+		"version": gorm.Expr("version + 1"),
+	}
+
+	if patch.Title != nil {
+		updates["title"] = *patch.Title
+	}
+
+	if patch.Excerpt != nil {
+		updates["excerpt"] = *patch.Excerpt
+	}
+
+	if patch.Description != nil {
+		updates["description"] = *patch.Description
+	}
+
+	if patch.BodyFormat != nil {
+		updates["body_format"] = *patch.BodyFormat
+	}
+
+	if patch.BodyHTML != nil {
+		updates["body_html"] = *patch.BodyHTML
+	}
+
+	if patch.Blocks != nil {
+		updates["blocks"] = blocksColumn(*patch.Blocks)
+	}
+
+	if patch.Image != nil {
+		updates["image"] = *patch.Image
+	}
+
+	if patch.Tags != nil {
+		updates["tags"] = strings.Join(*patch.Tags, ",")
+	}
+
+	if patch.Status != nil {
+		updates["status"] = *patch.Status
+	}
+
+	if patch.Type != nil {
+		updates["content_type"] = contentType(*patch.Type)
+	}
+
+	if patch.CategoryID != nil {
+		updates["category_id"] = *patch.CategoryID
+	}
+
+	if patch.SEO.MetaTitle != nil {
+		updates["meta_title"] = *patch.SEO.MetaTitle
+	}
+
+	if patch.SEO.MetaDescription != nil {
+		updates["meta_description"] = *patch.SEO.MetaDescription
+	}
+
+	if patch.SEO.CanonicalURL != nil {
+		updates["canonical_url"] = *patch.SEO.CanonicalURL
+	}
+
+	if patch.SEO.SocialImage != nil {
+		updates["social_image"] = *patch.SEO.SocialImage
+	}
+
+	if patch.SEO.NoIndex != nil {
+		updates["noindex"] = *patch.SEO.NoIndex
+	}
+
+	sqlMain := conn(ctx, c.db).Model(&model.Content{}).Where("id = ?", patch.ID)
+	if patch.Version > 0 {
+		sqlMain = sqlMain.Where("version = ?", patch.Version)
+	}
+
+	result := sqlMain.Updates(updates)
+	if result.Error != nil {
+		code = "[REPOSITORY] PatchContentByID = 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		var count int64
+		err = conn(ctx, c.db).Model(&model.Content{}).Where("id = ?", patch.ID).Count(&count).Error
+		if err != nil {
+			code = "[REPOSITORY] PatchContentByID = 2"
+			log.Errorw(code, err)
+			return err
+		}
+
+		err = entity.ErrNotFound
+		if count > 0 {
+			err = entity.ErrVersionConflict
+		}
+
+		code = "[REPOSITORY] PatchContentByID = 3"
 		log.Errorw(code, err)
 		return err
 	}
@@ -89,41 +267,110 @@ func (c *contentRepository) EditContentByID(ctx context.Context, req entity.Cont
 func (c *contentRepository) GetContentByID(ctx context.Context, id int64) (*entity.ContentEntity, error) {
 	var modelContent model.Content
 
-	err = c.db.Where("id = ?", id).Preload(clause.Associations).First(&modelContent).Error
+	err = conn(ctx, c.db).Where("id = ?", id).Preload(clause.Associations).First(&modelContent).Error
 	if err != nil {
 		code = "[REPOSITORY] GetContentByID = 1"
 		log.Errorw(code, err)
 		return nil, err
 	}
 
-	tags := strings.Split(modelContent.Tags, ",")
-	resp := entity.ContentEntity{
-		ID:          modelContent.ID,
-		Title:       modelContent.Title,
-		Excerpt:     modelContent.Excerpt,
-		Description: modelContent.Description,
-		Image:       modelContent.Image,
-		Tags:        tags,
-		Status:      modelContent.Status,
-		CategoryID:  modelContent.CategoryID,
-		CreatedByID: modelContent.CreatedByID,
-		CreatedAt:   modelContent.CreatedAt,
-		Category: entity.CategoryEntity{
-			ID:    modelContent.Category.ID,
-			Title: modelContent.Category.Title,
-			Slug:  modelContent.Category.Slug,
-		},
-		User: entity.UserEntity{
-			ID:   modelContent.User.ID,
-			Name: modelContent.User.Name,
-		},
+	resp := toContentEntity(modelContent)
+
+	return &resp, nil
+}
+
+// GetContentBySlug implements ContentRepository.
+// It returns nil without an error when no content has the slug.
+func (c *contentRepository) GetContentBySlug(ctx context.Context, slug string) (*entity.ContentEntity, error) {
+	var modelContents []model.Content
+
+	err = conn(ctx, c.db).Where("slug = ?", slug).Preload(clause.Associations).Limit(1).Find(&modelContents).Error
+	if err != nil {
+		code = "[REPOSITORY] GetContentBySlug = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if len(modelContents) == 0 {
+		return nil, nil
 	}
 
+	resp := toContentEntity(modelContents[0])
+
 	return &resp, nil
 }
 
+// ExportContents implements ContentRepository.
+// Contents are read in primary key order, batchSize rows at a time, so an
+// export never holds the whole table in memory.
+func (c *contentRepository) ExportContents(ctx context.Context, batchSize int, fn func([]entity.ContentEntity) error) error {
+	var modelContents []model.Content
+
+	err = conn(ctx, c.db).Preload(clause.Associations).
+		FindInBatches(&modelContents, batchSize, func(tx *gorm.DB, batch int) error {
+			resps := make([]entity.ContentEntity, 0, len(modelContents))
+			for _, content := range modelContents {
+				resps = append(resps, toContentEntity(content))
+			}
+
+			return fn(resps)
+		}).Error
+	if err != nil {
+		code = "[REPOSITORY] ExportContents = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// ImportContent implements ContentRepository.
+// It upserts on the slug and keeps the timestamps from req; an update bumps
+// the version like any other edit.
+func (c *contentRepository) ImportContent(ctx context.Context, req entity.ContentEntity) error {
+	modelContent := model.Content{
+		Title:       req.Title,
+		Slug:        req.Slug,
+		Excerpt:     req.Excerpt,
+		Description: req.Description,
+		BodyFormat:  req.BodyFormat,
+		BodyHTML:    req.BodyHTML,
+		Blocks:      blocksColumn(req.Blocks),
+		Image:       req.Image,
+		Tags:        strings.Join(req.Tags, ","),
+		Status:      req.Status,
+		Type:        contentType(req.Type),
+		CategoryID:  req.CategoryID,
+		CreatedByID: req.CreatedByID,
+		CreatedAt:   req.CreatedAt,
+		UpdatedAt:   &req.UpdatedAt,
+		Version:     1,
+	}
+
+	updates := clause.AssignmentColumns([]string{
+		"title", "excerpt", "description", "body_format", "body_html", "blocks", "image", "tags", "status",
+		"content_type", "category_id", "created_by_id", "created_at", "updated_at",
+	})
+	updates = append(updates, clause.Assignment{
+		Column: clause.Column{Name: "version"},
+		Value:  gorm.Expr("contents.version + 1"),
+	})
+
+	err = conn(ctx, c.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}},
+		DoUpdates: updates,
+	}).Create(&modelContent).Error
+	if err != nil {
+		code = "[REPOSITORY] ImportContent = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
 // GetContents implements ContentRepository.
-func (c *contentRepository) GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, int64, error) {
+func (c *contentRepository) GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, error) {
 	var modelContents []model.Content
 	var countData int64
 
@@ -146,28 +393,15 @@ func (c *contentRepository) GetContents(ctx context.Context, query entity.QueryS
 
 	order := fmt.Sprintf("%s %s", query.OrderBy, query.OrderType)
 	offset := (query.Page - 1) * query.Limit
-	status := ""
-	if query.Status != "" {
-		status = query.Status
-	}
-
-	sqlMain := c.db.Preload(clause.Associations).
-		Where("title ilike ? OR excerpt ilike ? OR description ilike ?", "%"+query.Search+"%", "%"+query.Search+"%", "%"+query.Search+"%").
-		Where("status LIKE ?", "%"+status+"%")
-
-	if query.CategoryID > 0 {
-		sqlMain = sqlMain.Where("category_id = ?", query.CategoryID)
-	}
+	sqlMain := filterContents(conn(ctx, c.db).Preload(clause.Associations), query)
 
 	err = sqlMain.Model(&modelContents).Count(&countData).Error
 	if err != nil {
 		code = "[REPOSITORY] GetContents = 1"
 		log.Errorw(code, err)
-		return nil, 0, 0, err
+		return nil, 0, err
 	}
 
-	totalPages := int(math.Ceil(float64(countData) / float64(query.Limit)))
-
 	err = sqlMain.
 		Order(order).
 		Limit(query.Limit).
@@ -176,38 +410,323 @@ func (c *contentRepository) GetContents(ctx context.Context, query entity.QueryS
 	if err != nil {
 		code = "[REPOSITORY] GetContents = 2"
 		log.Errorw(code, err)
-		return nil, 0, 0, err
+		return nil, 0, err
+	}
+
+	resps := []entity.ContentEntity{}
+	for _, content := range modelContents {
+		resps = append(resps, toContentEntity(content))
+	}
+
+	return resps, countData, nil
+
+}
+
+// GetContentsByCursor implements ContentRepository.
+// It pages with a (created_at, id) keyset instead of OFFSET, so rows inserted
+// while a reader scrolls neither shift nor duplicate the following pages.
+func (c *contentRepository) GetContentsByCursor(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, *entity.Cursor, error) {
+	var modelContents []model.Content
+
+	if query.Limit <= 0 {
+		query.Limit = 10
+	}
+
+	sqlMain := filterContents(conn(ctx, c.db).Preload(clause.Associations), query)
+
+	if query.After != nil {
+		sqlMain = sqlMain.Where("(created_at, id) < (?, ?)", query.After.CreatedAt, query.After.ID)
+	}
+
+	err = sqlMain.
+		Order("created_at DESC, id DESC").
+		Limit(query.Limit + 1).
+		Find(&modelContents).Error
+	if err != nil {
+		code = "[REPOSITORY] GetContentsByCursor = 1"
+		log.Errorw(code, err)
+		return nil, nil, err
+	}
+
+	var nextCursor *entity.Cursor
+	if len(modelContents) > query.Limit {
+		modelContents = modelContents[:query.Limit]
+		last := modelContents[len(modelContents)-1]
+		nextCursor = &entity.Cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		}
+	}
+
+	resps := []entity.ContentEntity{}
+	for _, content := range modelContents {
+		resps = append(resps, toContentEntity(content))
+	}
+
+	return resps, nextCursor, nil
+}
+
+// GetTags implements ContentRepository.
+// Tags live as a comma separated column on contents, so they are unnested
+// and counted per content matching query.Status.
+func (c *contentRepository) GetTags(ctx context.Context, query entity.QueryString) ([]entity.TagEntity, int64, error) {
+	var countData int64
+	var resps []entity.TagEntity
+
+	if query.Limit <= 0 {
+		query.Limit = 10
+	}
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	tagQuery := conn(ctx, c.db).Table("contents").
+		Select("DISTINCT id, TRIM(UNNEST(STRING_TO_ARRAY(tags, ','))) AS name").
+		Where("status LIKE ?", "%"+query.Status+"%")
+
+	sqlMain := conn(ctx, c.db).Table("(?) AS t", tagQuery).Where("name <> ''")
+	if query.Search != "" {
+		sqlMain = sqlMain.Where("name ilike ?", "%"+query.Search+"%")
+	}
+	sqlMain = sqlMain.Session(&gorm.Session{})
+
+	err = sqlMain.Distinct("name").Count(&countData).Error
+	if err != nil {
+		code = "[REPOSITORY] GetTags = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	err = sqlMain.
+		Select("name, COUNT(*) AS total").
+		Group("name").
+		Order("total DESC, name ASC").
+		Limit(query.Limit).
+		Offset((query.Page - 1) * query.Limit).
+		Scan(&resps).Error
+	if err != nil {
+		code = "[REPOSITORY] GetTags = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	return resps, countData, nil
+}
+
+// GetSitemapContents implements ContentRepository.
+// It pages in id order and skips the description column, so listing every
+// article for a sitemap stays cheap.
+func (c *contentRepository) GetSitemapContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, error) {
+	var modelContents []model.Content
+	var countData int64
+
+	sqlMain := filterContents(conn(ctx, c.db).Model(&model.Content{}), query)
+
+	err = sqlMain.Count(&countData).Error
+	if err != nil {
+		code = "[REPOSITORY] GetSitemapContents = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	err = sqlMain.
+		Select("id", "title", "slug", "tags", "category_id", "created_at", "updated_at").
+		Preload("Category").
+		Order("id ASC").
+		Limit(query.Limit).
+		Offset((query.Page - 1) * query.Limit).
+		Find(&modelContents).Error
+	if err != nil {
+		code = "[REPOSITORY] GetSitemapContents = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	resps := []entity.ContentEntity{}
+	for _, content := range modelContents {
+		resps = append(resps, toContentEntity(content))
+	}
+
+	return resps, countData, nil
+}
+
+// GetContentIDs implements ContentRepository.
+// It resolves the same filters as GetContents to at most limit ids.
+func (c *contentRepository) GetContentIDs(ctx context.Context, query entity.QueryString, limit int) ([]int64, error) {
+	var ids []int64
+
+	err = filterContents(conn(ctx, c.db).Model(&model.Content{}), query).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		code = "[REPOSITORY] GetContentIDs = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetContentsByIDs implements ContentRepository.
+// Missing ids are silently left out of the result.
+func (c *contentRepository) GetContentsByIDs(ctx context.Context, ids []int64) ([]entity.ContentEntity, error) {
+	var modelContents []model.Content
+
+	err = conn(ctx, c.db).Where("id IN ?", ids).Find(&modelContents).Error
+	if err != nil {
+		code = "[REPOSITORY] GetContentsByIDs = 1"
+		log.Errorw(code, err)
+		return nil, err
 	}
 
 	resps := []entity.ContentEntity{}
 	for _, content := range modelContents {
-		tags := strings.Split(content.Tags, ",")
-		resps = append(resps, entity.ContentEntity{
-			ID:          content.ID,
-			Title:       content.Title,
-			Excerpt:     content.Excerpt,
-			Description: content.Description,
-			Image:       content.Image,
-			Tags:        tags,
-			Status:      content.Status,
-			CategoryID:  content.CategoryID,
-			CreatedByID: content.CreatedByID,
-			CreatedAt:   content.CreatedAt,
-			Category: entity.CategoryEntity{
-				ID:    content.Category.ID,
-				Title: content.Category.Title,
-				Slug:  content.Category.Slug,
-			},
-			User: entity.UserEntity{
-				ID:   content.User.ID,
-				Name: content.User.Name,
-			},
-		})
-
-	}
-
-	return resps, countData, int64(totalPages), nil
+		resps = append(resps, toContentEntity(content))
+	}
+
+	return resps, nil
+}
+
+// GetRelatedCandidates implements ContentRepository.
+// Candidates are the newest published contents, other than content itself,
+// sharing a tag or the category with it; ranking them is left to the caller.
+func (c *contentRepository) GetRelatedCandidates(ctx context.Context, content entity.ContentEntity, limit int) ([]entity.ContentEntity, error) {
+	var modelContents []model.Content
+
+	sqlMain := conn(ctx, c.db).Preload(clause.Associations).
+		Where("status = ? AND id <> ?", entity.ContentStatusPublish, content.ID)
+
+	tags := related.NormalizeTags(content.Tags)
+	if len(tags) > 0 {
+		sqlMain = sqlMain.Where("category_id = ? OR EXISTS (SELECT 1 FROM UNNEST(REGEXP_SPLIT_TO_ARRAY(LOWER(tags), '\\s*,\\s*')) AS tag WHERE tag IN ?)", content.CategoryID, tags)
+	} else {
+		sqlMain = sqlMain.Where("category_id = ?", content.CategoryID)
+	}
+
+	err = sqlMain.Order("created_at DESC").Limit(limit).Find(&modelContents).Error
+	if err != nil {
+		code = "[REPOSITORY] GetRelatedCandidates = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := []entity.ContentEntity{}
+	for _, content := range modelContents {
+		resps = append(resps, toContentEntity(content))
+	}
+
+	return resps, nil
+}
+
+// filterContents applies the search, status, category, tag and creation
+// time filters shared by the content listings.
+func filterContents(sqlMain *gorm.DB, query entity.QueryString) *gorm.DB {
+	sqlMain = sqlMain.
+		Where("title ilike ? OR excerpt ilike ? OR description ilike ?", "%"+query.Search+"%", "%"+query.Search+"%", "%"+query.Search+"%").
+		Where("status LIKE ?", "%"+query.Status+"%")
+
+	if query.CategoryID > 0 {
+		sqlMain = sqlMain.Where("category_id = ?", query.CategoryID)
+	}
+
+	// Tags are split and trimmed the way GetTags lists them, and compared
+	// exactly so LIKE wildcards in a tag match nothing but themselves.
+	if tag := strings.TrimSpace(query.Tag); tag != "" {
+		sqlMain = sqlMain.Where("EXISTS (SELECT 1 FROM UNNEST(STRING_TO_ARRAY(tags, ',')) AS tag WHERE LOWER(TRIM(tag)) = LOWER(?))", tag)
+	}
+
+	if !query.Since.IsZero() {
+		sqlMain = sqlMain.Where("created_at >= ?", query.Since)
+	}
+
+	return sqlMain
+}
+
+// toContentEntity maps a content row, with its preloaded category and
+// author, onto the entity returned to services.
+func toContentEntity(content model.Content) entity.ContentEntity {
+	return entity.ContentEntity{
+		ID:          content.ID,
+		Title:       content.Title,
+		Slug:        content.Slug,
+		Excerpt:     content.Excerpt,
+		Description: content.Description,
+		BodyFormat:  content.BodyFormat,
+		BodyHTML:    content.BodyHTML,
+		Blocks:      blocksValue(content.Blocks),
+		Image:       content.Image,
+		Tags:        strings.Split(content.Tags, ","),
+		Status:      content.Status,
+		Type:        content.Type,
+		SEO: entity.ContentSEOEntity{
+			MetaTitle:       content.MetaTitle,
+			MetaDescription: content.MetaDescription,
+			CanonicalURL:    content.CanonicalURL,
+			SocialImage:     content.SocialImage,
+			NoIndex:         content.NoIndex,
+		},
+		Comments: entity.CommentSettingsEntity{
+			Enabled: content.CommentsEnabled,
+			Closed:  content.CommentsClosed,
+		},
+		CategoryID:  content.CategoryID,
+		CreatedByID: content.CreatedByID,
+		CreatedAt:   content.CreatedAt,
+		UpdatedAt:   updatedAt(content.CreatedAt, content.UpdatedAt),
+		Version:     content.Version,
+		Category: entity.CategoryEntity{
+			ID:    content.Category.ID,
+			Title: content.Category.Title,
+			Slug:  content.Category.Slug,
+		},
+		User: entity.UserEntity{
+			ID:    content.User.ID,
+			Name:  content.User.Name,
+			Email: content.User.Email,
+		},
+	}
+}
+
+// contentType defaults an empty content type to an article.
+func contentType(t string) string {
+	if t == "" {
+		return entity.ContentTypeArticle
+	}
+
+	return t
+}
+
+// blocksColumn maps an empty block document to NULL.
+func blocksColumn(doc json.RawMessage) *string {
+	if len(doc) == 0 {
+		return nil
+	}
+
+	column := string(doc)
+	return &column
+}
+
+func blocksValue(column *string) json.RawMessage {
+	if column == nil {
+		return nil
+	}
+
+	return json.RawMessage(*column)
+}
+
+// updatedAt falls back to createdAt for rows that were never updated.
+func updatedAt(createdAt time.Time, updatedAt *time.Time) time.Time {
+	if updatedAt == nil {
+		return createdAt
+	}
 
+	return *updatedAt
 }
 
 func NewContentRepository(db *gorm.DB) ContentRepository {
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/domain/model"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContentStatRepository interface {
+	AddContentViews(ctx context.Context, counts []entity.ContentViewCount) error
+	PruneContentStats(ctx context.Context, before time.Time) error
+	GetTrendingContents(ctx context.Context, query entity.TrendingQuery) ([]entity.TrendingContentEntity, error)
+}
+
+type contentStatRepository struct {
+	db *gorm.DB
+}
+
+// AddContentViews implements ContentStatRepository.
+// Counts are added to the stored buckets in one statement. Counts of
+// contents that do not exist or are not published are dropped, so a bogus
+// id cannot fail the batch.
+func (c *contentStatRepository) AddContentViews(ctx context.Context, counts []entity.ContentViewCount) error {
+	ids := []int64{}
+	for _, count := range counts {
+		ids = append(ids, count.ContentID)
+	}
+
+	var published []int64
+	err = conn(ctx, c.db).Model(&model.Content{}).Where("id IN ? AND status = ?", ids, entity.ContentStatusPublish).Pluck("id", &published).Error
+	if err != nil {
+		code = "[REPOSITORY] AddContentViews = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	known := map[int64]bool{}
+	for _, id := range published {
+		known[id] = true
+	}
+
+	modelStats := []model.ContentStat{}
+	for _, count := range counts {
+		if known[count.ContentID] {
+			modelStats = append(modelStats, model.ContentStat{
+				ContentID:   count.ContentID,
+				BucketStart: count.BucketStart,
+				Views:       count.Views,
+			})
+		}
+	}
+
+	if len(modelStats) == 0 {
+		return nil
+	}
+
+	err = conn(ctx, c.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "content_id"}, {Name: "bucket_start"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("content_stats.views + excluded.views")}),
+	}).Create(&modelStats).Error
+	if err != nil {
+		code = "[REPOSITORY] AddContentViews = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// PruneContentStats implements ContentStatRepository.
+func (c *contentStatRepository) PruneContentStats(ctx context.Context, before time.Time) error {
+	err = conn(ctx, c.db).Where("bucket_start < ?", before).Delete(&model.ContentStat{}).Error
+	if err != nil {
+		code = "[REPOSITORY] PruneContentStats = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetTrendingContents implements ContentStatRepository.
+// The score of a content is the sum of its bucket views, each halved for
+// every query.HalfLife between the bucket and query.Now. Only published
+// contents are ranked.
+func (c *contentStatRepository) GetTrendingContents(ctx context.Context, query entity.TrendingQuery) ([]entity.TrendingContentEntity, error) {
+	var rows []struct {
+		ContentID int64
+		Score     float64
+		Views     int64
+	}
+
+	err = conn(ctx, c.db).Model(&model.ContentStat{}).
+		Select("content_stats.content_id, SUM(content_stats.views * POWER(0.5, EXTRACT(EPOCH FROM (?::timestamp - content_stats.bucket_start)) / ?)) AS score, SUM(content_stats.views) AS views",
+			query.Now, query.HalfLife.Seconds()).
+		Joins("JOIN contents ON contents.id = content_stats.content_id").
+		Where("content_stats.bucket_start >= ? AND contents.status = ?", query.Since, entity.ContentStatusPublish).
+		Group("content_stats.content_id").
+		Order("score DESC, content_stats.content_id DESC").
+		Limit(query.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		code = "[REPOSITORY] GetTrendingContents = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return []entity.TrendingContentEntity{}, nil
+	}
+
+	ids := []int64{}
+	for _, row := range rows {
+		ids = append(ids, row.ContentID)
+	}
+
+	var modelContents []model.Content
+	err = conn(ctx, c.db).Where("id IN ?", ids).Preload(clause.Associations).Find(&modelContents).Error
+	if err != nil {
+		code = "[REPOSITORY] GetTrendingContents = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	byID := map[int64]model.Content{}
+	for _, content := range modelContents {
+		byID[content.ID] = content
+	}
+
+	resps := []entity.TrendingContentEntity{}
+	for _, row := range rows {
+		content, ok := byID[row.ContentID]
+		if !ok {
+			continue
+		}
+
+		resps = append(resps, entity.TrendingContentEntity{
+			Content: toContentEntity(content),
+			Score:   row.Score,
+			Views:   row.Views,
+		})
+	}
+
+	return resps, nil
+}
+
+func NewContentStatRepository(db *gorm.DB) ContentStatRepository {
+	return &contentStatRepository{db: db}
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/domain/model"
+	"context"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EmbedRepository interface {
+	GetEmbeds(ctx context.Context, urls []string) ([]entity.EmbedEntity, error)
+	SaveEmbed(ctx context.Context, req entity.EmbedEntity) error
+}
+
+type embedRepository struct {
+	db *gorm.DB
+}
+
+// GetEmbeds implements EmbedRepository.
+// Expired rows are returned too; the caller decides whether a stale embed
+// is better than none.
+func (e *embedRepository) GetEmbeds(ctx context.Context, urls []string) ([]entity.EmbedEntity, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	var modelEmbeds []model.Embed
+	err = conn(ctx, e.db).Where("url IN ?", urls).Find(&modelEmbeds).Error
+	if err != nil {
+		code = "[REPOSITORY] GetEmbeds = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := []entity.EmbedEntity{}
+	for _, val := range modelEmbeds {
+		resps = append(resps, entity.EmbedEntity{
+			URL:             val.URL,
+			Provider:        val.Provider,
+			Type:            val.Type,
+			Title:           val.Title,
+			AuthorName:      val.AuthorName,
+			AuthorURL:       val.AuthorURL,
+			ProviderName:    val.ProviderName,
+			ProviderURL:     val.ProviderURL,
+			HTML:            val.HTML,
+			Width:           val.Width,
+			Height:          val.Height,
+			ThumbnailURL:    val.ThumbnailURL,
+			ThumbnailWidth:  val.ThumbnailWidth,
+			ThumbnailHeight: val.ThumbnailHeight,
+			FetchedAt:       val.FetchedAt,
+			ExpiresAt:       val.ExpiresAt,
+		})
+	}
+
+	return resps, nil
+}
+
+// SaveEmbed implements EmbedRepository.
+// It inserts the embed or replaces the cached response of its URL.
+func (e *embedRepository) SaveEmbed(ctx context.Context, req entity.EmbedEntity) error {
+	modelEmbed := model.Embed{
+		URL:             req.URL,
+		Provider:        req.Provider,
+		Type:            req.Type,
+		Title:           req.Title,
+		AuthorName:      req.AuthorName,
+		AuthorURL:       req.AuthorURL,
+		ProviderName:    req.ProviderName,
+		ProviderURL:     req.ProviderURL,
+		HTML:            req.HTML,
+		Width:           req.Width,
+		Height:          req.Height,
+		ThumbnailURL:    req.ThumbnailURL,
+		ThumbnailWidth:  req.ThumbnailWidth,
+		ThumbnailHeight: req.ThumbnailHeight,
+		FetchedAt:       req.FetchedAt,
+		ExpiresAt:       req.ExpiresAt,
+	}
+
+	err = conn(ctx, e.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "url"}},
+		UpdateAll: true,
+	}).Create(&modelEmbed).Error
+	if err != nil {
+		code = "[REPOSITORY] SaveEmbed = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+func NewEmbedRepository(db *gorm.DB) EmbedRepository {
+	return &embedRepository{db: db}
+}
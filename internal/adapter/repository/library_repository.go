@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/domain/model"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LibraryRepository interface {
+	GetBookmarks(ctx context.Context, query entity.LibraryQuery) ([]entity.BookmarkEntity, int64, error)
+	CreateBookmark(ctx context.Context, readerID, contentID int64) error
+	DeleteBookmark(ctx context.Context, readerID, contentID int64) error
+	GetReadingHistory(ctx context.Context, query entity.LibraryQuery) ([]entity.ReadingHistoryEntity, int64, error)
+	SaveReadingProgress(ctx context.Context, readerID, contentID int64, progress int) error
+}
+
+type libraryRepository struct {
+	db *gorm.DB
+}
+
+// GetBookmarks implements LibraryRepository.
+// Bookmarks of contents that are no longer published are left out, newest
+// bookmark first.
+func (l *libraryRepository) GetBookmarks(ctx context.Context, query entity.LibraryQuery) ([]entity.BookmarkEntity, int64, error) {
+	var modelBookmarks []model.Bookmark
+	var countData int64
+
+	sqlMain := conn(ctx, l.db).Model(&model.Bookmark{}).
+		Joins("JOIN contents ON contents.id = bookmarks.content_id").
+		Where("bookmarks.reader_id = ? AND contents.status = ?", query.ReaderID, entity.ContentStatusPublish)
+
+	err = sqlMain.Count(&countData).Error
+	if err != nil {
+		code = "[REPOSITORY] GetBookmarks = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	err = sqlMain.Preload("Content.Category").Preload("Content.User").
+		Order("bookmarks.created_at DESC, bookmarks.content_id DESC").
+		Limit(query.Limit).Offset(offset).Find(&modelBookmarks).Error
+	if err != nil {
+		code = "[REPOSITORY] GetBookmarks = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	resps := []entity.BookmarkEntity{}
+	for _, val := range modelBookmarks {
+		resps = append(resps, entity.BookmarkEntity{
+			ContentID: val.ContentID,
+			Content:   toContentEntity(val.Content),
+			CreatedAt: val.CreatedAt,
+		})
+	}
+
+	return resps, countData, nil
+}
+
+// CreateBookmark implements LibraryRepository.
+// Bookmarking a content twice keeps the first bookmark.
+func (l *libraryRepository) CreateBookmark(ctx context.Context, readerID, contentID int64) error {
+	modelBookmark := model.Bookmark{
+		ReaderID:  readerID,
+		ContentID: contentID,
+		CreatedAt: time.Now(),
+	}
+
+	err = conn(ctx, l.db).Omit("Content").Clauses(clause.OnConflict{DoNothing: true}).Create(&modelBookmark).Error
+	if err != nil {
+		code = "[REPOSITORY] CreateBookmark = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteBookmark implements LibraryRepository.
+// Removing a bookmark that does not exist is not an error.
+func (l *libraryRepository) DeleteBookmark(ctx context.Context, readerID, contentID int64) error {
+	err = conn(ctx, l.db).Where("reader_id = ? AND content_id = ?", readerID, contentID).Delete(&model.Bookmark{}).Error
+	if err != nil {
+		code = "[REPOSITORY] DeleteBookmark = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetReadingHistory implements LibraryRepository.
+// Like GetBookmarks it only lists published contents, most recently read
+// first.
+func (l *libraryRepository) GetReadingHistory(ctx context.Context, query entity.LibraryQuery) ([]entity.ReadingHistoryEntity, int64, error) {
+	var modelHistory []model.ReadingHistory
+	var countData int64
+
+	sqlMain := conn(ctx, l.db).Model(&model.ReadingHistory{}).
+		Joins("JOIN contents ON contents.id = reading_history.content_id").
+		Where("reading_history.reader_id = ? AND contents.status = ?", query.ReaderID, entity.ContentStatusPublish)
+
+	if query.Unfinished {
+		sqlMain = sqlMain.Where("reading_history.progress < 100")
+	}
+
+	err = sqlMain.Count(&countData).Error
+	if err != nil {
+		code = "[REPOSITORY] GetReadingHistory = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	err = sqlMain.Preload("Content.Category").Preload("Content.User").
+		Order("reading_history.updated_at DESC, reading_history.content_id DESC").
+		Limit(query.Limit).Offset(offset).Find(&modelHistory).Error
+	if err != nil {
+		code = "[REPOSITORY] GetReadingHistory = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	resps := []entity.ReadingHistoryEntity{}
+	for _, val := range modelHistory {
+		resps = append(resps, entity.ReadingHistoryEntity{
+			ContentID: val.ContentID,
+			Content:   toContentEntity(val.Content),
+			Progress:  val.Progress,
+			UpdatedAt: val.UpdatedAt,
+		})
+	}
+
+	return resps, countData, nil
+}
+
+// SaveReadingProgress implements LibraryRepository.
+// The latest progress wins, so a reader scrolling back is resumed where
+// they left off.
+func (l *libraryRepository) SaveReadingProgress(ctx context.Context, readerID, contentID int64, progress int) error {
+	now := time.Now()
+	modelHistory := model.ReadingHistory{
+		ReaderID:  readerID,
+		ContentID: contentID,
+		Progress:  progress,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err = conn(ctx, l.db).Omit("Content").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "reader_id"}, {Name: "content_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"progress", "updated_at"}),
+	}).Create(&modelHistory).Error
+	if err != nil {
+		code = "[REPOSITORY] SaveReadingProgress = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+func NewLibraryRepository(db *gorm.DB) LibraryRepository {
+	return &libraryRepository{db: db}
+}
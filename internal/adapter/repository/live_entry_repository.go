@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/domain/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type LiveEntryRepository interface {
+	GetLiveEntries(ctx context.Context, query entity.LiveEntryQuery) ([]entity.LiveEntryEntity, int64, error)
+	GetLiveEntriesSince(ctx context.Context, contentID, seq int64, limit int) ([]entity.LiveEntryEntity, error)
+	GetLastLiveSeq(ctx context.Context, contentID int64) (int64, error)
+	GetLiveEntryByID(ctx context.Context, contentID, id int64) (*entity.LiveEntryEntity, error)
+	CreateLiveEntry(ctx context.Context, req entity.LiveEntryEntity) (int64, error)
+	UpdateLiveEntry(ctx context.Context, req entity.LiveEntryEntity) error
+	PinLiveEntry(ctx context.Context, contentID, id int64, pinned bool) error
+	DeleteLiveEntry(ctx context.Context, contentID, id int64) error
+}
+
+type liveEntryRepository struct {
+	db *gorm.DB
+}
+
+// nextLiveSeq moves an entry to the end of the change stream, so readers
+// resuming from an earlier event id receive the change.
+var nextLiveSeq = gorm.Expr("nextval('live_entries_seq')")
+
+// GetLiveEntries implements LiveEntryRepository.
+func (l *liveEntryRepository) GetLiveEntries(ctx context.Context, query entity.LiveEntryQuery) ([]entity.LiveEntryEntity, int64, error) {
+	var modelEntries []model.LiveEntry
+	var countData int64
+
+	sqlMain := conn(ctx, l.db).Model(&model.LiveEntry{}).
+		Where("live_entries.content_id = ? AND live_entries.deleted_at IS NULL", query.ContentID)
+
+	err = sqlMain.Count(&countData).Error
+	if err != nil {
+		code = "[REPOSITORY] GetLiveEntries = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	err = sqlMain.Joins("User").
+		Order("live_entries.pinned DESC, live_entries.created_at DESC, live_entries.id DESC").
+		Limit(query.Limit).Offset(offset).Find(&modelEntries).Error
+	if err != nil {
+		code = "[REPOSITORY] GetLiveEntries = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	resps := []entity.LiveEntryEntity{}
+	for _, val := range modelEntries {
+		resps = append(resps, toLiveEntryEntity(val))
+	}
+
+	return resps, countData, nil
+}
+
+// GetLiveEntriesSince implements LiveEntryRepository.
+// It returns the entries changed after seq in the order they changed,
+// deleted ones included. Writes to a live blog take their seq under
+// lockLiveBlog, so the seqs of one blog commit in order and a reader that
+// has seen seq never misses a smaller one committed later.
+func (l *liveEntryRepository) GetLiveEntriesSince(ctx context.Context, contentID, seq int64, limit int) ([]entity.LiveEntryEntity, error) {
+	var modelEntries []model.LiveEntry
+
+	err = conn(ctx, l.db).Joins("User").
+		Where("live_entries.content_id = ? AND live_entries.seq > ?", contentID, seq).
+		Order("live_entries.seq ASC").
+		Limit(limit).Find(&modelEntries).Error
+	if err != nil {
+		code = "[REPOSITORY] GetLiveEntriesSince = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := []entity.LiveEntryEntity{}
+	for _, val := range modelEntries {
+		resps = append(resps, toLiveEntryEntity(val))
+	}
+
+	return resps, nil
+}
+
+// GetLastLiveSeq implements LiveEntryRepository.
+// It is 0 for a live blog without entries.
+func (l *liveEntryRepository) GetLastLiveSeq(ctx context.Context, contentID int64) (int64, error) {
+	var seq int64
+
+	err = conn(ctx, l.db).Model(&model.LiveEntry{}).
+		Where("content_id = ?", contentID).
+		Select("COALESCE(MAX(seq), 0)").Scan(&seq).Error
+	if err != nil {
+		code = "[REPOSITORY] GetLastLiveSeq = 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return seq, nil
+}
+
+// GetLiveEntryByID implements LiveEntryRepository.
+// Deleted entries are not found.
+func (l *liveEntryRepository) GetLiveEntryByID(ctx context.Context, contentID, id int64) (*entity.LiveEntryEntity, error) {
+	var modelEntry model.LiveEntry
+
+	err = conn(ctx, l.db).Joins("User").
+		Where("live_entries.id = ? AND live_entries.content_id = ? AND live_entries.deleted_at IS NULL", id, contentID).
+		First(&modelEntry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		code = "[REPOSITORY] GetLiveEntryByID = 1"
+		log.Errorw(code, err)
+		return nil, entity.ErrNotFound
+	}
+
+	if err != nil {
+		code = "[REPOSITORY] GetLiveEntryByID = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resp := toLiveEntryEntity(modelEntry)
+
+	return &resp, nil
+}
+
+// CreateLiveEntry implements LiveEntryRepository.
+func (l *liveEntryRepository) CreateLiveEntry(ctx context.Context, req entity.LiveEntryEntity) (int64, error) {
+	modelEntry := model.LiveEntry{
+		ContentID:   req.ContentID,
+		Title:       req.Title,
+		Body:        req.Body,
+		Pinned:      req.Pinned,
+		CreatedByID: req.CreatedByID,
+		CreatedAt:   time.Now(),
+	}
+
+	err = l.lockLiveBlog(ctx, req.ContentID, func(tx *gorm.DB) error {
+		return tx.Omit("User", "UpdatedAt").Create(&modelEntry).Error
+	})
+	if err != nil {
+		code = "[REPOSITORY] CreateLiveEntry = 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return modelEntry.ID, nil
+}
+
+// UpdateLiveEntry implements LiveEntryRepository.
+// Only the title and the body change; the entry keeps its place in the
+// timeline.
+func (l *liveEntryRepository) UpdateLiveEntry(ctx context.Context, req entity.LiveEntryEntity) error {
+	return l.updateLiveEntry(ctx, req.ContentID, req.ID, map[string]interface{}{
+		"title": req.Title,
+		"body":  req.Body,
+	})
+}
+
+// PinLiveEntry implements LiveEntryRepository.
+func (l *liveEntryRepository) PinLiveEntry(ctx context.Context, contentID, id int64, pinned bool) error {
+	return l.updateLiveEntry(ctx, contentID, id, map[string]interface{}{
+		"pinned": pinned,
+	})
+}
+
+// DeleteLiveEntry implements LiveEntryRepository.
+// The row stays behind as a tombstone so resuming readers learn about the
+// deletion; it is removed with its content.
+func (l *liveEntryRepository) DeleteLiveEntry(ctx context.Context, contentID, id int64) error {
+	return l.updateLiveEntry(ctx, contentID, id, map[string]interface{}{
+		"deleted_at": time.Now(),
+	})
+}
+
+// updateLiveEntry applies updates to a live entry that is not deleted and
+// bumps its seq and updated_at.
+func (l *liveEntryRepository) updateLiveEntry(ctx context.Context, contentID, id int64, updates map[string]interface{}) error {
+	updates["seq"] = nextLiveSeq
+	updates["updated_at"] = time.Now()
+
+	var rows int64
+	err = l.lockLiveBlog(ctx, contentID, func(tx *gorm.DB) error {
+		result := tx.Model(&model.LiveEntry{}).
+			Where("id = ? AND content_id = ? AND deleted_at IS NULL", id, contentID).
+			Updates(updates)
+		rows = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		code = "[REPOSITORY] updateLiveEntry = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if rows == 0 {
+		code = "[REPOSITORY] updateLiveEntry = 2"
+		log.Errorw(code, entity.ErrNotFound)
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// lockLiveBlog runs fn in a transaction holding an advisory lock on the
+// live blog until it commits. Without it a write could take a seq, commit
+// after a later one and be skipped by readers already past that seq.
+func (l *liveEntryRepository) lockLiveBlog(ctx context.Context, contentID int64, fn func(tx *gorm.DB) error) error {
+	return conn(ctx, l.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", fmt.Sprintf("live_entries:%d", contentID)).Error
+		if err != nil {
+			return err
+		}
+
+		return fn(tx)
+	})
+}
+
+func toLiveEntryEntity(entry model.LiveEntry) entity.LiveEntryEntity {
+	return entity.LiveEntryEntity{
+		ID:          entry.ID,
+		ContentID:   entry.ContentID,
+		Title:       entry.Title,
+		Body:        entry.Body,
+		Pinned:      entry.Pinned,
+		Seq:         entry.Seq,
+		CreatedByID: entry.CreatedByID,
+		AuthorName:  entry.User.Name,
+		CreatedAt:   entry.CreatedAt,
+		UpdatedAt:   entry.UpdatedAt,
+		DeletedAt:   entry.DeletedAt,
+	}
+}
+
+func NewLiveEntryRepository(db *gorm.DB) LiveEntryRepository {
+	return &liveEntryRepository{db: db}
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/domain/model"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PlacementRepository interface {
+	GetPlacements(ctx context.Context, query entity.PlacementQuery) ([]entity.PlacementEntity, int64, error)
+	GetPlacementByID(ctx context.Context, id int64) (*entity.PlacementEntity, error)
+	GetSlotContents(ctx context.Context, slot string, now time.Time, limit int) ([]entity.PlacementEntity, error)
+	CreatePlacement(ctx context.Context, req entity.PlacementEntity) (int64, error)
+	UpdatePlacement(ctx context.Context, id int64, schedule entity.PlacementSchedule) error
+	DeletePlacement(ctx context.Context, id int64) error
+}
+
+type placementRepository struct {
+	db *gorm.DB
+}
+
+// GetPlacements implements PlacementRepository.
+// Expired and scheduled placements are listed too, each slot in display
+// order.
+func (p *placementRepository) GetPlacements(ctx context.Context, query entity.PlacementQuery) ([]entity.PlacementEntity, int64, error) {
+	var modelPlacements []model.Placement
+	var countData int64
+
+	sqlMain := conn(ctx, p.db).Model(&model.Placement{})
+	if query.Slot != "" {
+		sqlMain = sqlMain.Where("slot = ?", query.Slot)
+	}
+
+	err = sqlMain.Count(&countData).Error
+	if err != nil {
+		code = "[REPOSITORY] GetPlacements = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	err = sqlMain.Preload("Content", placementContentSummary).
+		Order("slot ASC, position ASC, created_at DESC").
+		Limit(query.Limit).Offset(offset).Find(&modelPlacements).Error
+	if err != nil {
+		code = "[REPOSITORY] GetPlacements = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	resps := []entity.PlacementEntity{}
+	for _, val := range modelPlacements {
+		resps = append(resps, toPlacementEntity(val))
+	}
+
+	return resps, countData, nil
+}
+
+// GetPlacementByID implements PlacementRepository.
+func (p *placementRepository) GetPlacementByID(ctx context.Context, id int64) (*entity.PlacementEntity, error) {
+	var modelPlacement model.Placement
+
+	err = conn(ctx, p.db).Where("id = ?", id).Preload("Content", placementContentSummary).First(&modelPlacement).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		code = "[REPOSITORY] GetPlacementByID = 1"
+		log.Errorw(code, err)
+		return nil, entity.ErrNotFound
+	}
+
+	if err != nil {
+		code = "[REPOSITORY] GetPlacementByID = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resp := toPlacementEntity(modelPlacement)
+
+	return &resp, nil
+}
+
+// GetSlotContents implements PlacementRepository.
+// The live placements of slot are read with their published contents,
+// categories and authors in a single joined query.
+func (p *placementRepository) GetSlotContents(ctx context.Context, slot string, now time.Time, limit int) ([]entity.PlacementEntity, error) {
+	var modelPlacements []model.Placement
+
+	err = conn(ctx, p.db).
+		Joins("Content").Joins("Content.Category").Joins("Content.User").
+		Where(`placements.slot = ? AND "Content".status = ?`, slot, entity.ContentStatusPublish).
+		Where("placements.starts_at IS NULL OR placements.starts_at <= ?", now).
+		Where("placements.expires_at IS NULL OR placements.expires_at > ?", now).
+		Order("placements.position ASC, placements.created_at DESC").
+		Limit(limit).Find(&modelPlacements).Error
+	if err != nil {
+		code = "[REPOSITORY] GetSlotContents = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := []entity.PlacementEntity{}
+	for _, val := range modelPlacements {
+		resps = append(resps, toPlacementEntity(val))
+	}
+
+	return resps, nil
+}
+
+// CreatePlacement implements PlacementRepository.
+// Placing a content in a slot it is already in reschedules the existing
+// placement instead of adding a second one.
+func (p *placementRepository) CreatePlacement(ctx context.Context, req entity.PlacementEntity) (int64, error) {
+	now := time.Now()
+	modelPlacement := model.Placement{
+		Slot:        req.Slot,
+		ContentID:   req.ContentID,
+		Position:    req.Position,
+		StartsAt:    req.StartsAt,
+		ExpiresAt:   req.ExpiresAt,
+		CreatedByID: req.CreatedByID,
+		CreatedAt:   now,
+	}
+
+	err = conn(ctx, p.db).Omit("Content").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "slot"}, {Name: "content_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"position":   req.Position,
+			"starts_at":  req.StartsAt,
+			"expires_at": req.ExpiresAt,
+			"updated_at": now,
+		}),
+	}).Create(&modelPlacement).Error
+	if err != nil {
+		code = "[REPOSITORY] CreatePlacement = 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return modelPlacement.ID, nil
+}
+
+// UpdatePlacement implements PlacementRepository.
+func (p *placementRepository) UpdatePlacement(ctx context.Context, id int64, schedule entity.PlacementSchedule) error {
+	result := conn(ctx, p.db).Model(&model.Placement{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"position":   schedule.Position,
+		"starts_at":  schedule.StartsAt,
+		"expires_at": schedule.ExpiresAt,
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		code = "[REPOSITORY] UpdatePlacement = 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		code = "[REPOSITORY] UpdatePlacement = 2"
+		log.Errorw(code, entity.ErrNotFound)
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeletePlacement implements PlacementRepository.
+func (p *placementRepository) DeletePlacement(ctx context.Context, id int64) error {
+	result := conn(ctx, p.db).Where("id = ?", id).Delete(&model.Placement{})
+	if result.Error != nil {
+		code = "[REPOSITORY] DeletePlacement = 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		code = "[REPOSITORY] DeletePlacement = 2"
+		log.Errorw(code, entity.ErrNotFound)
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// placementContentSummary keeps the preloaded content of a placement down
+// to what the admin list shows.
+func placementContentSummary(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "title", "slug", "status")
+}
+
+func toPlacementEntity(placement model.Placement) entity.PlacementEntity {
+	return entity.PlacementEntity{
+		ID:          placement.ID,
+		Slot:        placement.Slot,
+		ContentID:   placement.ContentID,
+		Content:     toContentEntity(placement.Content),
+		Position:    placement.Position,
+		StartsAt:    placement.StartsAt,
+		ExpiresAt:   placement.ExpiresAt,
+		CreatedByID: placement.CreatedByID,
+		CreatedAt:   placement.CreatedAt,
+		UpdatedAt:   placement.UpdatedAt,
+	}
+}
+
+func NewPlacementRepository(db *gorm.DB) PlacementRepository {
+	return &placementRepository{db: db}
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/domain/model"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type ReaderRepository interface {
+	GetReaderByID(ctx context.Context, id int64) (*entity.ReaderEntity, error)
+	GetReaderByEmail(ctx context.Context, email string) (*entity.ReaderEntity, error)
+	GetReaderByVerification(ctx context.Context, tokenHash string) (*entity.ReaderEntity, error)
+	GetReaderPassword(ctx context.Context, id int64) (string, error)
+	CreateReader(ctx context.Context, req entity.ReaderEntity, verification entity.ReaderVerification) (int64, error)
+	UpdateVerification(ctx context.Context, id int64, verification entity.ReaderVerification) error
+	MarkEmailVerified(ctx context.Context, id int64) error
+	UpdateReaderProfile(ctx context.Context, id int64, name string) error
+	UpdateReaderPassword(ctx context.Context, id int64, password string) error
+}
+
+type readerRepository struct {
+	db *gorm.DB
+}
+
+// GetReaderByID implements ReaderRepository.
+func (r *readerRepository) GetReaderByID(ctx context.Context, id int64) (*entity.ReaderEntity, error) {
+	var modelReader model.Reader
+
+	err = conn(ctx, r.db).Where("id = ?", id).First(&modelReader).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		code = "[REPOSITORY] GetReaderByID = 1"
+		log.Errorw(code, err)
+		return nil, entity.ErrNotFound
+	}
+
+	if err != nil {
+		code = "[REPOSITORY] GetReaderByID = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resp := toReaderEntity(modelReader)
+
+	return &resp, nil
+}
+
+// GetReaderByEmail implements ReaderRepository.
+// It returns nil without an error when no reader has the email, and keeps
+// the password hash for the login check.
+func (r *readerRepository) GetReaderByEmail(ctx context.Context, email string) (*entity.ReaderEntity, error) {
+	var modelReaders []model.Reader
+
+	err = conn(ctx, r.db).Where("email = ?", email).Limit(1).Find(&modelReaders).Error
+	if err != nil {
+		code = "[REPOSITORY] GetReaderByEmail = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if len(modelReaders) == 0 {
+		return nil, nil
+	}
+
+	resp := toReaderEntity(modelReaders[0])
+	resp.Password = modelReaders[0].Password
+
+	return &resp, nil
+}
+
+// GetReaderByVerification implements ReaderRepository.
+// It returns nil without an error when no reader waits for a verification
+// with the token or the token has expired.
+func (r *readerRepository) GetReaderByVerification(ctx context.Context, tokenHash string) (*entity.ReaderEntity, error) {
+	var modelReaders []model.Reader
+
+	err = conn(ctx, r.db).Where("verification_token = ? AND verification_expires_at > ?", tokenHash, time.Now()).
+		Limit(1).Find(&modelReaders).Error
+	if err != nil {
+		code = "[REPOSITORY] GetReaderByVerification = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if len(modelReaders) == 0 {
+		return nil, nil
+	}
+
+	resp := toReaderEntity(modelReaders[0])
+
+	return &resp, nil
+}
+
+// GetReaderPassword implements ReaderRepository.
+// It returns the password hash of the reader.
+func (r *readerRepository) GetReaderPassword(ctx context.Context, id int64) (string, error) {
+	var modelReader model.Reader
+
+	err = conn(ctx, r.db).Select("id", "password").Where("id = ?", id).First(&modelReader).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		code = "[REPOSITORY] GetReaderPassword = 1"
+		log.Errorw(code, err)
+		return "", entity.ErrNotFound
+	}
+
+	if err != nil {
+		code = "[REPOSITORY] GetReaderPassword = 2"
+		log.Errorw(code, err)
+		return "", err
+	}
+
+	return modelReader.Password, nil
+}
+
+// CreateReader implements ReaderRepository.
+// req.Password must already be hashed.
+func (r *readerRepository) CreateReader(ctx context.Context, req entity.ReaderEntity, verification entity.ReaderVerification) (int64, error) {
+	modelReader := model.Reader{
+		Name:                  req.Name,
+		Email:                 req.Email,
+		Password:              req.Password,
+		VerificationToken:     &verification.TokenHash,
+		VerificationExpiresAt: &verification.ExpiresAt,
+		CreatedAt:             time.Now(),
+	}
+
+	err = conn(ctx, r.db).Create(&modelReader).Error
+	if err != nil {
+		code = "[REPOSITORY] CreateReader = 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return modelReader.ID, nil
+}
+
+// UpdateVerification implements ReaderRepository.
+// The new token replaces the previous one, which stops working.
+func (r *readerRepository) UpdateVerification(ctx context.Context, id int64, verification entity.ReaderVerification) error {
+	err = conn(ctx, r.db).Model(&model.Reader{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"verification_token":      verification.TokenHash,
+		"verification_expires_at": verification.ExpiresAt,
+	}).Error
+	if err != nil {
+		code = "[REPOSITORY] UpdateVerification = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkEmailVerified implements ReaderRepository.
+func (r *readerRepository) MarkEmailVerified(ctx context.Context, id int64) error {
+	now := time.Now()
+	err = conn(ctx, r.db).Model(&model.Reader{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email_verified_at":       now,
+		"verification_token":      nil,
+		"verification_expires_at": nil,
+		"updated_at":              now,
+	}).Error
+	if err != nil {
+		code = "[REPOSITORY] MarkEmailVerified = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateReaderProfile implements ReaderRepository.
+func (r *readerRepository) UpdateReaderProfile(ctx context.Context, id int64, name string) error {
+	err = conn(ctx, r.db).Model(&model.Reader{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"name":       name,
+		"updated_at": time.Now(),
+	}).Error
+	if err != nil {
+		code = "[REPOSITORY] UpdateReaderProfile = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateReaderPassword implements ReaderRepository.
+// password must already be hashed.
+func (r *readerRepository) UpdateReaderPassword(ctx context.Context, id int64, password string) error {
+	err = conn(ctx, r.db).Model(&model.Reader{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password":   password,
+		"updated_at": time.Now(),
+	}).Error
+	if err != nil {
+		code = "[REPOSITORY] UpdateReaderPassword = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+func toReaderEntity(reader model.Reader) entity.ReaderEntity {
+	return entity.ReaderEntity{
+		ID:              reader.ID,
+		Name:            reader.Name,
+		Email:           reader.Email,
+		EmailVerifiedAt: reader.EmailVerifiedAt,
+		CreatedAt:       reader.CreatedAt,
+	}
+}
+
+func NewReaderRepository(db *gorm.DB) ReaderRepository {
+	return &readerRepository{db: db}
+}
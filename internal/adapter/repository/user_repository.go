@@ -12,6 +12,9 @@ import (
 type UserRepository interface {
 	UpdatePassword(ctx context.Context, id int64, newPass string) error
 	GetUserByID(ctx context.Context, id int64) (*entity.UserEntity, error)
+	GetUserByEmail(ctx context.Context, email string) (*entity.UserEntity, error)
+	CreateUser(ctx context.Context, req entity.UserEntity) (int64, error)
+	GetUsers(ctx context.Context, query entity.QueryString) ([]entity.UserEntity, int64, error)
 }
 
 type userRepository struct {
@@ -21,7 +24,7 @@ type userRepository struct {
 // GetUserByID implements UserRepository.
 func (u *userRepository) GetUserByID(ctx context.Context, id int64) (*entity.UserEntity, error) {
 	var modelUser model.User
-	err := u.db.Where("id = ?", id).First(&modelUser).Error
+	err := conn(ctx, u.db).Where("id = ?", id).First(&modelUser).Error
 	if err != nil {
 		code := "[REPOSITORY] GetUserByID-1"
 		log.Errorw(code, err)
@@ -32,12 +35,105 @@ func (u *userRepository) GetUserByID(ctx context.Context, id int64) (*entity.Use
 		ID:    modelUser.ID,
 		Name:  modelUser.Name,
 		Email: modelUser.Email,
+		Role:  modelUser.Role,
 	}, nil
 }
 
+// GetUserByEmail implements UserRepository.
+// It returns nil without an error when no user has the email.
+func (u *userRepository) GetUserByEmail(ctx context.Context, email string) (*entity.UserEntity, error) {
+	var modelUsers []model.User
+	err := conn(ctx, u.db).Where("email = ?", email).Limit(1).Find(&modelUsers).Error
+	if err != nil {
+		code := "[REPOSITORY] GetUserByEmail-1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if len(modelUsers) == 0 {
+		return nil, nil
+	}
+
+	return &entity.UserEntity{
+		ID:    modelUsers[0].ID,
+		Name:  modelUsers[0].Name,
+		Email: modelUsers[0].Email,
+		Role:  modelUsers[0].Role,
+	}, nil
+}
+
+// CreateUser implements UserRepository.
+// req.Password must already be hashed.
+func (u *userRepository) CreateUser(ctx context.Context, req entity.UserEntity) (int64, error) {
+	modelUser := model.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+		Role:     req.Role,
+	}
+
+	err := conn(ctx, u.db).Create(&modelUser).Error
+	if err != nil {
+		code := "[REPOSITORY] CreateUser-1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return modelUser.ID, nil
+}
+
+// GetUsers implements UserRepository.
+func (u *userRepository) GetUsers(ctx context.Context, query entity.QueryString) ([]entity.UserEntity, int64, error) {
+	var modelUsers []model.User
+	var countData int64
+
+	if query.Limit <= 0 {
+		query.Limit = 10
+	}
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	sqlMain := conn(ctx, u.db).Model(&model.User{})
+	if query.Search != "" {
+		sqlMain = sqlMain.Where("name ilike ? OR email ilike ?", "%"+query.Search+"%", "%"+query.Search+"%")
+	}
+
+	err = sqlMain.Count(&countData).Error
+	if err != nil {
+		code := "[REPOSITORY] GetUsers-1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	err = sqlMain.
+		Order("created_at desc").
+		Limit(query.Limit).
+		Offset((query.Page - 1) * query.Limit).
+		Find(&modelUsers).Error
+	if err != nil {
+		code := "[REPOSITORY] GetUsers-2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	resps := []entity.UserEntity{}
+	for _, user := range modelUsers {
+		resps = append(resps, entity.UserEntity{
+			ID:    user.ID,
+			Name:  user.Name,
+			Email: user.Email,
+			Role:  user.Role,
+		})
+	}
+
+	return resps, countData, nil
+}
+
 // UpdatePassword implements UserRepository.
 func (u *userRepository) UpdatePassword(ctx context.Context, id int64, newPass string) error {
-	err = u.db.Model(&model.User{}).Where("id = ?", id).Update("password", newPass).Error
+	err = conn(ctx, u.db).Model(&model.User{}).Where("id = ?", id).Update("password", newPass).Error
 	if err != nil {
 		code := "[REPOSITORY] UpdatePassword-1"
 		log.Errorw(code, err)
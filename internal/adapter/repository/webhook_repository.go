@@ -0,0 +1,372 @@
+package repository
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/domain/model"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	GetWebhooks(ctx context.Context, query entity.WebhookQuery) ([]entity.WebhookEntity, int64, error)
+	GetWebhookByID(ctx context.Context, id int64) (*entity.WebhookEntity, error)
+	GetWebhooksForEvent(ctx context.Context, event string) ([]entity.WebhookEntity, error)
+	CreateWebhook(ctx context.Context, req entity.WebhookEntity) (int64, error)
+	UpdateWebhook(ctx context.Context, req entity.WebhookEntity) error
+	DeleteWebhook(ctx context.Context, id int64) error
+
+	GetDeliveries(ctx context.Context, query entity.WebhookDeliveryQuery) ([]entity.WebhookDeliveryEntity, int64, error)
+	GetDeliveryByID(ctx context.Context, webhookID, id int64) (*entity.WebhookDeliveryEntity, error)
+	CreateDeliveries(ctx context.Context, deliveries []entity.WebhookDeliveryEntity) ([]int64, error)
+	ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entity.WebhookDeliveryEntity, error)
+	SaveDeliveryAttempt(ctx context.Context, delivery entity.WebhookDeliveryEntity) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// GetWebhooks implements WebhookRepository.
+func (w *webhookRepository) GetWebhooks(ctx context.Context, query entity.WebhookQuery) ([]entity.WebhookEntity, int64, error) {
+	var modelWebhooks []model.Webhook
+	var countData int64
+
+	sqlMain := conn(ctx, w.db).Model(&model.Webhook{})
+
+	err = sqlMain.Count(&countData).Error
+	if err != nil {
+		code = "[REPOSITORY] GetWebhooks = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	err = sqlMain.Order("id ASC").Limit(query.Limit).Offset(offset).Find(&modelWebhooks).Error
+	if err != nil {
+		code = "[REPOSITORY] GetWebhooks = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	resps := []entity.WebhookEntity{}
+	for _, val := range modelWebhooks {
+		resps = append(resps, toWebhookEntity(val))
+	}
+
+	return resps, countData, nil
+}
+
+// GetWebhookByID implements WebhookRepository.
+func (w *webhookRepository) GetWebhookByID(ctx context.Context, id int64) (*entity.WebhookEntity, error) {
+	var modelWebhook model.Webhook
+
+	err = conn(ctx, w.db).Where("id = ?", id).First(&modelWebhook).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		code = "[REPOSITORY] GetWebhookByID = 1"
+		log.Errorw(code, err)
+		return nil, entity.ErrNotFound
+	}
+
+	if err != nil {
+		code = "[REPOSITORY] GetWebhookByID = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resp := toWebhookEntity(modelWebhook)
+
+	return &resp, nil
+}
+
+// GetWebhooksForEvent implements WebhookRepository.
+// Only active webhooks subscribed to event are returned.
+func (w *webhookRepository) GetWebhooksForEvent(ctx context.Context, event string) ([]entity.WebhookEntity, error) {
+	var modelWebhooks []model.Webhook
+
+	err = conn(ctx, w.db).
+		Where("active AND ? = ANY(STRING_TO_ARRAY(events, ','))", event).
+		Order("id ASC").Find(&modelWebhooks).Error
+	if err != nil {
+		code = "[REPOSITORY] GetWebhooksForEvent = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := []entity.WebhookEntity{}
+	for _, val := range modelWebhooks {
+		resps = append(resps, toWebhookEntity(val))
+	}
+
+	return resps, nil
+}
+
+// CreateWebhook implements WebhookRepository.
+func (w *webhookRepository) CreateWebhook(ctx context.Context, req entity.WebhookEntity) (int64, error) {
+	modelWebhook := model.Webhook{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      strings.Join(req.Events, ","),
+		Active:      req.Active,
+		CreatedByID: req.CreatedByID,
+		CreatedAt:   time.Now(),
+	}
+
+	err = conn(ctx, w.db).Omit("UpdatedAt").Create(&modelWebhook).Error
+	if err != nil {
+		code = "[REPOSITORY] CreateWebhook = 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return modelWebhook.ID, nil
+}
+
+// UpdateWebhook implements WebhookRepository.
+// An empty Secret keeps the current one.
+func (w *webhookRepository) UpdateWebhook(ctx context.Context, req entity.WebhookEntity) error {
+	updates := map[string]interface{}{
+		"url":        req.URL,
+		"events":     strings.Join(req.Events, ","),
+		"active":     req.Active,
+		"updated_at": time.Now(),
+	}
+	if req.Secret != "" {
+		updates["secret"] = req.Secret
+	}
+
+	result := conn(ctx, w.db).Model(&model.Webhook{}).Where("id = ?", req.ID).Updates(updates)
+	if result.Error != nil {
+		code = "[REPOSITORY] UpdateWebhook = 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		code = "[REPOSITORY] UpdateWebhook = 2"
+		log.Errorw(code, entity.ErrNotFound)
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteWebhook implements WebhookRepository.
+// Its deliveries go with it.
+func (w *webhookRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	result := conn(ctx, w.db).Where("id = ?", id).Delete(&model.Webhook{})
+	if result.Error != nil {
+		code = "[REPOSITORY] DeleteWebhook = 1"
+		log.Errorw(code, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		code = "[REPOSITORY] DeleteWebhook = 2"
+		log.Errorw(code, entity.ErrNotFound)
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetDeliveries implements WebhookRepository.
+func (w *webhookRepository) GetDeliveries(ctx context.Context, query entity.WebhookDeliveryQuery) ([]entity.WebhookDeliveryEntity, int64, error) {
+	var modelDeliveries []model.WebhookDelivery
+	var countData int64
+
+	sqlMain := conn(ctx, w.db).Model(&model.WebhookDelivery{}).Where("webhook_id = ?", query.WebhookID)
+	if query.Status != "" {
+		sqlMain = sqlMain.Where("status = ?", query.Status)
+	}
+
+	err = sqlMain.Count(&countData).Error
+	if err != nil {
+		code = "[REPOSITORY] GetDeliveries = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	err = sqlMain.Order("created_at DESC, id DESC").Limit(query.Limit).Offset(offset).Find(&modelDeliveries).Error
+	if err != nil {
+		code = "[REPOSITORY] GetDeliveries = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	resps := []entity.WebhookDeliveryEntity{}
+	for _, val := range modelDeliveries {
+		resps = append(resps, toWebhookDeliveryEntity(val))
+	}
+
+	return resps, countData, nil
+}
+
+// GetDeliveryByID implements WebhookRepository.
+func (w *webhookRepository) GetDeliveryByID(ctx context.Context, webhookID, id int64) (*entity.WebhookDeliveryEntity, error) {
+	var modelDelivery model.WebhookDelivery
+
+	err = conn(ctx, w.db).Where("id = ? AND webhook_id = ?", id, webhookID).First(&modelDelivery).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		code = "[REPOSITORY] GetDeliveryByID = 1"
+		log.Errorw(code, err)
+		return nil, entity.ErrNotFound
+	}
+
+	if err != nil {
+		code = "[REPOSITORY] GetDeliveryByID = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resp := toWebhookDeliveryEntity(modelDelivery)
+
+	return &resp, nil
+}
+
+// CreateDeliveries implements WebhookRepository.
+// It queues the deliveries in one insert and returns their ids in order.
+func (w *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []entity.WebhookDeliveryEntity) ([]int64, error) {
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	modelDeliveries := make([]model.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		modelDeliveries = append(modelDeliveries, model.WebhookDelivery{
+			WebhookID:      delivery.WebhookID,
+			Event:          delivery.Event,
+			Payload:        string(delivery.Payload),
+			Status:         entity.DeliveryPending,
+			NextAttemptAt:  &now,
+			RedeliveryOfID: delivery.RedeliveryOfID,
+			CreatedAt:      now,
+		})
+	}
+
+	err = conn(ctx, w.db).Omit("Webhook", "UpdatedAt").Create(&modelDeliveries).Error
+	if err != nil {
+		code = "[REPOSITORY] CreateDeliveries = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(modelDeliveries))
+	for _, delivery := range modelDeliveries {
+		ids = append(ids, delivery.ID)
+	}
+
+	return ids, nil
+}
+
+// ClaimDeliveries implements WebhookRepository.
+// It picks the pending deliveries due at now whose webhooks are active,
+// oldest first, and moves their next attempt to leaseUntil so no other
+// worker picks them while they are sent; a worker that dies mid-send leaves
+// them to be retried after the lease. Rows another worker is claiming are
+// skipped rather than waited for.
+func (w *webhookRepository) ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entity.WebhookDeliveryEntity, error) {
+	var modelDeliveries []model.WebhookDelivery
+
+	err = conn(ctx, w.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Joins("Webhook").
+			Where(`webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ? AND "Webhook".active`, entity.DeliveryPending, now).
+			Order("webhook_deliveries.next_attempt_at ASC, webhook_deliveries.id ASC").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "webhook_deliveries"}, Options: "SKIP LOCKED"}).
+			Find(&modelDeliveries).Error
+		if err != nil || len(modelDeliveries) == 0 {
+			return err
+		}
+
+		ids := make([]int64, 0, len(modelDeliveries))
+		for _, delivery := range modelDeliveries {
+			ids = append(ids, delivery.ID)
+		}
+
+		return tx.Model(&model.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error
+	})
+	if err != nil {
+		code = "[REPOSITORY] ClaimDeliveries = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	resps := []entity.WebhookDeliveryEntity{}
+	for _, val := range modelDeliveries {
+		resps = append(resps, toWebhookDeliveryEntity(val))
+	}
+
+	return resps, nil
+}
+
+// SaveDeliveryAttempt implements WebhookRepository.
+// It records the outcome of an attempt: the status, attempt count, next
+// attempt and response of delivery.
+func (w *webhookRepository) SaveDeliveryAttempt(ctx context.Context, delivery entity.WebhookDeliveryEntity) error {
+	err = conn(ctx, w.db).Model(&model.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"next_attempt_at": delivery.NextAttemptAt,
+		"response_status": delivery.ResponseStatus,
+		"last_error":      delivery.LastError,
+		"delivered_at":    delivery.DeliveredAt,
+		"updated_at":      time.Now(),
+	}).Error
+	if err != nil {
+		code = "[REPOSITORY] SaveDeliveryAttempt = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+func toWebhookEntity(webhook model.Webhook) entity.WebhookEntity {
+	events := []string{}
+	if webhook.Events != "" {
+		events = strings.Split(webhook.Events, ",")
+	}
+
+	return entity.WebhookEntity{
+		ID:          webhook.ID,
+		URL:         webhook.URL,
+		Secret:      webhook.Secret,
+		Events:      events,
+		Active:      webhook.Active,
+		CreatedByID: webhook.CreatedByID,
+		CreatedAt:   webhook.CreatedAt,
+		UpdatedAt:   webhook.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryEntity(delivery model.WebhookDelivery) entity.WebhookDeliveryEntity {
+	return entity.WebhookDeliveryEntity{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Webhook:        toWebhookEntity(delivery.Webhook),
+		Event:          delivery.Event,
+		Payload:        []byte(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		RedeliveryOfID: delivery.RedeliveryOfID,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
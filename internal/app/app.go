@@ -7,11 +7,16 @@ import (
 	"bwanews/internal/adapter/repository"
 	"bwanews/internal/core/service"
 	"bwanews/lib/auth"
+	"bwanews/lib/live"
+	"bwanews/lib/mail"
 	"bwanews/lib/middleware"
+	"bwanews/lib/oembed"
 	"bwanews/lib/pagination"
+	"bwanews/lib/spam"
 	"context"
 	"log"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -43,30 +48,99 @@ func RunServer() {
 	r2Adapter := cloudflare.NewCloudflareR2Adapter(s3Client, cfg)
 
 	jwt := auth.NewJwt(cfg)
+	readerJwt := auth.NewReaderJwt(cfg)
 	middlewareAuth := middleware.NewMiddleware(cfg)
 
-	_ = pagination.NewPagination()
+	paginate := pagination.NewPagination()
+
+	embedTimeout := time.Duration(cfg.App.EmbedFetchTimeout) * time.Second
+	if embedTimeout <= 0 {
+		embedTimeout = 5 * time.Second
+	}
+	embedRegistry := oembed.NewRegistry(oembed.NewHTTPFetcher(embedTimeout), oembed.DefaultProviders(cfg.App.EmbedFacebookToken)...)
+
+	var mailer mail.Mailer = mail.LogMailer{}
+	if cfg.Mail.SMTPHost != "" {
+		mailer = mail.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUser, cfg.Mail.SMTPPassword, cfg.Mail.From)
+	}
 
 	// Repository
 	authRepo := repository.NewAuthRepository(db.DB)
 	categoryRepo := repository.NewCategoryRepository(db.DB)
 	contentRepo := repository.NewContentRepository(db.DB)
+	contentLockRepo := repository.NewContentLockRepository(db.DB)
+	embedRepo := repository.NewEmbedRepository(db.DB)
+	commentRepo := repository.NewCommentRepository(db.DB)
 	userRepo := repository.NewUserRepository(db.DB)
+	readerRepo := repository.NewReaderRepository(db.DB)
+	libraryRepo := repository.NewLibraryRepository(db.DB)
+	contentStatRepo := repository.NewContentStatRepository(db.DB)
+	placementRepo := repository.NewPlacementRepository(db.DB)
+	liveEntryRepo := repository.NewLiveEntryRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	unitOfWork := repository.NewUnitOfWork(db.DB)
 
 	// Service
 	authService := service.NewAuthService(authRepo, cfg, jwt)
-	categoryService := service.NewCategoryService(categoryRepo)
-	contentService := service.NewContentService(contentRepo, cfg, r2Adapter)
+	webhookService := service.NewWebhookService(webhookRepo, userRepo, cfg)
+	categoryService := service.NewCategoryService(categoryRepo, contentRepo, unitOfWork, webhookService)
+	embedService := service.NewEmbedService(embedRepo, contentRepo, embedRegistry, cfg)
+	contentService := service.NewContentService(contentRepo, contentLockRepo, categoryRepo, userRepo, unitOfWork, cfg, r2Adapter, embedService, webhookService)
+	contentLockService := service.NewContentLockService(contentLockRepo, userRepo, cfg)
 	userService := service.NewUserService(userRepo)
+	readerService := service.NewReaderService(readerRepo, cfg, readerJwt, mailer)
+	libraryService := service.NewLibraryService(libraryRepo, contentRepo)
+	statsService := service.NewStatsService(contentStatRepo, cfg)
+	placementService := service.NewPlacementService(placementRepo, contentRepo, userRepo)
+	liveBlogService := service.NewLiveBlogService(liveEntryRepo, contentRepo, userRepo, live.NewBroker())
+	commentService := service.NewCommentService(commentRepo, userRepo, readerRepo, newSpamChain(cfg, commentRepo), cfg)
+	transferService := service.NewTransferService(categoryRepo, contentRepo, userRepo, unitOfWork, webhookService, cfg)
 
 	// Handler
 	authHandler := handler.NewAuthHandler(authService)
-	categoryHandler := handler.NewCategoryHandler(categoryService)
-	contentHandler := handler.NewContentHandler(contentService)
-	userHandler := handler.NewUserHandler(userService)
+	categoryHandler := handler.NewCategoryHandler(categoryService, paginate)
+	contentHandler := handler.NewContentHandler(contentService, paginate)
+	contentLockHandler := handler.NewContentLockHandler(contentLockService)
+	tagHandler := handler.NewTagHandler(contentService, paginate)
+	feedHandler := handler.NewFeedHandler(contentService, categoryService, cfg)
+	sitemapHandler := handler.NewSitemapHandler(contentService, categoryService, cfg)
+	oembedHandler := handler.NewOEmbedHandler(embedService)
+	userHandler := handler.NewUserHandler(userService, paginate)
+	commentHandler := handler.NewCommentHandler(commentService, paginate)
+	readerHandler := handler.NewReaderHandler(readerService)
+	libraryHandler := handler.NewLibraryHandler(libraryService, paginate)
+	statsHandler := handler.NewStatsHandler(statsService)
+	placementHandler := handler.NewPlacementHandler(placementService, paginate)
+	webhookHandler := handler.NewWebhookHandler(webhookService, paginate)
+
+	// appCtx is the parent of every request context; cancelling it when
+	// the shutdown grace period runs out stops queries and uploads that
+	// outlive it.
+	appCtx, cancelApp := context.WithCancel(context.Background())
+	defer cancelApp()
+
+	statsCtx, stopStats := context.WithCancel(appCtx)
+	defer stopStats()
+	go statsService.Run(statsCtx)
+
+	go embedService.Run(appCtx)
+
+	webhookCtx, stopWebhooks := context.WithCancel(appCtx)
+	defer stopWebhooks()
+	go webhookService.Run(webhookCtx)
+
+	// Live blog streams and exports end with streamCtx; the server waits
+	// for open connections on shutdown, so it is cancelled first.
+	streamCtx, stopStreams := context.WithCancel(appCtx)
+	defer stopStreams()
+	liveBlogHandler := handler.NewLiveBlogHandler(liveBlogService, paginate, streamCtx, cfg)
+	transferHandler := handler.NewTransferHandler(transferService, streamCtx)
 
-	app := fiber.New()
-	app.Use(cors.New())
+	app := fiber.New(serverConfig(cfg))
+	app.Use(middlewareAuth.RequestContext(appCtx, "/api/admin/export", "/api/admin/import"))
+	app.Use(cors.New(cors.Config{
+		ExposeHeaders: "ETag, Link",
+	}))
 	app.Use(recover.New())
 	app.Use(logger.New(logger.Config{
 		Format: "[${time}] ${ip} ${status} -${latency} ${method} ${path}\n",
@@ -83,6 +157,27 @@ func RunServer() {
 		app.Use(swagger.New(cfg))
 	}
 
+	//Feed
+	app.Get("/feed.xml", feedHandler.RSS)
+	app.Get("/atom.xml", feedHandler.Atom)
+	app.Get("/feed.json", feedHandler.JSONFeed)
+	app.Get("/categories/:categorySlug/feed.xml", feedHandler.RSS)
+	app.Get("/categories/:categorySlug/atom.xml", feedHandler.Atom)
+	app.Get("/categories/:categorySlug/feed.json", feedHandler.JSONFeed)
+	app.Get("/tags/:tag/feed.xml", feedHandler.RSS)
+	app.Get("/tags/:tag/atom.xml", feedHandler.Atom)
+	app.Get("/tags/:tag/feed.json", feedHandler.JSONFeed)
+
+	//Sitemap
+	app.Get("/sitemap.xml", sitemapHandler.Index)
+	app.Get("/sitemaps/news.xml", sitemapHandler.News)
+	app.Get("/sitemaps/categories.xml", sitemapHandler.Categories)
+	app.Get("/sitemaps/contents-:page.xml", sitemapHandler.Contents)
+	app.Get("/sitemaps/tags-:page.xml", sitemapHandler.Tags)
+
+	//oEmbed
+	app.Get("/oembed", oembedHandler.OEmbed)
+
 	api := app.Group("/api")
 	api.Post("/login", authHandler.Login)
 
@@ -94,6 +189,7 @@ func RunServer() {
 	categoryApp.Get("/", categoryHandler.GetCategories)
 	categoryApp.Post("/", categoryHandler.CreateCategory)
 	categoryApp.Put("/:categoryID", categoryHandler.EditCategoryByID)
+	categoryApp.Patch("/:categoryID", categoryHandler.PatchCategoryByID)
 	categoryApp.Get("/:categoryID", categoryHandler.GetCategoryByID)
 	categoryApp.Delete("/:categoryID", categoryHandler.DeleteCategory)
 
@@ -102,20 +198,88 @@ func RunServer() {
 	contentApp.Get("/", contentHandler.GetContents)
 	contentApp.Post("/", contentHandler.CreateContent)
 	contentApp.Put("/:contentID", contentHandler.EditContentByID)
+	contentApp.Patch("/:contentID", contentHandler.PatchContentByID)
 	contentApp.Get("/:contentID", contentHandler.GetContentByID)
 	contentApp.Delete("/:contentID", contentHandler.DeleteContent)
 	contentApp.Post("/upload-image", contentHandler.UploadImageR2)
+	contentApp.Post("/bulk", contentHandler.BulkContents)
+	contentApp.Post("/:contentID/lock", contentLockHandler.AcquireLock)
+	contentApp.Put("/:contentID/lock", contentLockHandler.HeartbeatLock)
+	contentApp.Delete("/:contentID/lock", contentLockHandler.ReleaseLock)
+	contentApp.Post("/:contentID/lock/force", contentLockHandler.ForceLock)
+	contentApp.Put("/:contentID/comments", commentHandler.UpdateCommentSettings)
+	contentApp.Get("/:contentID/live-entries", liveBlogHandler.GetEntries)
+	contentApp.Post("/:contentID/live-entries", liveBlogHandler.CreateEntry)
+	contentApp.Put("/:contentID/live-entries/:entryID", liveBlogHandler.UpdateEntry)
+	contentApp.Put("/:contentID/live-entries/:entryID/pin", liveBlogHandler.PinEntry)
+	contentApp.Delete("/:contentID/live-entries/:entryID", liveBlogHandler.DeleteEntry)
+
+	//Comment
+	commentApp := adminApp.Group("/comments")
+	commentApp.Get("/", commentHandler.GetComments)
+	commentApp.Get("/:commentID", commentHandler.GetCommentByID)
+	commentApp.Post("/:commentID/approve", commentHandler.ApproveComment)
+	commentApp.Post("/:commentID/reject", commentHandler.RejectComment)
+	commentApp.Post("/:commentID/spam", commentHandler.SpamComment)
+	commentApp.Delete("/:commentID", commentHandler.DeleteComment)
+
+	//Placement
+	placementApp := adminApp.Group("/placements")
+	placementApp.Get("/", placementHandler.GetPlacements)
+	placementApp.Post("/", placementHandler.CreatePlacement)
+	placementApp.Get("/:placementID", placementHandler.GetPlacementByID)
+	placementApp.Put("/:placementID", placementHandler.UpdatePlacement)
+	placementApp.Delete("/:placementID", placementHandler.DeletePlacement)
+
+	//Webhook
+	webhookApp := adminApp.Group("/webhooks")
+	webhookApp.Get("/", webhookHandler.GetWebhooks)
+	webhookApp.Post("/", webhookHandler.CreateWebhook)
+	webhookApp.Get("/:webhookID", webhookHandler.GetWebhookByID)
+	webhookApp.Put("/:webhookID", webhookHandler.UpdateWebhook)
+	webhookApp.Delete("/:webhookID", webhookHandler.DeleteWebhook)
+	webhookApp.Get("/:webhookID/deliveries", webhookHandler.GetDeliveries)
+	webhookApp.Post("/:webhookID/deliveries/:deliveryID/redeliver", webhookHandler.Redeliver)
 
 	//User
 	userApp := adminApp.Group("/users")
+	userApp.Get("/", userHandler.GetUsers)
 	userApp.Get("/profile", userHandler.GetUserByID)
 	userApp.Put("/update-password", userHandler.UpdatePassword)
 
+	//Transfer
+	adminApp.Get("/export", transferHandler.Export)
+	adminApp.Post("/import", transferHandler.Import)
+
 	//FE
 	feApp := api.Group("/fe")
 	feApp.Get("/categories", categoryHandler.GetCategoryFE)
 	feApp.Get("/contents", contentHandler.GetContentWithQuery)
+	feApp.Get("/contents/trending", statsHandler.GetTrending)
 	feApp.Get("/contents/:contentID", contentHandler.GetContentDetail)
+	feApp.Get("/contents/:contentID/related", contentHandler.GetRelatedContents)
+	feApp.Post("/contents/:contentID/views", middlewareAuth.OptionalReaderToken(), statsHandler.TrackView)
+	feApp.Get("/contents/:contentID/comments", commentHandler.GetContentComments)
+	feApp.Post("/contents/:contentID/comments", middlewareAuth.OptionalReaderToken(), commentHandler.CreateComment)
+	feApp.Get("/contents/:contentID/live-entries", liveBlogHandler.GetEntriesFE)
+	feApp.Get("/contents/:contentID/live-entries/stream", liveBlogHandler.StreamEntries)
+	feApp.Get("/tags", tagHandler.GetTagFE)
+	feApp.Get("/placements/:slot", placementHandler.GetSlotContents)
+
+	//Reader
+	readerApp := feApp.Group("/readers")
+	readerApp.Post("/register", readerHandler.Register)
+	readerApp.Post("/verify-email", readerHandler.VerifyEmail)
+	readerApp.Post("/verify-email/resend", readerHandler.ResendVerification)
+	readerApp.Post("/login", readerHandler.Login)
+	readerApp.Get("/profile", middlewareAuth.CheckReaderToken(), readerHandler.GetProfile)
+	readerApp.Put("/profile", middlewareAuth.CheckReaderToken(), readerHandler.UpdateProfile)
+	readerApp.Put("/update-password", middlewareAuth.CheckReaderToken(), readerHandler.UpdatePassword)
+	readerApp.Get("/bookmarks", middlewareAuth.CheckReaderToken(), libraryHandler.GetBookmarks)
+	readerApp.Put("/bookmarks/:contentID", middlewareAuth.CheckReaderToken(), libraryHandler.CreateBookmark)
+	readerApp.Delete("/bookmarks/:contentID", middlewareAuth.CheckReaderToken(), libraryHandler.DeleteBookmark)
+	readerApp.Get("/history", middlewareAuth.CheckReaderToken(), libraryHandler.GetReadingHistory)
+	readerApp.Put("/history/:contentID", middlewareAuth.CheckReaderToken(), libraryHandler.SaveReadingProgress)
 
 	go func() {
 		if cfg.App.AppPort == "" {
@@ -137,6 +301,66 @@ func RunServer() {
 	log.Println("server shutdown of 5 second")
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	context.AfterFunc(ctx, cancelApp)
 
+	stopStreams()
 	app.ShutdownWithContext(ctx)
+
+	// Views counted since the last flush would be lost otherwise.
+	stopStats()
+	statsService.Flush(ctx)
+
+	// Deliveries cut short are sent again once their lease runs out.
+	stopWebhooks()
+}
+
+// newSpamChain builds the filters every new comment goes through; zero
+// settings fall back to the defaults documented in .env.example.
+func newSpamChain(cfg *config.Config, commentRepo repository.CommentRepository) *spam.Chain {
+	maxLinks := cfg.App.CommentMaxLinks
+	if maxLinks <= 0 {
+		maxLinks = 2
+	}
+
+	rateLimit := cfg.App.CommentRateLimit
+	if rateLimit <= 0 {
+		rateLimit = 5
+	}
+
+	rateWindow := time.Duration(cfg.App.CommentRateWindow) * time.Second
+	if rateWindow <= 0 {
+		rateWindow = 10 * time.Minute
+	}
+
+	return spam.NewChain(
+		spam.Honeypot(),
+		spam.LinkLimit(maxLinks),
+		spam.BannedWords(strings.Split(cfg.App.CommentBannedWords, ",")),
+		spam.Duplicate(commentRepo.CountRecentComments, 24*time.Hour),
+		spam.RateLimitByIP(commentRepo.CountRecentComments, rateLimit, rateWindow),
+		spam.RateLimitByAccount(commentRepo.CountRecentComments, rateLimit, rateWindow),
+	)
+}
+
+// serverConfig reads client addresses from the proxy header only on
+// requests coming from a trusted proxy; without any, c.IP() is always the
+// peer address, so clients cannot pick the address they are limited by.
+func serverConfig(cfg *config.Config) fiber.Config {
+	var proxies []string
+	for _, proxy := range strings.Split(cfg.App.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	if len(proxies) == 0 || cfg.App.ProxyHeader == "" {
+		return fiber.Config{}
+	}
+
+	return fiber.Config{
+		ProxyHeader:             cfg.App.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          proxies,
+		EnableIPValidation:      true,
+	}
 }
@@ -0,0 +1,210 @@
+package app
+
+import (
+	"bwanews/config"
+	"bwanews/internal/adapter/cloudflare"
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/internal/core/service"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"gorm.io/gorm"
+)
+
+// RunExport writes every category and content to output, or to stdout when
+// output is empty.
+func RunExport(format, output string) {
+	transferService := newTransferService()
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			log.Fatalf("Error creating export file: %v", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	reqEntity := entity.ExportOptions{Format: format}
+	if err := transferService.Export(context.Background(), w, reqEntity); err != nil {
+		log.Fatalf("Error exporting: %v", err)
+	}
+}
+
+// RunImport loads input and prints the import report to stdout.
+func RunImport(input string, reqEntity entity.ImportOptions) {
+	transferService := newTransferService()
+
+	file, err := os.Open(input)
+	if err != nil {
+		log.Fatalf("Error opening import file: %v", err)
+	}
+	defer file.Close()
+
+	report, err := transferService.Import(context.Background(), file, reqEntity)
+	if err != nil {
+		log.Fatalf("Error importing: %v", err)
+	}
+
+	for _, item := range report.Items {
+		if item.Action == entity.ImportItemFailed {
+			fmt.Printf("line %d: %s %s: %s\n", item.Line, item.Type, item.Slug, item.Error)
+		}
+	}
+
+	mode := ""
+	if report.DryRun {
+		mode = " (dry run, nothing was written)"
+	}
+
+	fmt.Printf("%d records: %d created, %d updated, %d failed%s\n", report.Total, report.Created, report.Updated, report.Failed, mode)
+}
+
+// RunImportWordPress loads a WXR file, prints the report to stdout and
+// writes the redirect map as CSV to redirects when it is set. Temporary
+// passwords of created accounts go to the passwords file, never to stdout,
+// which tends to end up in logs.
+func RunImportWordPress(input, redirects, passwords string, reqEntity entity.WordPressImportOptions) {
+	cfg := config.NewConfig()
+	db, err := cfg.ConnectionPostgres()
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+
+	s3Client := s3.NewFromConfig(cfg.LoadAwsConfig())
+	r2Adapter := cloudflare.NewCloudflareR2Adapter(s3Client, cfg)
+
+	contentRepo := repository.NewContentRepository(db.DB)
+	userRepo := repository.NewUserRepository(db.DB)
+	unitOfWork := repository.NewUnitOfWork(db.DB)
+	transferService := newTransferServiceWithDB(db.DB, cfg)
+	wordPressService := service.NewWordPressService(transferService, contentRepo, userRepo, unitOfWork, r2Adapter, cfg)
+
+	file, err := os.Open(input)
+	if err != nil {
+		log.Fatalf("Error opening WXR file: %v", err)
+	}
+	defer file.Close()
+
+	report, err := wordPressService.Import(context.Background(), file, reqEntity)
+	if err != nil {
+		log.Fatalf("Error importing: %v", err)
+	}
+
+	writePasswords(passwords, report.Authors)
+
+	uploaded := 0
+	for _, image := range report.Images {
+		switch {
+		case image.Error != "":
+			fmt.Printf("post %d: image %s kept: %s\n", image.PostID, image.Source, image.Error)
+		case !image.Reused:
+			uploaded++
+		}
+	}
+
+	for _, item := range report.Items {
+		if item.Action == entity.ImportItemFailed {
+			fmt.Printf("record %d: %s %s: %s\n", item.Line, item.Type, item.Slug, item.Error)
+		}
+	}
+
+	mode := ""
+	if report.DryRun {
+		mode = " (dry run, nothing was written)"
+	}
+
+	fmt.Printf("%d records: %d created, %d updated, %d failed, %d images uploaded%s\n", report.Total, report.Created, report.Updated, report.Failed, uploaded, mode)
+
+	if redirects == "" {
+		return
+	}
+
+	out, err := os.Create(redirects)
+	if err != nil {
+		log.Fatalf("Error creating redirect map: %v", err)
+	}
+	defer out.Close()
+
+	w := csv.NewWriter(out)
+	w.Write([]string{"from", "to"})
+	for _, redirect := range report.Redirects {
+		w.Write([]string{redirect.From, redirect.To})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Fatalf("Error writing redirect map: %v", err)
+	}
+}
+
+// writePasswords saves the temporary passwords of the created accounts as
+// CSV to a file only its owner can read.
+func writePasswords(passwords string, authors []entity.WordPressAuthorResult) {
+	var created []entity.WordPressAuthorResult
+	for _, author := range authors {
+		if author.Action == entity.WordPressAuthorCreated && author.Password != "" {
+			created = append(created, author)
+		}
+	}
+
+	if len(created) == 0 {
+		return
+	}
+
+	out, err := os.OpenFile(passwords, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		log.Fatalf("Error creating password file: %v", err)
+	}
+	defer out.Close()
+
+	// O_CREATE keeps the mode of an existing file.
+	if err := out.Chmod(0o600); err != nil {
+		log.Fatalf("Error securing password file: %v", err)
+	}
+
+	w := csv.NewWriter(out)
+	w.Write([]string{"email", "password"})
+	for _, author := range created {
+		w.Write([]string{author.Email, author.Password})
+		fmt.Printf("created user %s\n", author.Email)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Fatalf("Error writing password file: %v", err)
+	}
+
+	fmt.Printf("temporary passwords of %d created users written to %s\n", len(created), passwords)
+}
+
+func newTransferService() service.TransferService {
+	cfg := config.NewConfig()
+	db, err := cfg.ConnectionPostgres()
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+
+	return newTransferServiceWithDB(db.DB, cfg)
+}
+
+// newTransferServiceWithDB builds the transfer service of the CLI. The
+// webhook events of an import are only queued here; the server's worker
+// sends them.
+func newTransferServiceWithDB(db *gorm.DB, cfg *config.Config) service.TransferService {
+	categoryRepo := repository.NewCategoryRepository(db)
+	contentRepo := repository.NewContentRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	unitOfWork := repository.NewUnitOfWork(db)
+	webhookService := service.NewWebhookService(webhookRepo, userRepo, cfg)
+
+	return service.NewTransferService(categoryRepo, contentRepo, userRepo, unitOfWork, webhookService, cfg)
+}
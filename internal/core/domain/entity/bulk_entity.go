@@ -0,0 +1,48 @@
+package entity
+
+const (
+	ContentStatusPublish = "PUBLISH"
+	ContentStatusDraft   = "DRAFT"
+	ContentStatusArchive = "ARCHIVE"
+)
+
+const (
+	BulkActionPublish      = "publish"
+	BulkActionDraft        = "draft"
+	BulkActionArchive      = "archive"
+	BulkActionRecategorize = "recategorize"
+	BulkActionRetag        = "retag"
+	BulkActionDelete       = "delete"
+)
+
+const (
+	BulkItemSucceeded  = "succeeded"
+	BulkItemFailed     = "failed"
+	BulkItemRolledBack = "rolled_back"
+	BulkItemSkipped    = "skipped"
+)
+
+type BulkContentEntity struct {
+	IDs        []int64
+	Filter     *QueryString
+	Action     string
+	CategoryID int64
+	Tags       []string
+	Atomic     bool
+	UserID     int64
+}
+
+type BulkItemResult struct {
+	ID     int64
+	Status string
+	Error  string
+}
+
+type BulkContentResult struct {
+	Action    string
+	Atomic    bool
+	Total     int
+	Succeeded int
+	Failed    int
+	Items     []BulkItemResult
+}
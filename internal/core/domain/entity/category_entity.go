@@ -1,8 +1,12 @@
 package entity
 
+import "time"
+
 type CategoryEntity struct {
-	ID    int64
-	Title string
-	Slug  string
-	User  UserEntity
+	ID        int64
+	Title     string
+	Slug      string
+	User      UserEntity
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
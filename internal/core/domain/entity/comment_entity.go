@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+const (
+	CommentStatusPending  = "PENDING"
+	CommentStatusApproved = "APPROVED"
+	CommentStatusRejected = "REJECTED"
+	CommentStatusSpam     = "SPAM"
+)
+
+// CommentEntity is a reader comment on a content. RootID is the top-level
+// comment of the thread and is nil for top-level comments; Replies is only
+// filled on top-level comments served to the front end.
+type CommentEntity struct {
+	ID            int64
+	ContentID     int64
+	ContentTitle  string
+	ParentID      *int64
+	RootID        *int64
+	ReaderID      *int64
+	AuthorName    string
+	AuthorEmail   string
+	Body          string
+	Status        string
+	IPAddress     string
+	UserAgent     string
+	ModeratedByID *int64
+	ModeratedAt   *time.Time
+	CreatedAt     time.Time
+	Replies       []CommentEntity
+
+	// SpamScore and SpamReasons record why the spam filters held a
+	// comment. Honeypot is the hidden form field only bots fill in; it is
+	// checked but never stored.
+	SpamScore   int
+	SpamReasons []string
+	Honeypot    string
+}
+
+// CommentSettingsEntity holds the comment switches of a content. Disabled
+// comments are hidden altogether; closed comments stay visible but accept
+// no new ones.
+type CommentSettingsEntity struct {
+	Enabled bool
+	Closed  bool
+}
+
+// CommentCountQuery counts recent comments for the spam filters; empty
+// members are not filtered on.
+type CommentCountQuery struct {
+	Body      string
+	IPAddress string
+	ReaderID  *int64
+	Since     time.Time
+}
+
+type CommentQuery struct {
+	ContentID int64
+	Status    string
+	Page      int
+	Limit     int
+}
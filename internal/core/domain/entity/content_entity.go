@@ -1,20 +1,62 @@
 package entity
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
+
+// Content types. A live blog carries its coverage as LiveEntryEntity rows
+// next to the usual body, which serves as its summary.
+const (
+	ContentTypeArticle  = "article"
+	ContentTypeLiveBlog = "liveblog"
+)
 
 type ContentEntity struct {
 	ID          int64
 	Title       string
+	Slug        string
 	Excerpt     string
 	Description string
+	BodyFormat  string
+	BodyHTML    string
+	Blocks      json.RawMessage
 	Image       string
 	Tags        []string
 	Status      string
+	Type        string
+	SEO         ContentSEOEntity
+	Comments    CommentSettingsEntity
 	CategoryID  int64
 	CreatedByID int64
 	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Version     int64
 	Category    CategoryEntity
 	User        UserEntity
+	Lock        *ContentLockEntity
+}
+
+// ContentSEOEntity holds the search and social overrides stored with a
+// content. Empty members fall back to the regular content fields.
+type ContentSEOEntity struct {
+	MetaTitle       string
+	MetaDescription string
+	CanonicalURL    string
+	SocialImage     string
+	NoIndex         bool
+}
+
+// ContentMetaEntity is the SEO metadata of a content with every fallback
+// applied, plus its NewsArticle JSON-LD document.
+type ContentMetaEntity struct {
+	Title        string
+	Description  string
+	CanonicalURL string
+	Image        string
+	TwitterCard  string
+	NoIndex      bool
+	JSONLD       []byte
 }
 
 type QueryString struct {
@@ -24,5 +66,44 @@ type QueryString struct {
 	OrderType  string
 	Search     string
 	CategoryID int64
+	Tag        string
 	Status     string
+	Since      time.Time
+	After      *Cursor
+}
+
+// ContentPatch carries a partial update; nil members are left untouched and
+// a pointer to a zero value clears the column.
+type ContentPatch struct {
+	ID          int64
+	Title       *string
+	Excerpt     *string
+	Description *string
+	BodyFormat  *string
+	BodyHTML    *string
+	Blocks      *json.RawMessage
+	Image       *string
+	Tags        *[]string
+	Status      *string
+	Type        *string
+	CategoryID  *int64
+	SEO         ContentSEOPatch
+	UpdatedByID int64
+	Version     int64
+}
+
+// ContentSEOPatch is the SEO part of a ContentPatch.
+type ContentSEOPatch struct {
+	MetaTitle       *string
+	MetaDescription *string
+	CanonicalURL    *string
+	SocialImage     *string
+	NoIndex         *bool
+}
+
+// RelatedContentEntity is a content recommended next to another one, with
+// the score it was ranked by.
+type RelatedContentEntity struct {
+	Content ContentEntity
+	Score   float64
 }
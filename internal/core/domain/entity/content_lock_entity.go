@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+type ContentLockEntity struct {
+	ContentID   int64
+	UserID      int64
+	UserName    string
+	AcquiredAt  time.Time
+	HeartbeatAt time.Time
+	ExpiresAt   time.Time
+}
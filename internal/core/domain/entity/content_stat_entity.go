@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// ContentViewCount is the number of views a content got in the stats
+// bucket starting at BucketStart.
+type ContentViewCount struct {
+	ContentID   int64
+	BucketStart time.Time
+	Views       int64
+}
+
+// TrendingQuery ranks the contents viewed since Since. Every view counts
+// half as much for each HalfLife that passed before Now.
+type TrendingQuery struct {
+	Since    time.Time
+	Now      time.Time
+	HalfLife time.Duration
+	Limit    int
+}
+
+// TrendingContentEntity is a content with its decayed score and the raw
+// number of views in the window.
+type TrendingContentEntity struct {
+	Content ContentEntity
+	Score   float64
+	Views   int64
+}
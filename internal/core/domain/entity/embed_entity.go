@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// EmbedEntity is the oEmbed data of one URL: a third-party URL found in an
+// article body, or one of our own articles served from /oembed.
+type EmbedEntity struct {
+	URL             string
+	Provider        string
+	Type            string
+	Title           string
+	AuthorName      string
+	AuthorURL       string
+	ProviderName    string
+	ProviderURL     string
+	HTML            string
+	Width           int
+	Height          int
+	ThumbnailURL    string
+	ThumbnailWidth  int
+	ThumbnailHeight int
+	FetchedAt       time.Time
+	ExpiresAt       time.Time
+}
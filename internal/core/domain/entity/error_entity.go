@@ -0,0 +1,37 @@
+package entity
+
+import "errors"
+
+var (
+	ErrVersionConflict = errors.New("content was modified by someone else, reload and try again")
+	ErrContentLocked   = errors.New("content is being edited by someone else")
+	ErrLockNotHeld     = errors.New("you do not hold the edit lock for this content")
+	ErrForbidden       = errors.New("you are not allowed to perform this action")
+	ErrNotFound        = errors.New("record not found")
+	ErrBulkRolledBack  = errors.New("bulk operation failed and was rolled back")
+	ErrBulkTooLarge    = errors.New("bulk operation exceeds the maximum number of items")
+	ErrEmptyBulkFilter = errors.New("bulk filter needs at least one of search, status or category_id")
+	ErrUnknownFormat   = errors.New("unknown format, use jsonl or csv")
+	ErrMalformedImport = errors.New("malformed import file")
+	ErrSlugTaken       = errors.New("slug is already used by another content")
+
+	ErrCommentsDisabled = errors.New("comments are disabled for this content")
+	ErrCommentsClosed   = errors.New("comments are closed for this content")
+	ErrInvalidParent    = errors.New("parent comment not found on this content")
+
+	ErrEmailTaken         = errors.New("email is already registered")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrEmailNotVerified   = errors.New("email address is not verified yet")
+	ErrInvalidToken       = errors.New("verification link is invalid or expired")
+	ErrCurrentPassword    = errors.New("current password is incorrect")
+
+	ErrUnknownWindow = errors.New("unknown window, use 1h, 24h or 7d")
+
+	ErrInvalidSlot     = errors.New("invalid slot, use lower-case words and dashes such as hero or pinned:<category-slug>")
+	ErrInvalidSchedule = errors.New("expires_at must be after starts_at")
+
+	ErrNotLiveBlog = errors.New("content is not a live blog")
+
+	ErrUnknownEvent      = errors.New("unknown event, use content.published, content.updated, content.deleted or category.changed")
+	ErrInvalidWebhookURL = errors.New("webhook url must be an absolute http or https url on a public host")
+)
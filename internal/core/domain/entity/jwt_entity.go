@@ -2,6 +2,8 @@ package entity
 
 import "github.com/golang-jwt/jwt/v5"
 
+// JwtData is the claim set of an access token. UserID is a staff user for
+// admin tokens and a reader for reader tokens; the audience tells them apart.
 type JwtData struct {
 	UserID float64 `json:"user_id"`
 	jwt.RegisteredClaims
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// BookmarkEntity is a content a reader saved for later.
+type BookmarkEntity struct {
+	ContentID int64
+	Content   ContentEntity
+	CreatedAt time.Time
+}
+
+// ReadingHistoryEntity records how far a reader got in a content, as a
+// percentage, and when they last read it.
+type ReadingHistoryEntity struct {
+	ContentID int64
+	Content   ContentEntity
+	Progress  int
+	UpdatedAt time.Time
+}
+
+// LibraryQuery pages through a reader's bookmarks or reading history.
+// Unfinished keeps the history down to contents read below 100%.
+type LibraryQuery struct {
+	ReaderID   int64
+	Unfinished bool
+	Page       int
+	Limit      int
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// LiveEntryEntity is a timestamped update of a live blog. Seq grows with
+// every change to any entry and is the event id readers resume from;
+// DeletedAt is only set on entries replayed to a resuming reader.
+type LiveEntryEntity struct {
+	ID          int64
+	ContentID   int64
+	Title       string
+	Body        string
+	Pinned      bool
+	Seq         int64
+	CreatedByID *int64
+	AuthorName  string
+	CreatedAt   time.Time
+	UpdatedAt   *time.Time
+	DeletedAt   *time.Time
+}
+
+// LiveEntryQuery pages through the entries of a live blog, pinned entries
+// first and then newest first. PublishedOnly is set for readers, who only
+// see live blogs that are published.
+type LiveEntryQuery struct {
+	ContentID     int64
+	Page          int
+	Limit         int
+	PublishedOnly bool
+}
@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type Page struct {
 	Page       int
 	Perpage    int
@@ -8,3 +10,8 @@ type Page struct {
 	First      int
 	Last       int
 }
+
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// PlacementEntity puts a content in a named front-end slot. Slots are
+// shown lowest Position first; a placement is live from StartsAt until
+// ExpiresAt, and a nil bound leaves that side open. CreatedByID is nil once
+// the staff member who placed it is deleted.
+type PlacementEntity struct {
+	ID          int64
+	Slot        string
+	ContentID   int64
+	Content     ContentEntity
+	Position    int
+	StartsAt    *time.Time
+	ExpiresAt   *time.Time
+	CreatedByID *int64
+	CreatedAt   time.Time
+	UpdatedAt   *time.Time
+}
+
+// Live reports whether the placement is shown at now.
+func (p PlacementEntity) Live(now time.Time) bool {
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+
+	return p.ExpiresAt == nil || now.Before(*p.ExpiresAt)
+}
+
+// PlacementSchedule is what an edit may change on a placement; moving it
+// to another slot or content is a delete and a new placement.
+type PlacementSchedule struct {
+	Position  int
+	StartsAt  *time.Time
+	ExpiresAt *time.Time
+}
+
+// PlacementQuery pages through placements, in every slot when Slot is
+// empty.
+type PlacementQuery struct {
+	Slot  string
+	Page  int
+	Limit int
+}
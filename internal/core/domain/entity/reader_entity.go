@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// ReaderEntity is a front-end account. Readers are not staff: they sign in
+// with their own endpoints and tokens and never reach the admin API.
+type ReaderEntity struct {
+	ID              int64
+	Name            string
+	Email           string
+	Password        string
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+}
+
+// Verified reports whether the reader confirmed their email address.
+func (r ReaderEntity) Verified() bool {
+	return r.EmailVerifiedAt != nil
+}
+
+// ReaderVerification is a pending email verification; TokenHash is the
+// SHA-256 hash of the token sent to the reader.
+type ReaderVerification struct {
+	TokenHash string
+	ExpiresAt time.Time
+}
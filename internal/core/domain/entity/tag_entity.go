@@ -0,0 +1,6 @@
+package entity
+
+type TagEntity struct {
+	Name  string
+	Total int64
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	TransferFormatJSONL = "jsonl"
+	TransferFormatCSV   = "csv"
+)
+
+const (
+	TransferTypeCategory = "category"
+	TransferTypeContent  = "content"
+)
+
+const (
+	ImportItemCreated = "created"
+	ImportItemUpdated = "updated"
+	ImportItemFailed  = "failed"
+)
+
+// TransferRecord is one exported category or content. Authors travel by
+// email and categories by slug so records survive moving between databases.
+type TransferRecord struct {
+	Type         string
+	Slug         string
+	Title        string
+	Excerpt      string
+	Description  string
+	BodyFormat   string
+	Image        string
+	Tags         []string
+	Status       string
+	CategorySlug string
+	AuthorEmail  string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	Blocks      json.RawMessage
+	ContentType string
+}
+
+type ExportOptions struct {
+	Format string
+	UserID int64
+}
+
+type ImportOptions struct {
+	Format             string
+	DryRun             bool
+	UserID             int64
+	DefaultAuthorEmail string
+}
+
+type ImportItemResult struct {
+	Line   int
+	Type   string
+	Slug   string
+	Action string
+	Error  string
+}
+
+type ImportReport struct {
+	DryRun  bool
+	Total   int
+	Created int
+	Updated int
+	Failed  int
+	Items   []ImportItemResult
+}
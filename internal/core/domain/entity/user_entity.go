@@ -5,4 +5,17 @@ type UserEntity struct {
 	Name     string
 	Email    string
 	Password string
+	Role     string
+}
+
+const (
+	RoleAdmin      = "admin"
+	RoleEditor     = "editor"
+	RoleJournalist = "journalist"
+)
+
+// CanEditOthers reports whether the role may override other staff members'
+// work, such as taking over an edit lock.
+func (u UserEntity) CanEditOthers() bool {
+	return u.Role == RoleAdmin || u.Role == RoleEditor
 }
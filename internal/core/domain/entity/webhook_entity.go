@@ -0,0 +1,72 @@
+package entity
+
+import "time"
+
+const (
+	EventContentPublished = "content.published"
+	EventContentUpdated   = "content.updated"
+	EventContentDeleted   = "content.deleted"
+	EventCategoryChanged  = "category.changed"
+
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookEvents are the events a webhook may subscribe to.
+var WebhookEvents = []string{
+	EventContentPublished,
+	EventContentUpdated,
+	EventContentDeleted,
+	EventCategoryChanged,
+}
+
+// WebhookEntity is an endpoint notified of the Events it subscribes to.
+// Payloads are signed with Secret. CreatedByID is nil once the staff
+// member who added it is deleted.
+type WebhookEntity struct {
+	ID          int64
+	URL         string
+	Secret      string
+	Events      []string
+	Active      bool
+	CreatedByID *int64
+	CreatedAt   time.Time
+	UpdatedAt   *time.Time
+}
+
+// WebhookDeliveryEntity is one event queued for, or sent to, a webhook. A
+// pending delivery is attempted once NextAttemptAt has passed; it ends up
+// succeeded, or failed after the last retry. ResponseStatus and LastError
+// describe the latest attempt. Webhook is only filled in for sending.
+type WebhookDeliveryEntity struct {
+	ID             int64
+	WebhookID      int64
+	Webhook        WebhookEntity
+	Event          string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  *time.Time
+	ResponseStatus *int
+	LastError      string
+	DeliveredAt    *time.Time
+	RedeliveryOfID *int64
+	CreatedAt      time.Time
+	UpdatedAt      *time.Time
+}
+
+// WebhookQuery pages through webhooks, oldest first.
+type WebhookQuery struct {
+	Page  int
+	Limit int
+}
+
+// WebhookDeliveryQuery pages through the deliveries of a webhook, newest
+// first, with the given Status only when it is set.
+type WebhookDeliveryQuery struct {
+	WebhookID int64
+	Status    string
+	Page      int
+	Limit     int
+}
@@ -0,0 +1,43 @@
+package entity
+
+const (
+	WordPressAuthorExisting = "existing"
+	WordPressAuthorCreated  = "created"
+)
+
+type WordPressImportOptions struct {
+	DryRun             bool
+	UploadsDir         string
+	DefaultAuthorEmail string
+}
+
+// WordPressAuthorResult reports how a WordPress author was mapped. Password
+// is only set for accounts created by the import.
+type WordPressAuthorResult struct {
+	Login    string
+	Email    string
+	Action   string
+	Password string
+}
+
+// WordPressImageResult reports a featured image. Reused is set when an
+// earlier run already uploaded it.
+type WordPressImageResult struct {
+	PostID int64
+	Source string
+	URL    string
+	Error  string
+	Reused bool
+}
+
+type Redirect struct {
+	From string
+	To   string
+}
+
+type WordPressImportReport struct {
+	ImportReport
+	Authors   []WordPressAuthorResult
+	Images    []WordPressImageResult
+	Redirects []Redirect
+}
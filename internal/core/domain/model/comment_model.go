@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+type Comment struct {
+	ID            int64      `gorm:"id"`
+	ContentID     int64      `gorm:"content_id"`
+	Content       Content    `gorm:"foreignKey:ContentID"`
+	ParentID      *int64     `gorm:"parent_id"`
+	RootID        *int64     `gorm:"root_id"`
+	ReaderID      *int64     `gorm:"reader_id"`
+	AuthorName    string     `gorm:"author_name"`
+	AuthorEmail   string     `gorm:"author_email"`
+	Body          string     `gorm:"body"`
+	Status        string     `gorm:"status"`
+	IPAddress     string     `gorm:"column:ip_address"`
+	UserAgent     string     `gorm:"user_agent"`
+	ModeratedByID *int64     `gorm:"moderated_by_id"`
+	ModeratedAt   *time.Time `gorm:"moderated_at"`
+	CreatedAt     time.Time  `gorm:"created_at"`
+	UpdatedAt     *time.Time `gorm:"updated_at"`
+
+	SpamScore   int    `gorm:"spam_score"`
+	SpamReasons string `gorm:"spam_reasons"`
+}
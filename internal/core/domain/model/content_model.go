@@ -5,15 +5,30 @@ import "time"
 type Content struct {
 	ID          int64      `gorm:"id"`
 	Title       string     `gorm:"title"`
+	Slug        string     `gorm:"slug"`
 	Excerpt     string     `gorm:"excerpt"`
 	Description string     `gorm:"description"`
+	BodyFormat  string     `gorm:"body_format"`
+	BodyHTML    string     `gorm:"column:body_html"`
+	Blocks      *string    `gorm:"type:jsonb"`
 	Image       string     `gorm:"image"`
 	Tags        string     `gorm:"tags"`
 	Status      string     `gorm:"status"`
+	Type        string     `gorm:"column:content_type"`
 	CategoryID  int64      `gorm:"category_id"`
 	CreatedByID int64      `gorm:"created_by_id"`
 	User        User       `gorm:"foreignKey:CreatedByID"`
 	Category    Category   `gorm:"foreignKey:CategoryID"`
+	Version     int64      `gorm:"version;default:1"`
 	CreatedAt   time.Time  `gorm:"created_at"`
 	UpdatedAt   *time.Time `gorm:"updated_at"`
+
+	MetaTitle       string `gorm:"meta_title"`
+	MetaDescription string `gorm:"meta_description"`
+	CanonicalURL    string `gorm:"canonical_url"`
+	SocialImage     string `gorm:"social_image"`
+	NoIndex         bool   `gorm:"column:noindex"`
+
+	CommentsEnabled bool `gorm:"column:comments_enabled;default:true"`
+	CommentsClosed  bool `gorm:"column:comments_closed"`
 }
@@ -0,0 +1,9 @@
+package model
+
+import "time"
+
+type ContentStat struct {
+	ContentID   int64     `gorm:"content_id"`
+	BucketStart time.Time `gorm:"bucket_start"`
+	Views       int64     `gorm:"views"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type Embed struct {
+	URL             string    `gorm:"primaryKey"`
+	Provider        string    `gorm:"provider"`
+	Type            string    `gorm:"column:type"`
+	Title           string    `gorm:"title"`
+	AuthorName      string    `gorm:"author_name"`
+	AuthorURL       string    `gorm:"author_url"`
+	ProviderName    string    `gorm:"provider_name"`
+	ProviderURL     string    `gorm:"provider_url"`
+	HTML            string    `gorm:"html"`
+	Width           int       `gorm:"width"`
+	Height          int       `gorm:"height"`
+	ThumbnailURL    string    `gorm:"thumbnail_url"`
+	ThumbnailWidth  int       `gorm:"thumbnail_width"`
+	ThumbnailHeight int       `gorm:"thumbnail_height"`
+	FetchedAt       time.Time `gorm:"fetched_at"`
+	ExpiresAt       time.Time `gorm:"expires_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type Bookmark struct {
+	ReaderID  int64     `gorm:"reader_id"`
+	ContentID int64     `gorm:"content_id"`
+	Content   Content   `gorm:"foreignKey:ContentID"`
+	CreatedAt time.Time `gorm:"created_at"`
+}
+
+type ReadingHistory struct {
+	ReaderID  int64     `gorm:"reader_id"`
+	ContentID int64     `gorm:"content_id"`
+	Content   Content   `gorm:"foreignKey:ContentID"`
+	Progress  int       `gorm:"progress"`
+	CreatedAt time.Time `gorm:"created_at"`
+	UpdatedAt time.Time `gorm:"updated_at"`
+}
+
+func (ReadingHistory) TableName() string {
+	return "reading_history"
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type LiveEntry struct {
+	ID          int64      `gorm:"id"`
+	ContentID   int64      `gorm:"content_id"`
+	Title       string     `gorm:"title"`
+	Body        string     `gorm:"body"`
+	Pinned      bool       `gorm:"pinned"`
+	Seq         int64      `gorm:"column:seq;default:nextval('live_entries_seq')"`
+	CreatedByID *int64     `gorm:"created_by_id"`
+	User        User       `gorm:"foreignKey:CreatedByID"`
+	CreatedAt   time.Time  `gorm:"created_at"`
+	UpdatedAt   *time.Time `gorm:"updated_at"`
+	DeletedAt   *time.Time `gorm:"deleted_at"`
+}
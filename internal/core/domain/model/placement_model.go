@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type Placement struct {
+	ID          int64      `gorm:"id"`
+	Slot        string     `gorm:"slot"`
+	ContentID   int64      `gorm:"content_id"`
+	Content     Content    `gorm:"foreignKey:ContentID"`
+	Position    int        `gorm:"position"`
+	StartsAt    *time.Time `gorm:"starts_at"`
+	ExpiresAt   *time.Time `gorm:"expires_at"`
+	CreatedByID *int64     `gorm:"created_by_id"`
+	CreatedAt   time.Time  `gorm:"created_at"`
+	UpdatedAt   *time.Time `gorm:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type Reader struct {
+	ID                    int64      `gorm:"id"`
+	Name                  string     `gorm:"name"`
+	Email                 string     `gorm:"email"`
+	Password              string     `gorm:"password"`
+	EmailVerifiedAt       *time.Time `gorm:"email_verified_at"`
+	VerificationToken     *string    `gorm:"verification_token"`
+	VerificationExpiresAt *time.Time `gorm:"verification_expires_at"`
+	CreatedAt             time.Time  `gorm:"created_at"`
+	UpdatedAt             *time.Time `gorm:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type Webhook struct {
+	ID          int64      `gorm:"id"`
+	URL         string     `gorm:"url"`
+	Secret      string     `gorm:"secret"`
+	Events      string     `gorm:"events"`
+	Active      bool       `gorm:"active"`
+	CreatedByID *int64     `gorm:"created_by_id"`
+	CreatedAt   time.Time  `gorm:"created_at"`
+	UpdatedAt   *time.Time `gorm:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64      `gorm:"id"`
+	WebhookID      int64      `gorm:"webhook_id"`
+	Webhook        Webhook    `gorm:"foreignKey:WebhookID"`
+	Event          string     `gorm:"event"`
+	Payload        string     `gorm:"payload"`
+	Status         string     `gorm:"status"`
+	Attempts       int        `gorm:"attempts"`
+	NextAttemptAt  *time.Time `gorm:"next_attempt_at"`
+	ResponseStatus *int       `gorm:"response_status"`
+	LastError      string     `gorm:"last_error"`
+	DeliveredAt    *time.Time `gorm:"delivered_at"`
+	RedeliveryOfID *int64     `gorm:"redelivery_of_id"`
+	CreatedAt      time.Time  `gorm:"created_at"`
+	UpdatedAt      *time.Time `gorm:"updated_at"`
+}
@@ -8,6 +8,7 @@ import (
 	"bwanews/lib/conv"
 	"context"
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2/log"
@@ -47,7 +48,7 @@ func (a *authService) GetUserByEmail(ctx context.Context, req entity.LoginReques
 		UserID: float64(result.ID),
 		RegisteredClaims: jwt.RegisteredClaims{
 			NotBefore: jwt.NewNumericDate(time.Now().Add(time.Hour * 2)),
-			ID:        string(result.ID),
+			ID:        strconv.FormatInt(result.ID, 10),
 		},
 	}
 
@@ -5,13 +5,15 @@ import (
 	"bwanews/internal/core/domain/entity"
 	"bwanews/lib/conv"
 	"context"
+	"errors"
 
 	"github.com/gofiber/fiber/v2/log"
 )
 
 type CategoryService interface {
-	GetCategories(ctx context.Context) ([]entity.CategoryEntity, error)
+	GetCategories(ctx context.Context, query entity.QueryString) ([]entity.CategoryEntity, int64, error)
 	GetCategoryByID(ctx context.Context, id int64) (*entity.CategoryEntity, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*entity.CategoryEntity, error)
 	CreateCategory(ctx context.Context, req entity.CategoryEntity) error
 	EditCategoryByID(ctx context.Context, req entity.CategoryEntity) error
 	DeleteCategory(ctx context.Context, id int64) error
@@ -19,6 +21,9 @@ type CategoryService interface {
 
 type categoryService struct {
 	categoryRepository repository.CategoryRepository
+	contentRepository  repository.ContentRepository
+	unitOfWork         repository.UnitOfWork
+	events             webhookEvents
 }
 
 // CreateCategory implements CategoryService.
@@ -26,63 +31,127 @@ func (c *categoryService) CreateCategory(ctx context.Context, req entity.Categor
 	slug := conv.GenerateSlug(req.Title)
 	req.Slug = slug
 
-	err := c.categoryRepository.CreateCategory(ctx, req)
+	err := c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		id, err := c.categoryRepository.CreateCategory(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		return c.events.category(ctx, "created", id, nil)
+	})
 	if err != nil {
 		code = "[SERVICE] CreateCategory = 1"
 		log.Errorw(code, err)
 		return err
 	}
+
+	c.events.wake()
+
 	return nil
 }
 
 // DeleteCategory implements CategoryService.
 func (c *categoryService) DeleteCategory(ctx context.Context, id int64) error {
-	err = c.categoryRepository.DeleteCategory(ctx, id)
+	err := c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := c.categoryRepository.LockCategoryByID(ctx, id); err != nil {
+			return err
+		}
+
+		categoryData, err := c.categoryRepository.GetCategoryByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		count, err := c.contentRepository.CountContentsByCategoryID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return errors.New("cannot delete a category that has associated contents")
+		}
+
+		err = c.categoryRepository.DeleteCategory(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return c.events.category(ctx, "deleted", id, categoryData)
+	})
 	if err != nil {
 		code = "[SERVICE] DeleteCategory = 1"
 		log.Errorw(code, err)
 		return err
 	}
 
+	c.events.wake()
+
 	return nil
 }
 
 // EditCategoryByID implements CategoryService.
 func (c *categoryService) EditCategoryByID(ctx context.Context, req entity.CategoryEntity) error {
-	categoryData, err := c.categoryRepository.GetCategoryByID(ctx, req.ID)
+	err := c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := c.categoryRepository.LockCategoryByID(ctx, req.ID); err != nil {
+			return err
+		}
+
+		categoryData, err := c.categoryRepository.GetCategoryByID(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		slug := conv.GenerateSlug(req.Title)
+		if categoryData.Title == req.Title {
+			slug = categoryData.Slug
+		}
+
+		req.Slug = slug
+
+		err = c.categoryRepository.EditCategoryByID(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		return c.events.category(ctx, "updated", req.ID, nil)
+	})
 	if err != nil {
 		code = "[SERVICE] EditCategoryByID = 1"
 		log.Errorw(code, err)
 		return err
 	}
 
-	slug := conv.GenerateSlug(req.Title)
-	if categoryData.Title == req.Title {
-		slug = categoryData.Slug
-	}
+	c.events.wake()
 
-	req.Slug = slug
+	return nil
+}
 
-	err = c.categoryRepository.EditCategoryByID(ctx, req)
+// GetCategories implements CategoryService.
+func (c *categoryService) GetCategories(ctx context.Context, query entity.QueryString) ([]entity.CategoryEntity, int64, error) {
+	results, totalData, err := c.categoryRepository.GetCategories(ctx, query)
 	if err != nil {
-		code = "[SERVICE] EditCategoryByID = 2"
+		code = "[SERVICE] GetCategories = 1"
 		log.Errorw(code, err)
-		return err
+		return nil, 0, err
 	}
 
-	return nil
+	return results, totalData, nil
 }
 
-// GetCategories implements CategoryService.
-func (c *categoryService) GetCategories(ctx context.Context) ([]entity.CategoryEntity, error) {
-	results, err := c.categoryRepository.GetCategories(ctx)
+// GetCategoryBySlug implements CategoryService.
+func (c *categoryService) GetCategoryBySlug(ctx context.Context, slug string) (*entity.CategoryEntity, error) {
+	result, err := c.categoryRepository.GetCategoryBySlug(ctx, slug)
 	if err != nil {
-		code = "[SERVICE] GetCategories = 1"
+		code = "[SERVICE] GetCategoryBySlug = 1"
 		log.Errorw(code, err)
 		return nil, err
 	}
 
-	return results, nil
+	if result == nil {
+		return nil, entity.ErrNotFound
+	}
+
+	return result, nil
 }
 
 // GetCategoryByID implements CategoryService.
@@ -97,8 +166,14 @@ func (c *categoryService) GetCategoryByID(ctx context.Context, id int64) (*entit
 	return result, nil
 }
 
-func NewCategoryService(categoryRepository repository.CategoryRepository) CategoryService {
+func NewCategoryService(categoryRepository repository.CategoryRepository, contentRepository repository.ContentRepository, unitOfWork repository.UnitOfWork, webhookService WebhookService) CategoryService {
 	return &categoryService{
 		categoryRepository: categoryRepository,
+		contentRepository:  contentRepository,
+		unitOfWork:         unitOfWork,
+		events: webhookEvents{
+			categoryRepository: categoryRepository,
+			webhookService:     webhookService,
+		},
 	}
 }
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"bwanews/config"
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/lib/spam"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type CommentService interface {
+	GetComments(ctx context.Context, query entity.CommentQuery) ([]entity.CommentEntity, int64, error)
+	GetCommentByID(ctx context.Context, id int64) (*entity.CommentEntity, error)
+	ModerateComment(ctx context.Context, id int64, status string, userID int64) error
+	DeleteComment(ctx context.Context, id, userID int64) error
+	UpdateCommentSettings(ctx context.Context, contentID int64, settings entity.CommentSettingsEntity, userID int64) error
+
+	GetContentComments(ctx context.Context, query entity.CommentQuery) ([]entity.CommentEntity, int64, error)
+	CreateComment(ctx context.Context, req entity.CommentEntity) (*entity.CommentEntity, error)
+}
+
+type commentService struct {
+	commentRepository repository.CommentRepository
+	userRepository    repository.UserRepository
+	readerRepository  repository.ReaderRepository
+	spamFilter        *spam.Chain
+	autoApprove       bool
+}
+
+// GetComments implements CommentService.
+func (c *commentService) GetComments(ctx context.Context, query entity.CommentQuery) ([]entity.CommentEntity, int64, error) {
+	results, totalData, err := c.commentRepository.GetComments(ctx, query)
+	if err != nil {
+		code = "[SERVICE] GetComments = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	return results, totalData, nil
+}
+
+// GetCommentByID implements CommentService.
+func (c *commentService) GetCommentByID(ctx context.Context, id int64) (*entity.CommentEntity, error) {
+	result, err := c.commentRepository.GetCommentByID(ctx, id)
+	if err != nil {
+		code = "[SERVICE] GetCommentByID = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ModerateComment implements CommentService.
+// Only admins and editors moderate; status is one of the comment statuses.
+func (c *commentService) ModerateComment(ctx context.Context, id int64, status string, userID int64) error {
+	err := c.checkModerator(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] ModerateComment = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = c.commentRepository.UpdateCommentStatus(ctx, id, status, userID)
+	if err != nil {
+		code = "[SERVICE] ModerateComment = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteComment implements CommentService.
+func (c *commentService) DeleteComment(ctx context.Context, id int64, userID int64) error {
+	err := c.checkModerator(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] DeleteComment = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = c.commentRepository.DeleteComment(ctx, id)
+	if err != nil {
+		code = "[SERVICE] DeleteComment = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateCommentSettings implements CommentService.
+func (c *commentService) UpdateCommentSettings(ctx context.Context, contentID int64, settings entity.CommentSettingsEntity, userID int64) error {
+	err := c.checkModerator(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] UpdateCommentSettings = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = c.commentRepository.UpdateCommentSettings(ctx, contentID, settings)
+	if err != nil {
+		code = "[SERVICE] UpdateCommentSettings = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *commentService) checkModerator(ctx context.Context, userID int64) error {
+	user, err := c.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.CanEditOthers() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
+
+// GetContentComments implements CommentService.
+// It pages through the approved top-level comments of a published content
+// and attaches their approved replies, oldest first.
+func (c *commentService) GetContentComments(ctx context.Context, query entity.CommentQuery) ([]entity.CommentEntity, int64, error) {
+	settings, err := c.openSettings(ctx, query.ContentID)
+	if err != nil {
+		code = "[SERVICE] GetContentComments = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	if !settings.Enabled {
+		code = "[SERVICE] GetContentComments = 2"
+		log.Errorw(code, entity.ErrCommentsDisabled)
+		return nil, 0, entity.ErrCommentsDisabled
+	}
+
+	query.Status = entity.CommentStatusApproved
+	results, totalData, err := c.commentRepository.GetRootComments(ctx, query)
+	if err != nil {
+		code = "[SERVICE] GetContentComments = 3"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	rootIDs := []int64{}
+	index := map[int64]int{}
+	for i, comment := range results {
+		rootIDs = append(rootIDs, comment.ID)
+		index[comment.ID] = i
+	}
+
+	replies, err := c.commentRepository.GetReplies(ctx, rootIDs, entity.CommentStatusApproved)
+	if err != nil {
+		code = "[SERVICE] GetContentComments = 4"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	for _, reply := range replies {
+		i := index[*reply.RootID]
+		results[i].Replies = append(results[i].Replies, reply)
+	}
+
+	return results, totalData, nil
+}
+
+// CreateComment implements CommentService.
+// Every comment goes through the spam filters: suspicious ones are held in
+// the moderation queue with the reasons, obvious spam is filed as such and
+// the rest is published, or queued when auto-approval is off. A reply must
+// answer an approved comment of the same content and joins its thread.
+// Comments of signed-in readers carry the name and email of their account.
+func (c *commentService) CreateComment(ctx context.Context, req entity.CommentEntity) (*entity.CommentEntity, error) {
+	settings, err := c.openSettings(ctx, req.ContentID)
+	if err != nil {
+		code = "[SERVICE] CreateComment = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if !settings.Enabled {
+		code = "[SERVICE] CreateComment = 2"
+		log.Errorw(code, entity.ErrCommentsDisabled)
+		return nil, entity.ErrCommentsDisabled
+	}
+
+	if settings.Closed {
+		code = "[SERVICE] CreateComment = 3"
+		log.Errorw(code, entity.ErrCommentsClosed)
+		return nil, entity.ErrCommentsClosed
+	}
+
+	if req.ReaderID != nil {
+		reader, err := c.readerRepository.GetReaderByID(ctx, *req.ReaderID)
+		if err != nil {
+			code = "[SERVICE] CreateComment = 4"
+			log.Errorw(code, err)
+			return nil, err
+		}
+
+		req.AuthorName = reader.Name
+		req.AuthorEmail = reader.Email
+	}
+
+	req.RootID = nil
+	if req.ParentID != nil {
+		parent, err := c.commentRepository.GetCommentByID(ctx, *req.ParentID)
+		if err != nil && !errors.Is(err, entity.ErrNotFound) {
+			code = "[SERVICE] CreateComment = 5"
+			log.Errorw(code, err)
+			return nil, err
+		}
+
+		if parent == nil || parent.ContentID != req.ContentID || parent.Status != entity.CommentStatusApproved {
+			code = "[SERVICE] CreateComment = 6"
+			log.Errorw(code, entity.ErrInvalidParent)
+			return nil, entity.ErrInvalidParent
+		}
+
+		req.RootID = parent.RootID
+		if req.RootID == nil {
+			req.RootID = &parent.ID
+		}
+	}
+
+	req.AuthorName = strings.TrimSpace(req.AuthorName)
+	req.AuthorEmail = strings.ToLower(strings.TrimSpace(req.AuthorEmail))
+	req.Body = strings.TrimSpace(req.Body)
+
+	verdict, err := c.spamFilter.Evaluate(ctx, req)
+	if err != nil {
+		code = "[SERVICE] CreateComment = 7"
+		log.Errorw(code, err)
+		verdict = spam.Verdict{Score: spam.HoldScore, Reasons: []string{"spam check failed"}}
+	}
+
+	cleanStatus := entity.CommentStatusPending
+	if c.autoApprove {
+		cleanStatus = entity.CommentStatusApproved
+	}
+
+	req.Status = verdict.Status(cleanStatus)
+	req.SpamScore = verdict.Score
+	req.SpamReasons = verdict.Reasons
+	req.CreatedAt = time.Now()
+
+	req.ID, err = c.commentRepository.CreateComment(ctx, req)
+	if err != nil {
+		code = "[SERVICE] CreateComment = 8"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// openSettings returns the comment settings of a content readers can see;
+// unknown and unpublished contents are entity.ErrNotFound.
+func (c *commentService) openSettings(ctx context.Context, contentID int64) (*entity.CommentSettingsEntity, error) {
+	settings, status, err := c.commentRepository.GetCommentSettings(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings == nil || status != entity.ContentStatusPublish {
+		return nil, entity.ErrNotFound
+	}
+
+	return settings, nil
+}
+
+func NewCommentService(commentRepo repository.CommentRepository, userRepo repository.UserRepository, readerRepo repository.ReaderRepository, spamFilter *spam.Chain, cfg *config.Config) CommentService {
+	return &commentService{
+		commentRepository: commentRepo,
+		userRepository:    userRepo,
+		readerRepository:  readerRepo,
+		spamFilter:        spamFilter,
+		autoApprove:       cfg.App.CommentAutoApprove,
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bwanews/config"
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"context"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const defaultContentLockTTL = 120
+
+type ContentLockService interface {
+	AcquireLock(ctx context.Context, contentID, userID int64) (*entity.ContentLockEntity, error)
+	HeartbeatLock(ctx context.Context, contentID, userID int64) (*entity.ContentLockEntity, error)
+	ReleaseLock(ctx context.Context, contentID, userID int64) error
+	ForceLock(ctx context.Context, contentID, userID int64) (*entity.ContentLockEntity, error)
+}
+
+type contentLockService struct {
+	contentLockRepository repository.ContentLockRepository
+	userRepository        repository.UserRepository
+	ttl                   int
+}
+
+// AcquireLock implements ContentLockService.
+// When someone else holds the lock it returns their lock together with
+// entity.ErrContentLocked.
+func (c *contentLockService) AcquireLock(ctx context.Context, contentID int64, userID int64) (*entity.ContentLockEntity, error) {
+	acquired, err := c.contentLockRepository.AcquireLock(ctx, contentID, userID, c.ttl)
+	if err != nil {
+		code = "[SERVICE] AcquireLock = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	lock, err := c.contentLockRepository.GetActiveLock(ctx, contentID)
+	if err != nil {
+		code = "[SERVICE] AcquireLock = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if !acquired {
+		return lock, entity.ErrContentLocked
+	}
+
+	return lock, nil
+}
+
+// ForceLock implements ContentLockService.
+func (c *contentLockService) ForceLock(ctx context.Context, contentID int64, userID int64) (*entity.ContentLockEntity, error) {
+	user, err := c.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] ForceLock = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if !user.CanEditOthers() {
+		code = "[SERVICE] ForceLock = 2"
+		log.Errorw(code, entity.ErrForbidden)
+		return nil, entity.ErrForbidden
+	}
+
+	err = c.contentLockRepository.ForceLock(ctx, contentID, userID, c.ttl)
+	if err != nil {
+		code = "[SERVICE] ForceLock = 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return c.contentLockRepository.GetActiveLock(ctx, contentID)
+}
+
+// HeartbeatLock implements ContentLockService.
+func (c *contentLockService) HeartbeatLock(ctx context.Context, contentID int64, userID int64) (*entity.ContentLockEntity, error) {
+	err := c.contentLockRepository.HeartbeatLock(ctx, contentID, userID, c.ttl)
+	if err != nil {
+		code = "[SERVICE] HeartbeatLock = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return c.contentLockRepository.GetActiveLock(ctx, contentID)
+}
+
+// ReleaseLock implements ContentLockService.
+func (c *contentLockService) ReleaseLock(ctx context.Context, contentID int64, userID int64) error {
+	err := c.contentLockRepository.ReleaseLock(ctx, contentID, userID)
+	if err != nil {
+		code = "[SERVICE] ReleaseLock = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+func NewContentLockService(contentLockRepository repository.ContentLockRepository, userRepository repository.UserRepository, cfg *config.Config) ContentLockService {
+	ttl := cfg.App.ContentLockTTL
+	if ttl <= 0 {
+		ttl = defaultContentLockTTL
+	}
+
+	return &contentLockService{
+		contentLockRepository: contentLockRepository,
+		userRepository:        userRepository,
+		ttl:                   ttl,
+	}
+}
@@ -5,29 +5,68 @@ import (
 	"bwanews/internal/adapter/cloudflare"
 	"bwanews/internal/adapter/repository"
 	"bwanews/internal/core/domain/entity"
+	"bwanews/lib/blocks"
+	"bwanews/lib/cache"
+	"bwanews/lib/conv"
+	"bwanews/lib/markup"
+	"bwanews/lib/related"
+	"bwanews/lib/seo"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2/log"
 )
 
+const (
+	maxBulkItems          = 500
+	metaDescriptionLength = 160
+	maxSlugAttempts       = 5
+
+	// Related contents are ranked among relatedCandidates candidates and
+	// the best maxRelated are cached, whatever limit was asked for.
+	relatedCandidates = 200
+	maxRelated        = 20
+	relatedCacheTTL   = 15 * time.Minute
+)
+
 type ContentService interface {
-	GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, int64, error)
+	GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, error)
+	GetContentsByCursor(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, *entity.Cursor, error)
+	GetTags(ctx context.Context, query entity.QueryString) ([]entity.TagEntity, int64, error)
+	GetSitemapContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, error)
 	GetContentByID(ctx context.Context, id int64) (*entity.ContentEntity, error)
+	GetRelatedContents(ctx context.Context, id int64, limit int) ([]entity.RelatedContentEntity, error)
+	ContentMeta(content entity.ContentEntity) (*entity.ContentMetaEntity, error)
+	ContentEmbeds(ctx context.Context, content entity.ContentEntity) ([]entity.EmbedEntity, error)
 	CreateContent(ctx context.Context, req entity.ContentEntity) error
 	EditContentByID(ctx context.Context, req entity.ContentEntity) error
+	PatchContentByID(ctx context.Context, patch entity.ContentPatch) error
 	DeleteContent(ctx context.Context, id int64) error
+	BulkContents(ctx context.Context, req entity.BulkContentEntity) (*entity.BulkContentResult, error)
 	UploadImageR2(ctx context.Context, req entity.FileUploadEntity) (string, error)
 }
 
 type contentService struct {
-	contentRepository repository.ContentRepository
-	cfg               *config.Config
-	r2                cloudflare.CloudflareR2Adapter
+	contentRepository     repository.ContentRepository
+	contentLockRepository repository.ContentLockRepository
+	categoryRepository    repository.CategoryRepository
+	userRepository        repository.UserRepository
+	unitOfWork            repository.UnitOfWork
+	cfg                   *config.Config
+	r2                    cloudflare.CloudflareR2Adapter
+	embedService          EmbedService
+	events                webhookEvents
+	related               *cache.Cache[[]entity.RelatedContentEntity]
 }
 
 // UploadImageR2 implements ContentService.
 func (c *contentService) UploadImageR2(ctx context.Context, req entity.FileUploadEntity) (string, error) {
-	urlImage, err := c.r2.UploadImage(&req)
+	urlImage, err := c.r2.UploadImage(ctx, &req)
 	if err != nil {
 		code = "[SERVICE] UploadImageR2 = 1"
 		log.Errorw(code, err)
@@ -39,37 +78,345 @@ func (c *contentService) UploadImageR2(ctx context.Context, req entity.FileUploa
 
 // CreateContent implements ContentService.
 func (c *contentService) CreateContent(ctx context.Context, req entity.ContentEntity) error {
-	err = c.contentRepository.CreateContent(ctx, req)
+	err = renderBlocks(&req)
 	if err != nil {
 		code = "[SERVICE] CreateContent = 1"
 		log.Errorw(code, err)
 		return err
 	}
 
+	err = renderBody(&req)
+	if err != nil {
+		code = "[SERVICE] CreateContent = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	// Another content can take the slug between the check and the insert;
+	// the insert then fails and a fresh slug is picked.
+	for attempt := 0; attempt < maxSlugAttempts; attempt++ {
+		req.Slug, err = c.uniqueSlug(ctx, conv.GenerateSlug(req.Title))
+		if err != nil {
+			code = "[SERVICE] CreateContent = 3"
+			log.Errorw(code, err)
+			return err
+		}
+
+		err = c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+			id, err := c.contentRepository.CreateContent(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			return c.events.contents(ctx, []int64{id}, nil)
+		})
+		if !errors.Is(err, entity.ErrSlugTaken) {
+			break
+		}
+	}
+	if err != nil {
+		code = "[SERVICE] CreateContent = 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	c.related.Clear()
+	c.events.wake()
+
+	c.embedService.PrefetchEmbeds(c.embedService.ContentEmbedURLs(req))
+
 	return nil
 }
 
+// ContentEmbeds implements ContentService.
+// Only cached embeds are returned; providers are called in the background
+// after saving.
+func (c *contentService) ContentEmbeds(ctx context.Context, content entity.ContentEntity) ([]entity.EmbedEntity, error) {
+	results, err := c.embedService.CachedEmbeds(ctx, c.embedService.ContentEmbedURLs(content))
+	if err != nil {
+		code = "[SERVICE] ContentEmbeds = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// renderBlocks validates a block document and replaces the description with
+// its HTML rendering, so consumers that ignore blocks still get the body.
+func renderBlocks(content *entity.ContentEntity) error {
+	if len(content.Blocks) == 0 || string(content.Blocks) == "null" {
+		content.Blocks = nil
+		return nil
+	}
+
+	doc, err := blocks.Parse(content.Blocks)
+	if err != nil {
+		return err
+	}
+
+	content.Blocks, err = json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	content.Description = blocks.Render(doc)
+	content.BodyFormat = markup.FormatHTML
+	return nil
+}
+
+// renderBody sanitizes the description, or the raw HTML in a Markdown
+// one, and stores its rendering, in its declared format, as BodyHTML.
+func renderBody(content *entity.ContentEntity) error {
+	if content.BodyFormat == "" {
+		content.BodyFormat = markup.FormatHTML
+	}
+
+	content.Description = sanitizeDescription(content.BodyFormat, content.Description)
+
+	bodyHTML, err := markup.Render(content.BodyFormat, content.Description)
+	if err != nil {
+		return err
+	}
+
+	content.BodyHTML = bodyHTML
+	return nil
+}
+
+// sanitizeBody runs a stored body through the sanitizer again before it is
+// served, so rows written before sanitizing existed, or under an older
+// policy, never reach readers unfiltered.
+func sanitizeBody(content *entity.ContentEntity) {
+	content.Description = sanitizeDescription(content.BodyFormat, content.Description)
+
+	if content.BodyHTML != "" {
+		content.BodyHTML = markup.Sanitize(content.BodyHTML)
+		return
+	}
+
+	bodyHTML, err := markup.Render(content.BodyFormat, content.Description)
+	if err != nil {
+		bodyHTML = markup.Sanitize(content.Description)
+	}
+
+	content.BodyHTML = bodyHTML
+}
+
+// sanitizeDescription filters a description written in format. Markdown
+// keeps its syntax and only loses the unsafe parts of its raw HTML.
+func sanitizeDescription(format, description string) string {
+	if format == markup.FormatMarkdown {
+		return markup.SanitizeMarkdown(description)
+	}
+
+	return markup.Sanitize(description)
+}
+
+// uniqueSlug appends -2, -3, ... to slug until no content uses it. Slugs
+// are fixed at creation so edits to the title never break links.
+func (c *contentService) uniqueSlug(ctx context.Context, slug string) (string, error) {
+	candidate := slug
+	for i := 2; ; i++ {
+		existing, err := c.contentRepository.GetContentBySlug(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+
+		if existing == nil {
+			return candidate, nil
+		}
+
+		candidate = fmt.Sprintf("%s-%d", slug, i)
+	}
+}
+
 // DeleteContent implements ContentService.
 func (c *contentService) DeleteContent(ctx context.Context, id int64) error {
-	err = c.contentRepository.DeleteContent(ctx, id)
+	err = c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		before, err := c.events.contentsByID(ctx, []int64{id})
+		if err != nil {
+			return err
+		}
+
+		err = c.contentRepository.DeleteContent(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return c.events.contents(ctx, []int64{id}, before)
+	})
 	if err != nil {
 		code = "[SERVICE] DeleteContent = 1"
 		log.Errorw(code, err)
 		return err
 	}
 
+	c.related.Clear()
+	c.events.wake()
+
 	return nil
 }
 
 // EditContentByID implements ContentService.
 func (c *contentService) EditContentByID(ctx context.Context, req entity.ContentEntity) error {
-	err = c.contentRepository.EditContentByID(ctx, req)
+	err = renderBlocks(&req)
 	if err != nil {
 		code = "[SERVICE] EditContentByID = 1"
 		log.Errorw(code, err)
 		return err
 	}
 
+	err = renderBody(&req)
+	if err != nil {
+		code = "[SERVICE] EditContentByID = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := c.checkEditLock(ctx, req.ID, req.CreatedByID); err != nil {
+			return err
+		}
+
+		before, err := c.events.contentsByID(ctx, []int64{req.ID})
+		if err != nil {
+			return err
+		}
+
+		err = c.contentRepository.EditContentByID(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		return c.events.contents(ctx, []int64{req.ID}, before)
+	})
+	if err != nil {
+		code = "[SERVICE] EditContentByID = 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	c.related.Clear()
+	c.events.wake()
+
+	c.embedService.PrefetchEmbeds(c.embedService.ContentEmbedURLs(req))
+
+	return nil
+}
+
+// PatchContentByID implements ContentService.
+func (c *contentService) PatchContentByID(ctx context.Context, patch entity.ContentPatch) error {
+	if patch.Blocks != nil {
+		content := entity.ContentEntity{Blocks: *patch.Blocks}
+		err := renderBlocks(&content)
+		if err != nil {
+			code = "[SERVICE] PatchContentByID = 1"
+			log.Errorw(code, err)
+			return err
+		}
+
+		patch.Blocks = &content.Blocks
+		if content.Blocks != nil {
+			patch.Description = &content.Description
+			patch.BodyFormat = &content.BodyFormat
+		}
+	}
+
+	if patch.Description != nil || patch.BodyFormat != nil {
+		err := c.patchBody(ctx, &patch)
+		if err != nil {
+			code = "[SERVICE] PatchContentByID = 2"
+			log.Errorw(code, err)
+			return err
+		}
+	}
+
+	err := c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := c.checkEditLock(ctx, patch.ID, patch.UpdatedByID); err != nil {
+			return err
+		}
+
+		before, err := c.events.contentsByID(ctx, []int64{patch.ID})
+		if err != nil {
+			return err
+		}
+
+		err = c.contentRepository.PatchContentByID(ctx, patch)
+		if err != nil {
+			return err
+		}
+
+		return c.events.contents(ctx, []int64{patch.ID}, before)
+	})
+	if err != nil {
+		code = "[SERVICE] PatchContentByID = 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	c.related.Clear()
+	c.events.wake()
+
+	if patch.BodyHTML != nil {
+		content := entity.ContentEntity{BodyHTML: *patch.BodyHTML}
+		if patch.Blocks != nil {
+			content.Blocks = *patch.Blocks
+		}
+
+		c.embedService.PrefetchEmbeds(c.embedService.ContentEmbedURLs(content))
+	}
+
+	return nil
+}
+
+// checkEditLock fails with entity.ErrContentLocked when someone other than
+// userID holds the edit lock of the content. It runs in the unit of work of
+// the write, which keeps the lock from changing hands until it commits.
+func (c *contentService) checkEditLock(ctx context.Context, contentID, userID int64) error {
+	lock, err := c.contentLockRepository.GetActiveLockForWrite(ctx, contentID)
+	if err != nil {
+		return err
+	}
+
+	if lock != nil && lock.UserID != userID {
+		return entity.ErrContentLocked
+	}
+
+	return nil
+}
+
+// patchBody re-renders the body when a patch changes the description or
+// its format, reading whichever of the two the patch leaves untouched.
+func (c *contentService) patchBody(ctx context.Context, patch *entity.ContentPatch) error {
+	content := entity.ContentEntity{}
+	if patch.Description == nil || patch.BodyFormat == nil {
+		current, err := c.contentRepository.GetContentsByIDs(ctx, []int64{patch.ID})
+		if err != nil {
+			return err
+		}
+
+		if len(current) == 0 {
+			return entity.ErrNotFound
+		}
+
+		content = current[0]
+	}
+
+	if patch.Description != nil {
+		content.Description = *patch.Description
+	}
+
+	if patch.BodyFormat != nil {
+		content.BodyFormat = *patch.BodyFormat
+	}
+
+	if err := renderBody(&content); err != nil {
+		return err
+	}
+
+	patch.Description = &content.Description
+	patch.BodyFormat = &content.BodyFormat
+	patch.BodyHTML = &content.BodyHTML
 	return nil
 }
 
@@ -82,25 +429,416 @@ func (c *contentService) GetContentByID(ctx context.Context, id int64) (*entity.
 		return nil, err
 	}
 
+	result.Lock, err = c.contentLockRepository.GetActiveLock(ctx, id)
+	if err != nil {
+		code = "[SERVICE] GetContentByID = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	sanitizeBody(result)
+
 	return result, nil
 }
 
+// GetRelatedContents implements ContentService.
+// Rankings are cached per content. Any content write clears the whole
+// cache, since an edit can move a content in or out of the lists of others.
+func (c *contentService) GetRelatedContents(ctx context.Context, id int64, limit int) ([]entity.RelatedContentEntity, error) {
+	key := strconv.FormatInt(id, 10)
+	results, ok := c.related.Get(key)
+	if !ok {
+		contents, err := c.contentRepository.GetContentsByIDs(ctx, []int64{id})
+		if err != nil {
+			code = "[SERVICE] GetRelatedContents = 1"
+			log.Errorw(code, err)
+			return nil, err
+		}
+
+		if len(contents) == 0 || contents[0].Status != entity.ContentStatusPublish {
+			code = "[SERVICE] GetRelatedContents = 2"
+			log.Errorw(code, entity.ErrNotFound)
+			return nil, entity.ErrNotFound
+		}
+
+		content := contents[0]
+		candidates, err := c.contentRepository.GetRelatedCandidates(ctx, content, relatedCandidates)
+		if err != nil {
+			code = "[SERVICE] GetRelatedContents = 3"
+			log.Errorw(code, err)
+			return nil, err
+		}
+
+		results = related.Rank(content, candidates, time.Now(), maxRelated)
+		c.related.Set(key, results)
+	}
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// ContentMeta implements ContentService.
+// Empty SEO overrides fall back to the title, the excerpt (or the start of
+// the body), the public article URL and the featured image. URL overrides
+// that are not http or https, saved before they were validated, are
+// ignored. Contents that are not published are always noindex.
+func (c *contentService) ContentMeta(content entity.ContentEntity) (*entity.ContentMetaEntity, error) {
+	app := c.cfg.App
+	meta := &entity.ContentMetaEntity{
+		Title:        content.SEO.MetaTitle,
+		Description:  content.SEO.MetaDescription,
+		CanonicalURL: httpURL(content.SEO.CanonicalURL),
+		Image:        httpURL(content.SEO.SocialImage),
+		TwitterCard:  "summary",
+		NoIndex:      content.SEO.NoIndex || content.Status != entity.ContentStatusPublish,
+	}
+
+	if meta.Title == "" {
+		meta.Title = content.Title
+	}
+
+	if meta.Description == "" {
+		meta.Description = plainExcerpt(content.Excerpt, metaDescriptionLength)
+	}
+
+	if meta.Description == "" {
+		meta.Description = plainExcerpt(content.BodyHTML, metaDescriptionLength)
+	}
+
+	if meta.CanonicalURL == "" {
+		meta.CanonicalURL = app.PublicBaseURL + conv.ContentPath(app.ContentPath, content.Category.Slug, content.Slug)
+	}
+
+	if meta.Image == "" {
+		meta.Image = content.Image
+	}
+
+	if meta.Image != "" {
+		meta.TwitterCard = "summary_large_image"
+	}
+
+	publisher := app.SiteName
+	if publisher == "" {
+		publisher = "Bwanews"
+	}
+
+	language := app.SiteLanguage
+	if language == "" {
+		language = "id"
+	}
+
+	jsonLD, err := seo.NewsArticle(seo.Article{
+		URL:           meta.CanonicalURL,
+		Headline:      content.Title,
+		Description:   meta.Description,
+		Images:        []string{meta.Image},
+		Published:     content.CreatedAt,
+		Modified:      content.UpdatedAt,
+		Authors:       []string{content.User.Name},
+		Section:       content.Category.Title,
+		Keywords:      content.Tags,
+		Language:      language,
+		PublisherName: publisher,
+		PublisherLogo: app.SiteLogo,
+	})
+	if err != nil {
+		code = "[SERVICE] ContentMeta = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	meta.JSONLD = jsonLD
+	return meta, nil
+}
+
+// httpURL returns rawURL when it is an absolute http or https URL, and ""
+// otherwise.
+func httpURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+
+	return rawURL
+}
+
 // GetContents implements ContentService.
-func (c *contentService) GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, int64, error) {
-	results, totalData, totalPages, err := c.contentRepository.GetContents(ctx, query)
+func (c *contentService) GetContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, error) {
+	results, totalData, err := c.contentRepository.GetContents(ctx, query)
 	if err != nil {
 		code = "[SERVICE] GetContents = 1"
 		log.Errorw(code, err)
-		return nil, 0, 0, err
+		return nil, 0, err
+	}
+
+	for i := range results {
+		sanitizeBody(&results[i])
+	}
+
+	return results, totalData, nil
+}
+
+// GetContentsByCursor implements ContentService.
+func (c *contentService) GetContentsByCursor(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, *entity.Cursor, error) {
+	results, nextCursor, err := c.contentRepository.GetContentsByCursor(ctx, query)
+	if err != nil {
+		code = "[SERVICE] GetContentsByCursor = 1"
+		log.Errorw(code, err)
+		return nil, nil, err
+	}
+
+	for i := range results {
+		sanitizeBody(&results[i])
+	}
+
+	return results, nextCursor, nil
+}
+
+// BulkContents implements ContentService.
+// Contents are picked by req.IDs or, when req.Filter is set, by the same
+// filters as GetContents. Non-atomic runs apply each item on its own and
+// report per-item failures; atomic runs share one transaction, and on the
+// first failure every applied item is reported as rolled back and the rest
+// as skipped, together with entity.ErrBulkRolledBack.
+func (c *contentService) BulkContents(ctx context.Context, req entity.BulkContentEntity) (*entity.BulkContentResult, error) {
+	user, err := c.userRepository.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		code = "[SERVICE] BulkContents = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if req.Action == entity.BulkActionDelete && !user.CanEditOthers() {
+		code = "[SERVICE] BulkContents = 2"
+		log.Errorw(code, entity.ErrForbidden)
+		return nil, entity.ErrForbidden
+	}
+
+	if req.Action == entity.BulkActionRecategorize {
+		if _, err = c.categoryRepository.GetCategoryByID(ctx, req.CategoryID); err != nil {
+			code = "[SERVICE] BulkContents = 3"
+			log.Errorw(code, err)
+			return nil, fmt.Errorf("category %d: %w", req.CategoryID, entity.ErrNotFound)
+		}
+	}
+
+	// An empty filter would select every content.
+	if req.Filter != nil && req.Filter.Search == "" && req.Filter.Status == "" && req.Filter.CategoryID == 0 {
+		code = "[SERVICE] BulkContents = 4"
+		log.Errorw(code, entity.ErrEmptyBulkFilter)
+		return nil, entity.ErrEmptyBulkFilter
+	}
+
+	ids := req.IDs
+	if req.Filter != nil {
+		ids, err = c.contentRepository.GetContentIDs(ctx, *req.Filter, maxBulkItems+1)
+		if err != nil {
+			code = "[SERVICE] BulkContents = 5"
+			log.Errorw(code, err)
+			return nil, err
+		}
+	}
+
+	ids = uniqueIDs(ids)
+	if len(ids) > maxBulkItems {
+		code = "[SERVICE] BulkContents = 6"
+		log.Errorw(code, entity.ErrBulkTooLarge)
+		return nil, entity.ErrBulkTooLarge
+	}
+
+	contents, err := c.contentRepository.GetContentsByIDs(ctx, ids)
+	if err != nil {
+		code = "[SERVICE] BulkContents = 7"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	owners := make(map[int64]int64, len(contents))
+	before := make(map[int64]entity.ContentEntity, len(contents))
+	for _, content := range contents {
+		owners[content.ID] = content.CreatedByID
+		before[content.ID] = content
+	}
+
+	apply := func(ctx context.Context, id int64) error {
+		ownerID, ok := owners[id]
+		if !ok {
+			return entity.ErrNotFound
+		}
+
+		if ownerID != user.ID && !user.CanEditOthers() {
+			return entity.ErrForbidden
+		}
+
+		if err := c.checkEditLock(ctx, id, user.ID); err != nil {
+			return err
+		}
+
+		if req.Action == entity.BulkActionDelete {
+			err = c.contentRepository.DeleteContent(ctx, id)
+		} else {
+			err = c.contentRepository.PatchContentByID(ctx, bulkPatch(req, id))
+		}
+		if err != nil {
+			return err
+		}
+
+		return c.events.contents(ctx, []int64{id}, before)
+	}
+
+	result := &entity.BulkContentResult{
+		Action: req.Action,
+		Atomic: req.Atomic,
+		Total:  len(ids),
+		Items:  make([]entity.BulkItemResult, 0, len(ids)),
+	}
+
+	// A non-atomic run may apply some items and fail others, so the
+	// rankings are dropped and the queued events sent whatever the outcome.
+	defer c.related.Clear()
+	defer c.events.wake()
+
+	if !req.Atomic {
+		for _, id := range ids {
+			err := c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+				return apply(ctx, id)
+			})
+			if err != nil {
+				result.Failed++
+				result.Items = append(result.Items, entity.BulkItemResult{ID: id, Status: entity.BulkItemFailed, Error: err.Error()})
+				continue
+			}
+
+			result.Succeeded++
+			result.Items = append(result.Items, entity.BulkItemResult{ID: id, Status: entity.BulkItemSucceeded})
+		}
+
+		return result, nil
+	}
+
+	err = c.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		for _, id := range ids {
+			if err := apply(ctx, id); err != nil {
+				result.Items = append(result.Items, entity.BulkItemResult{ID: id, Status: entity.BulkItemFailed, Error: err.Error()})
+				return err
+			}
+
+			result.Items = append(result.Items, entity.BulkItemResult{ID: id, Status: entity.BulkItemSucceeded})
+		}
+
+		return nil
+	})
+	if err != nil {
+		code = "[SERVICE] BulkContents = 8"
+		log.Errorw(code, err)
+
+		done := make(map[int64]bool, len(result.Items))
+		for i := range result.Items {
+			done[result.Items[i].ID] = true
+			if result.Items[i].Status == entity.BulkItemSucceeded {
+				result.Items[i].Status = entity.BulkItemRolledBack
+			}
+		}
+
+		for _, id := range ids {
+			if !done[id] {
+				result.Items = append(result.Items, entity.BulkItemResult{ID: id, Status: entity.BulkItemSkipped})
+			}
+		}
+
+		result.Failed = 1
+		return result, entity.ErrBulkRolledBack
+	}
+
+	result.Succeeded = len(ids)
+	return result, nil
+}
+
+// bulkPatch builds the patch a non-delete bulk action applies to one content.
+func bulkPatch(req entity.BulkContentEntity, id int64) entity.ContentPatch {
+	patch := entity.ContentPatch{
+		ID:          id,
+		UpdatedByID: req.UserID,
+	}
+
+	var status string
+	switch req.Action {
+	case entity.BulkActionPublish:
+		status = entity.ContentStatusPublish
+	case entity.BulkActionDraft:
+		status = entity.ContentStatusDraft
+	case entity.BulkActionArchive:
+		status = entity.ContentStatusArchive
+	case entity.BulkActionRecategorize:
+		patch.CategoryID = &req.CategoryID
+	case entity.BulkActionRetag:
+		patch.Tags = &req.Tags
+	}
+
+	if status != "" {
+		patch.Status = &status
+	}
+
+	return patch
+}
+
+// uniqueIDs drops repeated ids while keeping the first occurrence order.
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
+// GetSitemapContents implements ContentService.
+func (c *contentService) GetSitemapContents(ctx context.Context, query entity.QueryString) ([]entity.ContentEntity, int64, error) {
+	results, totalData, err := c.contentRepository.GetSitemapContents(ctx, query)
+	if err != nil {
+		code = "[SERVICE] GetSitemapContents = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	return results, totalData, nil
+}
+
+// GetTags implements ContentService.
+func (c *contentService) GetTags(ctx context.Context, query entity.QueryString) ([]entity.TagEntity, int64, error) {
+	results, totalData, err := c.contentRepository.GetTags(ctx, query)
+	if err != nil {
+		code = "[SERVICE] GetTags = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
 	}
 
-	return results, totalData, totalPages, nil
+	return results, totalData, nil
 }
 
-func NewContentService(repo repository.ContentRepository, cfg *config.Config, r2 cloudflare.CloudflareR2Adapter) ContentService {
+func NewContentService(repo repository.ContentRepository, lockRepo repository.ContentLockRepository, categoryRepo repository.CategoryRepository, userRepo repository.UserRepository, unitOfWork repository.UnitOfWork, cfg *config.Config, r2 cloudflare.CloudflareR2Adapter, embedService EmbedService, webhookService WebhookService) ContentService {
 	return &contentService{
-		contentRepository: repo,
-		cfg:               cfg,
-		r2:                r2,
+		contentRepository:     repo,
+		contentLockRepository: lockRepo,
+		categoryRepository:    categoryRepo,
+		userRepository:        userRepo,
+		unitOfWork:            unitOfWork,
+		cfg:                   cfg,
+		r2:                    r2,
+		embedService:          embedService,
+		related:               cache.New[[]entity.RelatedContentEntity](relatedCacheTTL),
+		events: webhookEvents{
+			contentRepository:  repo,
+			categoryRepository: categoryRepo,
+			webhookService:     webhookService,
+			cfg:                cfg,
+		},
 	}
 }
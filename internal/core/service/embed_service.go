@@ -0,0 +1,309 @@
+package service
+
+import (
+	"bwanews/config"
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/lib/blocks"
+	"bwanews/lib/conv"
+	"bwanews/lib/oembed"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const (
+	defaultEmbedCacheTTL = 86400
+	maxContentEmbeds     = 20
+
+	// Saved bodies wait in a queue of prefetchQueue for their embeds to be
+	// fetched, each given at most prefetchTimeout.
+	prefetchQueue   = 100
+	prefetchTimeout = 2 * time.Minute
+
+	// Size of the card returned by our own oEmbed endpoint.
+	oembedWidth  = 550
+	oembedHeight = 250
+)
+
+type EmbedService interface {
+	ContentEmbedURLs(content entity.ContentEntity) []string
+	ResolveEmbeds(ctx context.Context, urls []string) ([]entity.EmbedEntity, error)
+	CachedEmbeds(ctx context.Context, urls []string) ([]entity.EmbedEntity, error)
+	ContentOEmbed(ctx context.Context, rawURL string, maxWidth, maxHeight int) (*entity.EmbedEntity, error)
+	PrefetchEmbeds(urls []string)
+	Run(ctx context.Context)
+}
+
+type embedService struct {
+	embedRepository   repository.EmbedRepository
+	contentRepository repository.ContentRepository
+	registry          *oembed.Registry
+	cfg               *config.Config
+	ttl               time.Duration
+	prefetch          chan []string
+}
+
+// ContentEmbedURLs implements EmbedService.
+// It lists the embeddable URLs of a body: standalone links in the HTML and
+// the embed blocks of the block document, at most maxContentEmbeds of them.
+func (e *embedService) ContentEmbedURLs(content entity.ContentEntity) []string {
+	urls := e.registry.FindURLs(content.BodyHTML)
+
+	var doc blocks.Document
+	if len(content.Blocks) > 0 && json.Unmarshal(content.Blocks, &doc) == nil {
+		for _, b := range doc.Blocks {
+			if b.Type != blocks.TypeEmbed {
+				continue
+			}
+
+			if _, ok := e.registry.Match(b.URL); ok && !slices.Contains(urls, b.URL) {
+				urls = append(urls, b.URL)
+			}
+		}
+	}
+
+	if len(urls) > maxContentEmbeds {
+		urls = urls[:maxContentEmbeds]
+	}
+
+	return urls
+}
+
+// PrefetchEmbeds implements EmbedService.
+// It queues urls for Run to fetch and returns at once, so slow providers
+// never hold up the request saving a body. When the queue is full the
+// urls are dropped; they are fetched again on the next save.
+func (e *embedService) PrefetchEmbeds(urls []string) {
+	if len(urls) == 0 {
+		return
+	}
+
+	select {
+	case e.prefetch <- urls:
+	default:
+		code = "[SERVICE] PrefetchEmbeds = 1"
+		log.Errorw(code, fmt.Errorf("embed prefetch queue full, dropping %d urls", len(urls)))
+	}
+}
+
+// Run implements EmbedService.
+// It resolves the queued embeds until ctx is done.
+func (e *embedService) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case urls := <-e.prefetch:
+			fetchCtx, cancel := context.WithTimeout(ctx, prefetchTimeout)
+			_, err := e.ResolveEmbeds(fetchCtx, urls)
+			cancel()
+			if err != nil {
+				code := "[SERVICE] Run = 1"
+				log.Errorw(code, err)
+			}
+		}
+	}
+}
+
+// ResolveEmbeds implements EmbedService.
+// Fresh cached responses are reused; the others are fetched from their
+// provider and cached. A URL whose provider fails keeps its stale response
+// when there is one and is skipped otherwise, so a provider outage never
+// fails the caller.
+func (e *embedService) ResolveEmbeds(ctx context.Context, urls []string) ([]entity.EmbedEntity, error) {
+	cached, err := e.embedRepository.GetEmbeds(ctx, urls)
+	if err != nil {
+		code := "[SERVICE] ResolveEmbeds = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	byURL := map[string]entity.EmbedEntity{}
+	for _, embed := range cached {
+		byURL[embed.URL] = embed
+	}
+
+	now := time.Now()
+	results := []entity.EmbedEntity{}
+	for _, rawURL := range urls {
+		embed, ok := byURL[rawURL]
+		if ok && embed.ExpiresAt.After(now) {
+			results = append(results, embed)
+			continue
+		}
+
+		fetched, err := e.fetchEmbed(ctx, rawURL)
+		if err != nil {
+			code := "[SERVICE] ResolveEmbeds = 2"
+			log.Errorw(code, err)
+			if ok {
+				results = append(results, embed)
+			}
+			continue
+		}
+
+		if err := e.embedRepository.SaveEmbed(ctx, *fetched); err != nil {
+			code := "[SERVICE] ResolveEmbeds = 3"
+			log.Errorw(code, err)
+		}
+
+		results = append(results, *fetched)
+	}
+
+	return results, nil
+}
+
+// fetchEmbed asks the provider of rawURL for its oEmbed response. The
+// response is cached for its cache_age, or the configured TTL without one.
+func (e *embedService) fetchEmbed(ctx context.Context, rawURL string) (*entity.EmbedEntity, error) {
+	provider, resp, err := e.registry.Resolve(ctx, rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rawURL, err)
+	}
+
+	ttl := e.ttl
+	if resp.CacheAge > 0 {
+		ttl = time.Duration(resp.CacheAge) * time.Second
+	}
+
+	now := time.Now()
+	return &entity.EmbedEntity{
+		URL:             rawURL,
+		Provider:        provider.Name,
+		Type:            resp.Type,
+		Title:           resp.Title,
+		AuthorName:      resp.AuthorName,
+		AuthorURL:       resp.AuthorURL,
+		ProviderName:    resp.ProviderName,
+		ProviderURL:     resp.ProviderURL,
+		HTML:            resp.HTML,
+		Width:           int(resp.Width),
+		Height:          int(resp.Height),
+		ThumbnailURL:    resp.ThumbnailURL,
+		ThumbnailWidth:  int(resp.ThumbnailWidth),
+		ThumbnailHeight: int(resp.ThumbnailHeight),
+		FetchedAt:       now,
+		ExpiresAt:       now.Add(ttl),
+	}, nil
+}
+
+// CachedEmbeds implements EmbedService.
+// It never calls a provider, so it is safe on the read path; embeds are
+// fetched when the content is saved.
+func (e *embedService) CachedEmbeds(ctx context.Context, urls []string) ([]entity.EmbedEntity, error) {
+	cached, err := e.embedRepository.GetEmbeds(ctx, urls)
+	if err != nil {
+		code = "[SERVICE] CachedEmbeds = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	byURL := map[string]entity.EmbedEntity{}
+	for _, embed := range cached {
+		byURL[embed.URL] = embed
+	}
+
+	results := []entity.EmbedEntity{}
+	for _, rawURL := range urls {
+		if embed, ok := byURL[rawURL]; ok {
+			results = append(results, embed)
+		}
+	}
+
+	return results, nil
+}
+
+// ContentOEmbed implements EmbedService.
+// rawURL must be the public URL of a published article; anything else is
+// entity.ErrNotFound. A maxWidth or maxHeight of 0 means no limit.
+func (e *embedService) ContentOEmbed(ctx context.Context, rawURL string, maxWidth, maxHeight int) (*entity.EmbedEntity, error) {
+	app := e.cfg.App
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		code = "[SERVICE] ContentOEmbed = 1"
+		log.Errorw(code, err)
+		return nil, entity.ErrNotFound
+	}
+
+	u.RawQuery, u.Fragment = "", ""
+	path, ok := strings.CutPrefix(u.String(), app.PublicBaseURL)
+	if !ok || !strings.HasPrefix(path, "/") {
+		return nil, entity.ErrNotFound
+	}
+
+	slug, ok := conv.SlugFromContentPath(app.ContentPath, path)
+	if !ok {
+		return nil, entity.ErrNotFound
+	}
+
+	content, err := e.contentRepository.GetContentBySlug(ctx, slug)
+	if err != nil {
+		code = "[SERVICE] ContentOEmbed = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if content == nil || content.Status != entity.ContentStatusPublish {
+		return nil, entity.ErrNotFound
+	}
+
+	width, height := oembedWidth, oembedHeight
+	if maxWidth > 0 && maxWidth < width {
+		width = maxWidth
+	}
+
+	if maxHeight > 0 && maxHeight < height {
+		height = maxHeight
+	}
+
+	link := app.PublicBaseURL + conv.ContentPath(app.ContentPath, content.Category.Slug, content.Slug)
+	siteName := app.SiteName
+	if siteName == "" {
+		siteName = "Bwanews"
+	}
+
+	esc := html.EscapeString
+	var card strings.Builder
+	fmt.Fprintf(&card, `<blockquote class="bwanews-embed" style="max-width:%dpx">`, width)
+	fmt.Fprintf(&card, `<p><a href="%s">%s</a></p>`, esc(link), esc(content.Title))
+	if content.Excerpt != "" {
+		card.WriteString("<p>" + esc(content.Excerpt) + "</p>")
+	}
+	card.WriteString("<footer>" + esc(siteName) + "</footer></blockquote>")
+
+	return &entity.EmbedEntity{
+		URL:          link,
+		Type:         "rich",
+		Title:        content.Title,
+		AuthorName:   content.User.Name,
+		ProviderName: siteName,
+		ProviderURL:  app.PublicBaseURL,
+		HTML:         card.String(),
+		Width:        width,
+		Height:       height,
+	}, nil
+}
+
+func NewEmbedService(embedRepo repository.EmbedRepository, contentRepo repository.ContentRepository, registry *oembed.Registry, cfg *config.Config) EmbedService {
+	ttl := cfg.App.EmbedCacheTTL
+	if ttl <= 0 {
+		ttl = defaultEmbedCacheTTL
+	}
+
+	return &embedService{
+		embedRepository:   embedRepo,
+		contentRepository: contentRepo,
+		registry:          registry,
+		cfg:               cfg,
+		ttl:               time.Duration(ttl) * time.Second,
+		prefetch:          make(chan []string, prefetchQueue),
+	}
+}
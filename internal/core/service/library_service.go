@@ -0,0 +1,122 @@
+package service
+
+import (
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"context"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// LibraryService manages a reader's library: their bookmarks and the
+// reading history used to resume articles.
+type LibraryService interface {
+	GetBookmarks(ctx context.Context, query entity.LibraryQuery) ([]entity.BookmarkEntity, int64, error)
+	Bookmark(ctx context.Context, readerID, contentID int64) error
+	RemoveBookmark(ctx context.Context, readerID, contentID int64) error
+	GetReadingHistory(ctx context.Context, query entity.LibraryQuery) ([]entity.ReadingHistoryEntity, int64, error)
+	SaveReadingProgress(ctx context.Context, readerID, contentID int64, progress int) error
+}
+
+type libraryService struct {
+	libraryRepository repository.LibraryRepository
+	contentRepository repository.ContentRepository
+}
+
+// GetBookmarks implements LibraryService.
+func (l *libraryService) GetBookmarks(ctx context.Context, query entity.LibraryQuery) ([]entity.BookmarkEntity, int64, error) {
+	results, totalData, err := l.libraryRepository.GetBookmarks(ctx, query)
+	if err != nil {
+		code = "[SERVICE] GetBookmarks = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	return results, totalData, nil
+}
+
+// Bookmark implements LibraryService.
+// Only published contents can be bookmarked.
+func (l *libraryService) Bookmark(ctx context.Context, readerID, contentID int64) error {
+	err := l.checkPublished(ctx, contentID)
+	if err != nil {
+		code = "[SERVICE] Bookmark = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = l.libraryRepository.CreateBookmark(ctx, readerID, contentID)
+	if err != nil {
+		code = "[SERVICE] Bookmark = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveBookmark implements LibraryService.
+func (l *libraryService) RemoveBookmark(ctx context.Context, readerID, contentID int64) error {
+	err := l.libraryRepository.DeleteBookmark(ctx, readerID, contentID)
+	if err != nil {
+		code = "[SERVICE] RemoveBookmark = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetReadingHistory implements LibraryService.
+func (l *libraryService) GetReadingHistory(ctx context.Context, query entity.LibraryQuery) ([]entity.ReadingHistoryEntity, int64, error) {
+	results, totalData, err := l.libraryRepository.GetReadingHistory(ctx, query)
+	if err != nil {
+		code = "[SERVICE] GetReadingHistory = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	return results, totalData, nil
+}
+
+// SaveReadingProgress implements LibraryService.
+// progress is the percentage read, between 0 and 100.
+func (l *libraryService) SaveReadingProgress(ctx context.Context, readerID, contentID int64, progress int) error {
+	err := l.checkPublished(ctx, contentID)
+	if err != nil {
+		code = "[SERVICE] SaveReadingProgress = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = l.libraryRepository.SaveReadingProgress(ctx, readerID, contentID, min(max(progress, 0), 100))
+	if err != nil {
+		code = "[SERVICE] SaveReadingProgress = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// checkPublished returns entity.ErrNotFound unless the content exists and
+// is published.
+func (l *libraryService) checkPublished(ctx context.Context, contentID int64) error {
+	contents, err := l.contentRepository.GetContentsByIDs(ctx, []int64{contentID})
+	if err != nil {
+		return err
+	}
+
+	if len(contents) == 0 || contents[0].Status != entity.ContentStatusPublish {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
+
+func NewLibraryService(libraryRepo repository.LibraryRepository, contentRepo repository.ContentRepository) LibraryService {
+	return &libraryService{
+		libraryRepository: libraryRepo,
+		contentRepository: contentRepo,
+	}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/lib/live"
+	"bwanews/lib/markup"
+	"context"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// liveChangeBatch caps the changes a stream reads at once; a reader far
+// behind catches up over several reads.
+const liveChangeBatch = 100
+
+type LiveBlogService interface {
+	GetEntries(ctx context.Context, query entity.LiveEntryQuery) ([]entity.LiveEntryEntity, int64, int64, error)
+	CreateEntry(ctx context.Context, req entity.LiveEntryEntity, userID int64) (*entity.LiveEntryEntity, error)
+	UpdateEntry(ctx context.Context, req entity.LiveEntryEntity, userID int64) error
+	PinEntry(ctx context.Context, contentID, id int64, pinned bool, userID int64) error
+	DeleteEntry(ctx context.Context, contentID, id, userID int64) error
+
+	OpenStream(ctx context.Context, contentID int64) (int64, error)
+	Subscribe(contentID int64) (<-chan struct{}, func())
+	GetChanges(ctx context.Context, contentID, seq int64) ([]entity.LiveEntryEntity, error)
+}
+
+type liveBlogService struct {
+	liveEntryRepository repository.LiveEntryRepository
+	contentRepository   repository.ContentRepository
+	userRepository      repository.UserRepository
+	broker              *live.Broker
+}
+
+// GetEntries implements LiveBlogService.
+// Besides the page it returns the id of the last event, from which a
+// reader that rendered the page resumes the stream. It is read before the
+// entries so a change made in between is sent again rather than missed.
+func (l *liveBlogService) GetEntries(ctx context.Context, query entity.LiveEntryQuery) ([]entity.LiveEntryEntity, int64, int64, error) {
+	_, err := l.liveBlog(ctx, query.ContentID, query.PublishedOnly)
+	if err != nil {
+		code = "[SERVICE] GetEntries = 1"
+		log.Errorw(code, err)
+		return nil, 0, 0, err
+	}
+
+	lastSeq, err := l.liveEntryRepository.GetLastLiveSeq(ctx, query.ContentID)
+	if err != nil {
+		code = "[SERVICE] GetEntries = 2"
+		log.Errorw(code, err)
+		return nil, 0, 0, err
+	}
+
+	results, totalData, err := l.liveEntryRepository.GetLiveEntries(ctx, query)
+	if err != nil {
+		code = "[SERVICE] GetEntries = 3"
+		log.Errorw(code, err)
+		return nil, 0, 0, err
+	}
+
+	return results, totalData, lastSeq, nil
+}
+
+// CreateEntry implements LiveBlogService.
+// Any staff member may post to a live blog, published or not.
+func (l *liveBlogService) CreateEntry(ctx context.Context, req entity.LiveEntryEntity, userID int64) (*entity.LiveEntryEntity, error) {
+	_, err := l.liveBlog(ctx, req.ContentID, false)
+	if err != nil {
+		code = "[SERVICE] CreateEntry = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	req.Body = markup.Sanitize(req.Body)
+	req.CreatedByID = &userID
+	id, err := l.liveEntryRepository.CreateLiveEntry(ctx, req)
+	if err != nil {
+		code = "[SERVICE] CreateEntry = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	l.broker.Publish(req.ContentID)
+
+	result, err := l.liveEntryRepository.GetLiveEntryByID(ctx, req.ContentID, id)
+	if err != nil {
+		code = "[SERVICE] CreateEntry = 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateEntry implements LiveBlogService.
+func (l *liveBlogService) UpdateEntry(ctx context.Context, req entity.LiveEntryEntity, userID int64) error {
+	err := l.checkEntryEditor(ctx, req.ContentID, req.ID, userID)
+	if err != nil {
+		code = "[SERVICE] UpdateEntry = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	req.Body = markup.Sanitize(req.Body)
+	err = l.liveEntryRepository.UpdateLiveEntry(ctx, req)
+	if err != nil {
+		code = "[SERVICE] UpdateEntry = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	l.broker.Publish(req.ContentID)
+
+	return nil
+}
+
+// PinEntry implements LiveBlogService.
+func (l *liveBlogService) PinEntry(ctx context.Context, contentID, id int64, pinned bool, userID int64) error {
+	err := l.checkEntryEditor(ctx, contentID, id, userID)
+	if err != nil {
+		code = "[SERVICE] PinEntry = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = l.liveEntryRepository.PinLiveEntry(ctx, contentID, id, pinned)
+	if err != nil {
+		code = "[SERVICE] PinEntry = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	l.broker.Publish(contentID)
+
+	return nil
+}
+
+// DeleteEntry implements LiveBlogService.
+func (l *liveBlogService) DeleteEntry(ctx context.Context, contentID, id, userID int64) error {
+	err := l.checkEntryEditor(ctx, contentID, id, userID)
+	if err != nil {
+		code = "[SERVICE] DeleteEntry = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = l.liveEntryRepository.DeleteLiveEntry(ctx, contentID, id)
+	if err != nil {
+		code = "[SERVICE] DeleteEntry = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	l.broker.Publish(contentID)
+
+	return nil
+}
+
+// OpenStream implements LiveBlogService.
+// It checks that readers may follow the live blog and returns the id of
+// its last event, where a reader that does not resume starts.
+func (l *liveBlogService) OpenStream(ctx context.Context, contentID int64) (int64, error) {
+	_, err := l.liveBlog(ctx, contentID, true)
+	if err != nil {
+		code = "[SERVICE] OpenStream = 1"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	lastSeq, err := l.liveEntryRepository.GetLastLiveSeq(ctx, contentID)
+	if err != nil {
+		code = "[SERVICE] OpenStream = 2"
+		log.Errorw(code, err)
+		return 0, err
+	}
+
+	return lastSeq, nil
+}
+
+// Subscribe implements LiveBlogService.
+func (l *liveBlogService) Subscribe(contentID int64) (<-chan struct{}, func()) {
+	return l.broker.Subscribe(contentID)
+}
+
+// GetChanges implements LiveBlogService.
+// Changes come in commit order, so a stream resuming after seq misses none.
+func (l *liveBlogService) GetChanges(ctx context.Context, contentID, seq int64) ([]entity.LiveEntryEntity, error) {
+	results, err := l.liveEntryRepository.GetLiveEntriesSince(ctx, contentID, seq, liveChangeBatch)
+	if err != nil {
+		code = "[SERVICE] GetChanges = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Body = markup.Sanitize(results[i].Body)
+	}
+
+	return results, nil
+}
+
+// liveBlog loads a content that must be a live blog and, for readers,
+// published.
+func (l *liveBlogService) liveBlog(ctx context.Context, contentID int64, publishedOnly bool) (*entity.ContentEntity, error) {
+	contents, err := l.contentRepository.GetContentsByIDs(ctx, []int64{contentID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(contents) == 0 || (publishedOnly && contents[0].Status != entity.ContentStatusPublish) {
+		return nil, entity.ErrNotFound
+	}
+
+	if contents[0].Type != entity.ContentTypeLiveBlog {
+		return nil, entity.ErrNotLiveBlog
+	}
+
+	return &contents[0], nil
+}
+
+// checkEntryEditor fails with ErrForbidden unless the staff member wrote
+// the entry or may edit the work of others.
+func (l *liveBlogService) checkEntryEditor(ctx context.Context, contentID, id, userID int64) error {
+	entry, err := l.liveEntryRepository.GetLiveEntryByID(ctx, contentID, id)
+	if err != nil {
+		return err
+	}
+
+	if entry.CreatedByID != nil && *entry.CreatedByID == userID {
+		return nil
+	}
+
+	user, err := l.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.CanEditOthers() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
+
+func NewLiveBlogService(liveEntryRepo repository.LiveEntryRepository, contentRepo repository.ContentRepository, userRepo repository.UserRepository, broker *live.Broker) LiveBlogService {
+	return &liveBlogService{
+		liveEntryRepository: liveEntryRepo,
+		contentRepository:   contentRepo,
+		userRepository:      userRepo,
+		broker:              broker,
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// slotPattern accepts slot names such as hero, breaking-news or
+// pinned:technology, the part after the colon naming a category slug.
+var slotPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*(:[a-z0-9]+(-[a-z0-9]+)*)?$`)
+
+type PlacementService interface {
+	GetPlacements(ctx context.Context, query entity.PlacementQuery) ([]entity.PlacementEntity, int64, error)
+	GetPlacementByID(ctx context.Context, id int64) (*entity.PlacementEntity, error)
+	CreatePlacement(ctx context.Context, req entity.PlacementEntity, userID int64) (*entity.PlacementEntity, error)
+	UpdatePlacement(ctx context.Context, id int64, schedule entity.PlacementSchedule, userID int64) error
+	DeletePlacement(ctx context.Context, id, userID int64) error
+
+	GetSlotContents(ctx context.Context, slot string, limit int) ([]entity.PlacementEntity, error)
+}
+
+type placementService struct {
+	placementRepository repository.PlacementRepository
+	contentRepository   repository.ContentRepository
+	userRepository      repository.UserRepository
+}
+
+// GetPlacements implements PlacementService.
+func (p *placementService) GetPlacements(ctx context.Context, query entity.PlacementQuery) ([]entity.PlacementEntity, int64, error) {
+	results, totalData, err := p.placementRepository.GetPlacements(ctx, query)
+	if err != nil {
+		code = "[SERVICE] GetPlacements = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	return results, totalData, nil
+}
+
+// GetPlacementByID implements PlacementService.
+func (p *placementService) GetPlacementByID(ctx context.Context, id int64) (*entity.PlacementEntity, error) {
+	result, err := p.placementRepository.GetPlacementByID(ctx, id)
+	if err != nil {
+		code = "[SERVICE] GetPlacementByID = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreatePlacement implements PlacementService.
+// Only admins and editors curate slots. Unpublished contents may be placed
+// ahead of time; the front end only shows them once published.
+func (p *placementService) CreatePlacement(ctx context.Context, req entity.PlacementEntity, userID int64) (*entity.PlacementEntity, error) {
+	err := p.checkCurator(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] CreatePlacement = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if !slotPattern.MatchString(req.Slot) {
+		code = "[SERVICE] CreatePlacement = 2"
+		log.Errorw(code, entity.ErrInvalidSlot)
+		return nil, entity.ErrInvalidSlot
+	}
+
+	err = checkSchedule(req.StartsAt, req.ExpiresAt)
+	if err != nil {
+		code = "[SERVICE] CreatePlacement = 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	contents, err := p.contentRepository.GetContentsByIDs(ctx, []int64{req.ContentID})
+	if err != nil {
+		code = "[SERVICE] CreatePlacement = 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if len(contents) == 0 {
+		code = "[SERVICE] CreatePlacement = 5"
+		log.Errorw(code, entity.ErrNotFound)
+		return nil, fmt.Errorf("content %d: %w", req.ContentID, entity.ErrNotFound)
+	}
+
+	req.CreatedByID = &userID
+	id, err := p.placementRepository.CreatePlacement(ctx, req)
+	if err != nil {
+		code = "[SERVICE] CreatePlacement = 6"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	result, err := p.placementRepository.GetPlacementByID(ctx, id)
+	if err != nil {
+		code = "[SERVICE] CreatePlacement = 7"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdatePlacement implements PlacementService.
+func (p *placementService) UpdatePlacement(ctx context.Context, id int64, schedule entity.PlacementSchedule, userID int64) error {
+	err := p.checkCurator(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] UpdatePlacement = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = checkSchedule(schedule.StartsAt, schedule.ExpiresAt)
+	if err != nil {
+		code = "[SERVICE] UpdatePlacement = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = p.placementRepository.UpdatePlacement(ctx, id, schedule)
+	if err != nil {
+		code = "[SERVICE] UpdatePlacement = 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// DeletePlacement implements PlacementService.
+func (p *placementService) DeletePlacement(ctx context.Context, id, userID int64) error {
+	err := p.checkCurator(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] DeletePlacement = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = p.placementRepository.DeletePlacement(ctx, id)
+	if err != nil {
+		code = "[SERVICE] DeletePlacement = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetSlotContents implements PlacementService.
+// An unknown slot is not an error; it is simply empty.
+func (p *placementService) GetSlotContents(ctx context.Context, slot string, limit int) ([]entity.PlacementEntity, error) {
+	if !slotPattern.MatchString(slot) {
+		code = "[SERVICE] GetSlotContents = 1"
+		log.Errorw(code, entity.ErrInvalidSlot)
+		return nil, entity.ErrInvalidSlot
+	}
+
+	results, err := p.placementRepository.GetSlotContents(ctx, slot, time.Now(), limit)
+	if err != nil {
+		code = "[SERVICE] GetSlotContents = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// checkCurator fails with ErrForbidden unless the staff member may curate
+// the front page.
+func (p *placementService) checkCurator(ctx context.Context, userID int64) error {
+	user, err := p.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.CanEditOthers() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
+
+// checkSchedule fails when a placement would expire before it starts.
+func checkSchedule(startsAt, expiresAt *time.Time) error {
+	if startsAt != nil && expiresAt != nil && !expiresAt.After(*startsAt) {
+		return entity.ErrInvalidSchedule
+	}
+
+	return nil
+}
+
+func NewPlacementService(placementRepo repository.PlacementRepository, contentRepo repository.ContentRepository, userRepo repository.UserRepository) PlacementService {
+	return &placementService{
+		placementRepository: placementRepo,
+		contentRepository:   contentRepo,
+		userRepository:      userRepo,
+	}
+}
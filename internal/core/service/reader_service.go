@@ -0,0 +1,324 @@
+package service
+
+import (
+	"bwanews/config"
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/lib/auth"
+	"bwanews/lib/conv"
+	"bwanews/lib/mail"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const defaultReaderVerifyTTL = 172800
+
+type ReaderService interface {
+	Register(ctx context.Context, req entity.ReaderEntity) (*entity.ReaderEntity, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	Login(ctx context.Context, req entity.LoginRequest) (*entity.AccessToken, error)
+	GetReaderByID(ctx context.Context, id int64) (*entity.ReaderEntity, error)
+	UpdateProfile(ctx context.Context, id int64, name string) error
+	UpdatePassword(ctx context.Context, id int64, currentPass, newPass string) error
+}
+
+type readerService struct {
+	readerRepository repository.ReaderRepository
+	cfg              *config.Config
+	jwtToken         auth.Jwt
+	mailer           mail.Mailer
+	verifyTTL        time.Duration
+}
+
+// Register implements ReaderService.
+// The account cannot sign in until the reader follows the link emailed to
+// them. A failed email is only logged: the reader can ask for a new one.
+func (r *readerService) Register(ctx context.Context, req entity.ReaderEntity) (*entity.ReaderEntity, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+
+	existing, err := r.readerRepository.GetReaderByEmail(ctx, req.Email)
+	if err != nil {
+		code = "[SERVICE] Register = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if existing != nil {
+		code = "[SERVICE] Register = 2"
+		log.Errorw(code, entity.ErrEmailTaken)
+		return nil, entity.ErrEmailTaken
+	}
+
+	req.Password, err = conv.HashPassword(req.Password)
+	if err != nil {
+		code = "[SERVICE] Register = 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	token, verification, err := r.newVerification()
+	if err != nil {
+		code = "[SERVICE] Register = 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	req.ID, err = r.readerRepository.CreateReader(ctx, req, verification)
+	if err != nil {
+		code = "[SERVICE] Register = 5"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	err = r.sendVerification(ctx, req, token)
+	if err != nil {
+		code = "[SERVICE] Register = 6"
+		log.Errorw(code, err)
+	}
+
+	req.Password = ""
+	req.CreatedAt = time.Now()
+
+	return &req, nil
+}
+
+// VerifyEmail implements ReaderService.
+func (r *readerService) VerifyEmail(ctx context.Context, token string) error {
+	reader, err := r.readerRepository.GetReaderByVerification(ctx, hashToken(token))
+	if err != nil {
+		code = "[SERVICE] VerifyEmail = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if reader == nil {
+		code = "[SERVICE] VerifyEmail = 2"
+		log.Errorw(code, entity.ErrInvalidToken)
+		return entity.ErrInvalidToken
+	}
+
+	err = r.readerRepository.MarkEmailVerified(ctx, reader.ID)
+	if err != nil {
+		code = "[SERVICE] VerifyEmail = 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// ResendVerification implements ReaderService.
+// Unknown and already verified addresses are ignored without an error, so
+// the endpoint does not reveal who has an account.
+func (r *readerService) ResendVerification(ctx context.Context, email string) error {
+	reader, err := r.readerRepository.GetReaderByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		code = "[SERVICE] ResendVerification = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if reader == nil || reader.Verified() {
+		return nil
+	}
+
+	token, verification, err := r.newVerification()
+	if err != nil {
+		code = "[SERVICE] ResendVerification = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = r.readerRepository.UpdateVerification(ctx, reader.ID, verification)
+	if err != nil {
+		code = "[SERVICE] ResendVerification = 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = r.sendVerification(ctx, *reader, token)
+	if err != nil {
+		code = "[SERVICE] ResendVerification = 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// Login implements ReaderService.
+// It issues a reader token, which the admin API does not accept.
+func (r *readerService) Login(ctx context.Context, req entity.LoginRequest) (*entity.AccessToken, error) {
+	reader, err := r.readerRepository.GetReaderByEmail(ctx, strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil {
+		code = "[SERVICE] Login = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if reader == nil || !conv.CheckPasswordHash(req.Password, reader.Password) {
+		code = "[SERVICE] Login = 2"
+		log.Errorw(code, entity.ErrInvalidCredentials)
+		return nil, entity.ErrInvalidCredentials
+	}
+
+	if !reader.Verified() {
+		code = "[SERVICE] Login = 3"
+		log.Errorw(code, entity.ErrEmailNotVerified)
+		return nil, entity.ErrEmailNotVerified
+	}
+
+	jwtData := entity.JwtData{
+		UserID: float64(reader.ID),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID: strconv.FormatInt(reader.ID, 10),
+		},
+	}
+
+	accessToken, expiresAt, err := r.jwtToken.GenerateToken(&jwtData)
+	if err != nil {
+		code = "[SERVICE] Login = 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return &entity.AccessToken{
+		AccessToken: accessToken,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// GetReaderByID implements ReaderService.
+func (r *readerService) GetReaderByID(ctx context.Context, id int64) (*entity.ReaderEntity, error) {
+	result, err := r.readerRepository.GetReaderByID(ctx, id)
+	if err != nil {
+		code = "[SERVICE] GetReaderByID = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateProfile implements ReaderService.
+func (r *readerService) UpdateProfile(ctx context.Context, id int64, name string) error {
+	err := r.readerRepository.UpdateReaderProfile(ctx, id, strings.TrimSpace(name))
+	if err != nil {
+		code = "[SERVICE] UpdateProfile = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdatePassword implements ReaderService.
+// The current password must be confirmed first.
+func (r *readerService) UpdatePassword(ctx context.Context, id int64, currentPass, newPass string) error {
+	current, err := r.readerRepository.GetReaderPassword(ctx, id)
+	if err != nil {
+		code = "[SERVICE] UpdatePassword = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if !conv.CheckPasswordHash(currentPass, current) {
+		code = "[SERVICE] UpdatePassword = 2"
+		log.Errorw(code, entity.ErrCurrentPassword)
+		return entity.ErrCurrentPassword
+	}
+
+	password, err := conv.HashPassword(newPass)
+	if err != nil {
+		code = "[SERVICE] UpdatePassword = 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = r.readerRepository.UpdateReaderPassword(ctx, id, password)
+	if err != nil {
+		code = "[SERVICE] UpdatePassword = 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// newVerification returns a random token for the email and the
+// verification to store, which only keeps its hash.
+func (r *readerService) newVerification() (string, entity.ReaderVerification, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", entity.ReaderVerification{}, err
+	}
+
+	token := hex.EncodeToString(b)
+	return token, entity.ReaderVerification{
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(r.verifyTTL),
+	}, nil
+}
+
+func (r *readerService) sendVerification(ctx context.Context, reader entity.ReaderEntity, token string) error {
+	app := r.cfg.App
+	link := app.ReaderVerifyURL
+	if link == "" {
+		link = app.PublicBaseURL + "/verify-email"
+	}
+
+	separator := "?"
+	if strings.Contains(link, "?") {
+		separator = "&"
+	}
+	link += separator + "token=" + url.QueryEscape(token)
+
+	siteName := app.SiteName
+	if siteName == "" {
+		siteName = "Bwanews"
+	}
+
+	expiresIn := fmt.Sprintf("%d hours", int(r.verifyTTL.Hours()))
+	if r.verifyTTL < 2*time.Hour {
+		expiresIn = fmt.Sprintf("%d minutes", int(r.verifyTTL.Minutes()))
+	}
+
+	return r.mailer.Send(ctx, mail.Message{
+		To:      reader.Email,
+		Subject: "Verify your email address for " + siteName,
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm your email address to finish creating your %s account:\n\n%s\n\nThe link expires in %s. If you did not sign up, ignore this email.\n",
+			reader.Name, siteName, link, expiresIn),
+	})
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func NewReaderService(readerRepo repository.ReaderRepository, cfg *config.Config, jwtToken auth.Jwt, mailer mail.Mailer) ReaderService {
+	ttl := cfg.App.ReaderVerifyTTL
+	if ttl <= 0 {
+		ttl = defaultReaderVerifyTTL
+	}
+
+	return &readerService{
+		readerRepository: readerRepo,
+		cfg:              cfg,
+		jwtToken:         jwtToken,
+		mailer:           mailer,
+		verifyTTL:        time.Duration(ttl) * time.Second,
+	}
+}
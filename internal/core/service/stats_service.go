@@ -0,0 +1,160 @@
+package service
+
+import (
+	"bwanews/config"
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/lib/cache"
+	"bwanews/lib/views"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const (
+	defaultViewFlushInterval = 30
+	defaultViewDedupeWindow  = 1800
+
+	statsBucket     = 5 * time.Minute
+	statsRetention  = 7*24*time.Hour + statsBucket
+	maxPendingViews = 1000
+	maxSeenViews    = 100000
+)
+
+// trendingWindows are the windows GetTrending accepts. Within a window a
+// view loses half its weight every quarter of the window.
+var trendingWindows = map[string]time.Duration{
+	"1h":  time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+}
+
+type StatsService interface {
+	TrackView(contentID int64, visitor string) bool
+	GetTrending(ctx context.Context, window string, limit int) ([]entity.TrendingContentEntity, error)
+	Run(ctx context.Context)
+	Flush(ctx context.Context) error
+}
+
+type statsService struct {
+	contentStatRepository repository.ContentStatRepository
+	counter               *views.Counter
+	interval              time.Duration
+	trending              *cache.Cache[[]entity.TrendingContentEntity]
+	lastPrune             time.Time
+}
+
+// TrackView implements StatsService.
+// The view is only counted in memory; Run writes it out with the next
+// batch. It reports false when the visitor already viewed the content within
+// the dedupe window.
+func (s *statsService) TrackView(contentID int64, visitor string) bool {
+	return s.counter.Add(contentID, visitor, time.Now())
+}
+
+// GetTrending implements StatsService.
+// Rankings are cached for a flush interval, since the stored counts do not
+// change faster than that.
+func (s *statsService) GetTrending(ctx context.Context, window string, limit int) ([]entity.TrendingContentEntity, error) {
+	duration, ok := trendingWindows[window]
+	if !ok {
+		code = "[SERVICE] GetTrending = 1"
+		log.Errorw(code, entity.ErrUnknownWindow)
+		return nil, entity.ErrUnknownWindow
+	}
+
+	key := window + ":" + strconv.Itoa(limit)
+	if results, ok := s.trending.Get(key); ok {
+		return results, nil
+	}
+
+	now := time.Now()
+	results, err := s.contentStatRepository.GetTrendingContents(ctx, entity.TrendingQuery{
+		Since:    now.Add(-duration),
+		Now:      now,
+		HalfLife: duration / 4,
+		Limit:    limit,
+	})
+	if err != nil {
+		code = "[SERVICE] GetTrending = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	s.trending.Set(key, results)
+
+	return results, nil
+}
+
+// Run implements StatsService.
+// It flushes the counted views every interval until ctx is done.
+func (s *statsService) Run(ctx context.Context) {
+	s.counter.Run(ctx, s.interval, func(err error) {
+		code := "[SERVICE] Run = 1"
+		log.Errorw(code, err)
+	})
+}
+
+// Flush implements StatsService.
+// It writes the pending views out now, for a graceful shutdown.
+func (s *statsService) Flush(ctx context.Context) error {
+	err := s.counter.Flush(ctx)
+	if err != nil {
+		code := "[SERVICE] Flush = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// store is the flush function of the counter. Buckets older than the
+// longest window are pruned at most once an hour.
+func (s *statsService) store(ctx context.Context, counts []views.Count) error {
+	viewCounts := []entity.ContentViewCount{}
+	for _, count := range counts {
+		viewCounts = append(viewCounts, entity.ContentViewCount{
+			ContentID:   count.ContentID,
+			BucketStart: count.Bucket,
+			Views:       count.Views,
+		})
+	}
+
+	err := s.contentStatRepository.AddContentViews(ctx, viewCounts)
+	if err != nil {
+		return err
+	}
+
+	if time.Since(s.lastPrune) >= time.Hour {
+		s.lastPrune = time.Now()
+		if err := s.contentStatRepository.PruneContentStats(ctx, s.lastPrune.Add(-statsRetention)); err != nil {
+			code := "[SERVICE] store = 1"
+			log.Errorw(code, err)
+		}
+	}
+
+	return nil
+}
+
+func NewStatsService(contentStatRepo repository.ContentStatRepository, cfg *config.Config) StatsService {
+	interval := cfg.App.ViewFlushInterval
+	if interval <= 0 {
+		interval = defaultViewFlushInterval
+	}
+
+	dedupe := cfg.App.ViewDedupeWindow
+	if dedupe <= 0 {
+		dedupe = defaultViewDedupeWindow
+	}
+
+	s := &statsService{
+		contentStatRepository: contentStatRepo,
+		interval:              time.Duration(interval) * time.Second,
+		trending:              cache.New[[]entity.TrendingContentEntity](time.Duration(interval) * time.Second),
+	}
+	s.counter = views.NewCounter(s.store, statsBucket, time.Duration(dedupe)*time.Second, maxPendingViews, maxSeenViews)
+
+	return s
+}
@@ -0,0 +1,502 @@
+package service
+
+import (
+	"bwanews/config"
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/lib/markup"
+	"bwanews/lib/transfer"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const exportBatchSize = 200
+
+var (
+	// errDryRun rolls back the import transaction once a dry run has been
+	// reported.
+	errDryRun = errors.New("dry run")
+	// errInvalidRecord marks a record that is skipped without aborting the
+	// import.
+	errInvalidRecord = errors.New("invalid record")
+)
+
+type TransferService interface {
+	CheckExport(ctx context.Context, req entity.ExportOptions) error
+	Export(ctx context.Context, w io.Writer, req entity.ExportOptions) error
+	Import(ctx context.Context, r io.Reader, req entity.ImportOptions) (*entity.ImportReport, error)
+	ImportRecords(ctx context.Context, dec transfer.Decoder, req entity.ImportOptions) (*entity.ImportReport, error)
+}
+
+type transferService struct {
+	categoryRepository repository.CategoryRepository
+	contentRepository  repository.ContentRepository
+	userRepository     repository.UserRepository
+	unitOfWork         repository.UnitOfWork
+	events             webhookEvents
+}
+
+// CheckExport implements TransferService.
+// It runs the checks of Export without writing anything, so a streamed
+// export can still answer with an error status.
+func (t *transferService) CheckExport(ctx context.Context, req entity.ExportOptions) error {
+	if err := t.authorize(ctx, req.UserID); err != nil {
+		code = "[SERVICE] CheckExport = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if _, err := transfer.NewEncoder(io.Discard, req.Format); err != nil {
+		code = "[SERVICE] CheckExport = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// Export implements TransferService.
+// Categories are written before contents so an import can resolve every
+// content's category slug. A zero req.UserID marks a trusted caller such
+// as the CLI and skips the role check.
+func (t *transferService) Export(ctx context.Context, w io.Writer, req entity.ExportOptions) error {
+	if err := t.authorize(ctx, req.UserID); err != nil {
+		code = "[SERVICE] Export = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	enc, err := transfer.NewEncoder(w, req.Format)
+	if err != nil {
+		code = "[SERVICE] Export = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = t.categoryRepository.ExportCategories(ctx, func(categories []entity.CategoryEntity) error {
+		for _, category := range categories {
+			err := enc.Encode(entity.TransferRecord{
+				Type:        entity.TransferTypeCategory,
+				Slug:        category.Slug,
+				Title:       category.Title,
+				AuthorEmail: category.User.Email,
+				CreatedAt:   category.CreatedAt,
+				UpdatedAt:   category.UpdatedAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		code = "[SERVICE] Export = 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = t.contentRepository.ExportContents(ctx, exportBatchSize, func(contents []entity.ContentEntity) error {
+		for _, content := range contents {
+			err := enc.Encode(entity.TransferRecord{
+				Type:         entity.TransferTypeContent,
+				Slug:         content.Slug,
+				Title:        content.Title,
+				Excerpt:      content.Excerpt,
+				Description:  content.Description,
+				BodyFormat:   content.BodyFormat,
+				Blocks:       content.Blocks,
+				Image:        content.Image,
+				Tags:         content.Tags,
+				Status:       content.Status,
+				CategorySlug: content.Category.Slug,
+				AuthorEmail:  content.User.Email,
+				CreatedAt:    content.CreatedAt,
+				UpdatedAt:    content.UpdatedAt,
+				ContentType:  content.Type,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		code = "[SERVICE] Export = 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return enc.Flush()
+}
+
+// Import implements TransferService.
+// Records are upserted by slug inside one transaction. Invalid records are
+// reported and skipped; a database error aborts the whole import. A dry
+// run performs the same writes and rolls them back, so its report matches
+// what a real import would do.
+func (t *transferService) Import(ctx context.Context, r io.Reader, req entity.ImportOptions) (*entity.ImportReport, error) {
+	dec, err := transfer.NewDecoder(r, req.Format)
+	if err != nil {
+		code = "[SERVICE] Import = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return t.ImportRecords(ctx, dec, req)
+}
+
+// ImportRecords implements TransferService.
+// It is Import for callers that produce records themselves. Inside an
+// outer UnitOfWork it joins that transaction.
+func (t *transferService) ImportRecords(ctx context.Context, dec transfer.Decoder, req entity.ImportOptions) (*entity.ImportReport, error) {
+	if err := t.authorize(ctx, req.UserID); err != nil {
+		code = "[SERVICE] ImportRecords = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	report := &entity.ImportReport{DryRun: req.DryRun, Items: []entity.ImportItemResult{}}
+	err := t.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		imp := &importer{transferService: t, authors: map[string]int64{}, categories: map[string]int64{}}
+		if err := imp.defaultAuthor(ctx, req); err != nil {
+			return err
+		}
+
+		for {
+			rec, err := dec.Decode()
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				return fmt.Errorf("%w: %v", entity.ErrMalformedImport, err)
+			}
+
+			item := entity.ImportItemResult{Line: dec.Line(), Type: rec.Type, Slug: rec.Slug}
+			item.Action, err = imp.importRecord(ctx, rec)
+			if err != nil && !errors.Is(err, errInvalidRecord) {
+				return fmt.Errorf("line %d: %w", item.Line, err)
+			}
+
+			report.Total++
+			switch {
+			case err != nil:
+				item.Action = entity.ImportItemFailed
+				item.Error = err.Error()
+				report.Failed++
+			case item.Action == entity.ImportItemCreated:
+				report.Created++
+			default:
+				report.Updated++
+			}
+
+			report.Items = append(report.Items, item)
+		}
+
+		if req.DryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		code = "[SERVICE] ImportRecords = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if !req.DryRun {
+		t.events.wake()
+	}
+
+	return report, nil
+}
+
+func (t *transferService) authorize(ctx context.Context, userID int64) error {
+	if userID == 0 {
+		return nil
+	}
+
+	user, err := t.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.CanEditOthers() {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
+
+// importer resolves author emails and category slugs once per import.
+type importer struct {
+	*transferService
+	authors       map[string]int64
+	categories    map[string]int64
+	defaultUserID int64
+}
+
+func (i *importer) defaultAuthor(ctx context.Context, req entity.ImportOptions) error {
+	i.defaultUserID = req.UserID
+	if req.DefaultAuthorEmail == "" {
+		return nil
+	}
+
+	user, err := i.userRepository.GetUserByEmail(ctx, req.DefaultAuthorEmail)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return fmt.Errorf("default author %s: %w", req.DefaultAuthorEmail, entity.ErrNotFound)
+	}
+
+	i.defaultUserID = user.ID
+	return nil
+}
+
+func (i *importer) importRecord(ctx context.Context, rec entity.TransferRecord) (string, error) {
+	if rec.Slug == "" || rec.Title == "" {
+		return "", fmt.Errorf("%w: slug and title are required", errInvalidRecord)
+	}
+
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = time.Now()
+	}
+
+	if rec.UpdatedAt.IsZero() {
+		rec.UpdatedAt = rec.CreatedAt
+	}
+
+	authorID, err := i.author(ctx, rec.AuthorEmail)
+	if err != nil {
+		return "", err
+	}
+
+	switch rec.Type {
+	case entity.TransferTypeCategory:
+		return i.importCategory(ctx, rec, authorID)
+	case entity.TransferTypeContent:
+		return i.importContent(ctx, rec, authorID)
+	}
+
+	return "", fmt.Errorf("%w: unknown type %q", errInvalidRecord, rec.Type)
+}
+
+func (i *importer) importCategory(ctx context.Context, rec entity.TransferRecord, authorID int64) (string, error) {
+	if utf8.RuneCountInString(rec.Title) > 200 {
+		return "", fmt.Errorf("%w: title is longer than 200 characters", errInvalidRecord)
+	}
+
+	existing, err := i.categoryRepository.GetCategoryBySlug(ctx, rec.Slug)
+	if err != nil {
+		return "", err
+	}
+
+	err = i.categoryRepository.ImportCategory(ctx, entity.CategoryEntity{
+		Title:     rec.Title,
+		Slug:      rec.Slug,
+		User:      entity.UserEntity{ID: authorID},
+		CreatedAt: rec.CreatedAt,
+		UpdatedAt: rec.UpdatedAt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	delete(i.categories, rec.Slug)
+
+	imported, err := i.categoryRepository.GetCategoryBySlug(ctx, rec.Slug)
+	if err != nil {
+		return "", err
+	}
+
+	action, event := entity.ImportItemCreated, "created"
+	if existing != nil {
+		action, event = entity.ImportItemUpdated, "updated"
+	}
+
+	err = i.events.category(ctx, event, imported.ID, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return action, nil
+}
+
+func (i *importer) importContent(ctx context.Context, rec entity.TransferRecord, authorID int64) (string, error) {
+	switch {
+	case utf8.RuneCountInString(rec.Slug) > 250:
+		return "", fmt.Errorf("%w: slug is longer than 250 characters", errInvalidRecord)
+	case utf8.RuneCountInString(rec.Title) > 200:
+		return "", fmt.Errorf("%w: title is longer than 200 characters", errInvalidRecord)
+	case utf8.RuneCountInString(rec.Excerpt) > 250:
+		return "", fmt.Errorf("%w: excerpt is longer than 250 characters", errInvalidRecord)
+	}
+
+	if !markup.ValidFormat(rec.BodyFormat) {
+		return "", fmt.Errorf("%w: unknown body format %q", errInvalidRecord, rec.BodyFormat)
+	}
+
+	switch rec.Status {
+	case "":
+		rec.Status = entity.ContentStatusDraft
+	case entity.ContentStatusPublish, entity.ContentStatusDraft, entity.ContentStatusArchive:
+	default:
+		return "", fmt.Errorf("%w: unknown status %q", errInvalidRecord, rec.Status)
+	}
+
+	switch rec.ContentType {
+	case "", entity.ContentTypeArticle, entity.ContentTypeLiveBlog:
+	default:
+		return "", fmt.Errorf("%w: unknown content type %q", errInvalidRecord, rec.ContentType)
+	}
+
+	categoryID, err := i.category(ctx, rec.CategorySlug)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := i.contentRepository.GetContentBySlug(ctx, rec.Slug)
+	if err != nil {
+		return "", err
+	}
+
+	// Records without a type, such as those of older exports or of the
+	// WordPress importer, keep the type of the content they replace.
+	if rec.ContentType == "" && existing != nil {
+		rec.ContentType = existing.Type
+	}
+
+	content := entity.ContentEntity{
+		Title:       rec.Title,
+		Slug:        rec.Slug,
+		Excerpt:     rec.Excerpt,
+		Description: rec.Description,
+		BodyFormat:  rec.BodyFormat,
+		Blocks:      rec.Blocks,
+		Image:       rec.Image,
+		Tags:        rec.Tags,
+		Status:      rec.Status,
+		Type:        rec.ContentType,
+		CategoryID:  categoryID,
+		CreatedByID: authorID,
+		CreatedAt:   rec.CreatedAt,
+		UpdatedAt:   rec.UpdatedAt,
+	}
+
+	err = renderBlocks(&content)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errInvalidRecord, err.Error())
+	}
+
+	err = renderBody(&content)
+	if err != nil {
+		return "", err
+	}
+
+	err = i.contentRepository.ImportContent(ctx, content)
+	if err != nil {
+		return "", err
+	}
+
+	imported, err := i.contentRepository.GetContentBySlug(ctx, rec.Slug)
+	if err != nil {
+		return "", err
+	}
+
+	before := map[int64]entity.ContentEntity{}
+	if existing != nil {
+		before[existing.ID] = *existing
+	}
+
+	err = i.events.contents(ctx, []int64{imported.ID}, before)
+	if err != nil {
+		return "", err
+	}
+
+	if existing != nil {
+		return entity.ImportItemUpdated, nil
+	}
+
+	return entity.ImportItemCreated, nil
+}
+
+// author resolves an email to a user id. Missing or unknown emails fall
+// back to the default author and fail the record when there is none.
+func (i *importer) author(ctx context.Context, email string) (int64, error) {
+	if email == "" {
+		if i.defaultUserID == 0 {
+			return 0, fmt.Errorf("%w: author_email is required", errInvalidRecord)
+		}
+
+		return i.defaultUserID, nil
+	}
+
+	if id, ok := i.authors[email]; ok {
+		return id, nil
+	}
+
+	user, err := i.userRepository.GetUserByEmail(ctx, email)
+	if err != nil {
+		return 0, err
+	}
+
+	if user == nil {
+		if i.defaultUserID == 0 {
+			return 0, fmt.Errorf("%w: unknown author %s", errInvalidRecord, email)
+		}
+
+		i.authors[email] = i.defaultUserID
+		return i.defaultUserID, nil
+	}
+
+	i.authors[email] = user.ID
+	return user.ID, nil
+}
+
+func (i *importer) category(ctx context.Context, slug string) (int64, error) {
+	if slug == "" {
+		return 0, fmt.Errorf("%w: category_slug is required", errInvalidRecord)
+	}
+
+	if id, ok := i.categories[slug]; ok {
+		return id, nil
+	}
+
+	category, err := i.categoryRepository.GetCategoryBySlug(ctx, slug)
+	if err != nil {
+		return 0, err
+	}
+
+	if category == nil {
+		return 0, fmt.Errorf("%w: unknown category %s", errInvalidRecord, slug)
+	}
+
+	i.categories[slug] = category.ID
+	return category.ID, nil
+}
+
+func NewTransferService(categoryRepo repository.CategoryRepository, contentRepo repository.ContentRepository, userRepo repository.UserRepository, unitOfWork repository.UnitOfWork, webhookService WebhookService, cfg *config.Config) TransferService {
+	return &transferService{
+		categoryRepository: categoryRepo,
+		contentRepository:  contentRepo,
+		userRepository:     userRepo,
+		unitOfWork:         unitOfWork,
+		events: webhookEvents{
+			contentRepository:  contentRepo,
+			categoryRepository: categoryRepo,
+			webhookService:     webhookService,
+			cfg:                cfg,
+		},
+	}
+}
@@ -12,6 +12,7 @@ import (
 type UserService interface {
 	UpdatePassword(ctx context.Context, id int64, newPass string) error
 	GetUserByID(ctx context.Context, id int64) (*entity.UserEntity, error)
+	GetUsers(ctx context.Context, query entity.QueryString, userID int64) ([]entity.UserEntity, int64, error)
 }
 
 type userService struct {
@@ -30,6 +31,33 @@ func (u *userService) GetUserByID(ctx context.Context, id int64) (*entity.UserEn
 	return result, nil
 }
 
+// GetUsers implements UserService.
+// Only admins may list the staff, since the list exposes every email and
+// role.
+func (u *userService) GetUsers(ctx context.Context, query entity.QueryString, userID int64) ([]entity.UserEntity, int64, error) {
+	user, err := u.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		code := "[SERVICE] GetUsers-1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	if user.Role != entity.RoleAdmin {
+		code := "[SERVICE] GetUsers-2"
+		log.Errorw(code, entity.ErrForbidden)
+		return nil, 0, entity.ErrForbidden
+	}
+
+	results, totalData, err := u.userRepo.GetUsers(ctx, query)
+	if err != nil {
+		code := "[SERVICE] GetUsers-3"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	return results, totalData, nil
+}
+
 // UpdatePassword implements UserService.
 func (u *userService) UpdatePassword(ctx context.Context, id int64, newPass string) error {
 	password, err := conv.HashPassword(newPass)
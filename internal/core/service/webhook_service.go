@@ -0,0 +1,673 @@
+package service
+
+import (
+	"bwanews/config"
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/lib/conv"
+	"bwanews/lib/webhook"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const (
+	defaultWebhookPollInterval = 10
+	defaultWebhookTimeout      = 10
+	defaultWebhookMaxAttempts  = 8
+
+	// webhookBatch deliveries are claimed at a time and sent by up to
+	// webhookWorkers at once.
+	webhookBatch   = 20
+	webhookWorkers = 4
+	// webhookLease keeps claimed deliveries from other workers while they
+	// are sent; it must outlast a batch.
+	webhookLease     = 5 * time.Minute
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = 6 * time.Hour
+	maxDeliveryError = 1000
+)
+
+type WebhookService interface {
+	GetWebhooks(ctx context.Context, query entity.WebhookQuery, userID int64) ([]entity.WebhookEntity, int64, error)
+	GetWebhookByID(ctx context.Context, id, userID int64) (*entity.WebhookEntity, error)
+	CreateWebhook(ctx context.Context, req entity.WebhookEntity, userID int64) (*entity.WebhookEntity, error)
+	UpdateWebhook(ctx context.Context, req entity.WebhookEntity, userID int64) error
+	DeleteWebhook(ctx context.Context, id, userID int64) error
+
+	GetDeliveries(ctx context.Context, query entity.WebhookDeliveryQuery, userID int64) ([]entity.WebhookDeliveryEntity, int64, error)
+	Redeliver(ctx context.Context, webhookID, id, userID int64) (*entity.WebhookDeliveryEntity, error)
+
+	Dispatch(ctx context.Context, event string, data interface{}) error
+	Wake()
+	Run(ctx context.Context)
+}
+
+type webhookService struct {
+	webhookRepository repository.WebhookRepository
+	userRepository    repository.UserRepository
+	sender            webhook.Sender
+	interval          time.Duration
+	maxAttempts       int
+	// wake starts a round of deliveries before the next tick when an event
+	// is queued on this instance.
+	wake chan struct{}
+}
+
+// webhookPayload is the body of every delivery. ID identifies the event,
+// so receivers can drop the duplicates retries and redeliveries bring.
+type webhookPayload struct {
+	ID         string      `json:"id"`
+	Event      string      `json:"event"`
+	OccurredAt string      `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// contentEventData is the data of the content events.
+type contentEventData struct {
+	ID         int64    `json:"id"`
+	Title      string   `json:"title"`
+	Slug       string   `json:"slug"`
+	URL        string   `json:"url"`
+	Excerpt    string   `json:"excerpt"`
+	Image      string   `json:"image"`
+	Tags       []string `json:"tags"`
+	Status     string   `json:"status"`
+	Type       string   `json:"type"`
+	CategoryID int64    `json:"category_id"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+// categoryEventData is the data of category.changed; Action is created,
+// updated or deleted.
+type categoryEventData struct {
+	Action string `json:"action"`
+	ID     int64  `json:"id"`
+	Title  string `json:"title"`
+	Slug   string `json:"slug"`
+}
+
+// GetWebhooks implements WebhookService.
+func (w *webhookService) GetWebhooks(ctx context.Context, query entity.WebhookQuery, userID int64) ([]entity.WebhookEntity, int64, error) {
+	err := w.checkAdmin(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] GetWebhooks = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	results, totalData, err := w.webhookRepository.GetWebhooks(ctx, query)
+	if err != nil {
+		code = "[SERVICE] GetWebhooks = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	return results, totalData, nil
+}
+
+// GetWebhookByID implements WebhookService.
+func (w *webhookService) GetWebhookByID(ctx context.Context, id, userID int64) (*entity.WebhookEntity, error) {
+	err := w.checkAdmin(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] GetWebhookByID = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	result, err := w.webhookRepository.GetWebhookByID(ctx, id)
+	if err != nil {
+		code = "[SERVICE] GetWebhookByID = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateWebhook implements WebhookService.
+// A secret is generated when none is given; it is only ever returned here.
+func (w *webhookService) CreateWebhook(ctx context.Context, req entity.WebhookEntity, userID int64) (*entity.WebhookEntity, error) {
+	err := w.checkAdmin(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] CreateWebhook = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	req.Events, err = checkWebhook(req.URL, req.Events)
+	if err != nil {
+		code = "[SERVICE] CreateWebhook = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	if req.Secret == "" {
+		req.Secret, err = randomHex(32)
+		if err != nil {
+			code = "[SERVICE] CreateWebhook = 3"
+			log.Errorw(code, err)
+			return nil, err
+		}
+	}
+
+	req.CreatedByID = &userID
+	id, err := w.webhookRepository.CreateWebhook(ctx, req)
+	if err != nil {
+		code = "[SERVICE] CreateWebhook = 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	result, err := w.webhookRepository.GetWebhookByID(ctx, id)
+	if err != nil {
+		code = "[SERVICE] CreateWebhook = 5"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateWebhook implements WebhookService.
+// An empty secret keeps the current one.
+func (w *webhookService) UpdateWebhook(ctx context.Context, req entity.WebhookEntity, userID int64) error {
+	err := w.checkAdmin(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] UpdateWebhook = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	req.Events, err = checkWebhook(req.URL, req.Events)
+	if err != nil {
+		code = "[SERVICE] UpdateWebhook = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = w.webhookRepository.UpdateWebhook(ctx, req)
+	if err != nil {
+		code = "[SERVICE] UpdateWebhook = 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWebhook implements WebhookService.
+func (w *webhookService) DeleteWebhook(ctx context.Context, id, userID int64) error {
+	err := w.checkAdmin(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] DeleteWebhook = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	err = w.webhookRepository.DeleteWebhook(ctx, id)
+	if err != nil {
+		code = "[SERVICE] DeleteWebhook = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetDeliveries implements WebhookService.
+func (w *webhookService) GetDeliveries(ctx context.Context, query entity.WebhookDeliveryQuery, userID int64) ([]entity.WebhookDeliveryEntity, int64, error) {
+	err := w.checkAdmin(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] GetDeliveries = 1"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	_, err = w.webhookRepository.GetWebhookByID(ctx, query.WebhookID)
+	if err != nil {
+		code = "[SERVICE] GetDeliveries = 2"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	results, totalData, err := w.webhookRepository.GetDeliveries(ctx, query)
+	if err != nil {
+		code = "[SERVICE] GetDeliveries = 3"
+		log.Errorw(code, err)
+		return nil, 0, err
+	}
+
+	return results, totalData, nil
+}
+
+// Redeliver implements WebhookService.
+// It queues a new delivery of the same payload, whatever became of the
+// original, with a fresh set of attempts.
+func (w *webhookService) Redeliver(ctx context.Context, webhookID, id, userID int64) (*entity.WebhookDeliveryEntity, error) {
+	err := w.checkAdmin(ctx, userID)
+	if err != nil {
+		code = "[SERVICE] Redeliver = 1"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	original, err := w.webhookRepository.GetDeliveryByID(ctx, webhookID, id)
+	if err != nil {
+		code = "[SERVICE] Redeliver = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	ids, err := w.webhookRepository.CreateDeliveries(ctx, []entity.WebhookDeliveryEntity{{
+		WebhookID:      original.WebhookID,
+		Event:          original.Event,
+		Payload:        original.Payload,
+		RedeliveryOfID: &original.ID,
+	}})
+	if err != nil {
+		code = "[SERVICE] Redeliver = 3"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	w.signal()
+
+	result, err := w.webhookRepository.GetDeliveryByID(ctx, webhookID, ids[0])
+	if err != nil {
+		code = "[SERVICE] Redeliver = 4"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Dispatch implements WebhookService.
+// It queues event for every active webhook subscribed to it. Called inside
+// the unit of work of the change that raised the event, the deliveries are
+// saved with that change or not at all; Wake sends them once it commits.
+func (w *webhookService) Dispatch(ctx context.Context, event string, data interface{}) error {
+	webhooks, err := w.webhookRepository.GetWebhooksForEvent(ctx, event)
+	if err != nil {
+		code = "[SERVICE] Dispatch = 1"
+		log.Errorw(code, err)
+		return err
+	}
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	eventID, err := randomHex(16)
+	if err != nil {
+		code = "[SERVICE] Dispatch = 2"
+		log.Errorw(code, err)
+		return err
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		ID:         eventID,
+		Event:      event,
+		OccurredAt: time.Now().Format(time.RFC3339),
+		Data:       data,
+	})
+	if err != nil {
+		code = "[SERVICE] Dispatch = 3"
+		log.Errorw(code, err)
+		return err
+	}
+
+	deliveries := make([]entity.WebhookDeliveryEntity, 0, len(webhooks))
+	for _, hook := range webhooks {
+		deliveries = append(deliveries, entity.WebhookDeliveryEntity{
+			WebhookID: hook.ID,
+			Event:     event,
+			Payload:   payload,
+		})
+	}
+
+	_, err = w.webhookRepository.CreateDeliveries(ctx, deliveries)
+	if err != nil {
+		code = "[SERVICE] Dispatch = 4"
+		log.Errorw(code, err)
+		return err
+	}
+
+	return nil
+}
+
+// Wake implements WebhookService.
+// It starts a round of deliveries on this instance without waiting for the
+// next poll.
+func (w *webhookService) Wake() {
+	w.signal()
+}
+
+// Run implements WebhookService.
+// It sends the due deliveries every poll interval, and right away when an
+// event is queued on this instance, until ctx is done. Several instances
+// may run it side by side.
+func (w *webhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+
+		// A full batch suggests more are due.
+		for w.deliverDue(ctx) == webhookBatch && ctx.Err() == nil {
+		}
+	}
+}
+
+// deliverDue claims a batch of due deliveries, sends them and returns how
+// many it claimed.
+func (w *webhookService) deliverDue(ctx context.Context) int {
+	now := time.Now()
+	deliveries, err := w.webhookRepository.ClaimDeliveries(ctx, now, now.Add(webhookLease), webhookBatch)
+	if err != nil {
+		code = "[SERVICE] deliverDue = 1"
+		log.Errorw(code, err)
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, webhookWorkers)
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(delivery entity.WebhookDeliveryEntity) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			w.deliver(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+// deliver makes one attempt at delivery and records the outcome: success,
+// a retry after a growing wait, or failure once the attempts run out. An
+// attempt cut short by shutdown is not recorded; the delivery is sent
+// again once its lease runs out.
+func (w *webhookService) deliver(ctx context.Context, delivery entity.WebhookDeliveryEntity) {
+	status, err := w.sender.Send(ctx, webhook.Request{
+		URL:        delivery.Webhook.URL,
+		Secret:     delivery.Webhook.Secret,
+		Event:      delivery.Event,
+		DeliveryID: delivery.ID,
+		Body:       delivery.Payload,
+	})
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = nil
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = entity.DeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case delivery.Attempts >= w.maxAttempts:
+		delivery.Status = entity.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = truncate(err.Error(), maxDeliveryError)
+	default:
+		next := now.Add(webhook.Backoff(delivery.Attempts, webhookRetryBase, webhookRetryMax))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = truncate(err.Error(), maxDeliveryError)
+	}
+
+	err = w.webhookRepository.SaveDeliveryAttempt(ctx, delivery)
+	if err != nil {
+		code = "[SERVICE] deliver = 1"
+		log.Errorw(code, err)
+	}
+}
+
+// signal wakes Run without blocking; a pending wake-up already covers the
+// new deliveries.
+func (w *webhookService) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// checkAdmin fails with ErrForbidden unless the staff member is an admin;
+// webhooks carry secrets and send content to outside systems.
+func (w *webhookService) checkAdmin(ctx context.Context, userID int64) error {
+	user, err := w.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Role != entity.RoleAdmin {
+		return entity.ErrForbidden
+	}
+
+	return nil
+}
+
+// checkWebhook validates the endpoint and events of a webhook and returns
+// the events without repeats.
+func checkWebhook(rawURL string, events []string) ([]string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, entity.ErrInvalidWebhookURL
+	}
+
+	if webhook.CheckURL(rawURL) != nil {
+		return nil, entity.ErrInvalidWebhookURL
+	}
+
+	known := make(map[string]bool, len(entity.WebhookEvents))
+	for _, event := range entity.WebhookEvents {
+		known[event] = true
+	}
+
+	seen := make(map[string]bool, len(events))
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		if !known[event] {
+			return nil, entity.ErrUnknownEvent
+		}
+
+		if !seen[event] {
+			seen[event] = true
+			result = append(result, event)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, entity.ErrUnknownEvent
+	}
+
+	return result, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// truncate cuts s to at most n bytes.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	return s[:n]
+}
+
+// webhookEvents queues the webhook events of content and category writes.
+// Its methods run inside the unit of work of the write, so the deliveries
+// commit or roll back with it; wake sends them once it has committed.
+// Content events need every field, category events only categoryRepository
+// and webhookService.
+type webhookEvents struct {
+	contentRepository  repository.ContentRepository
+	categoryRepository repository.CategoryRepository
+	webhookService     WebhookService
+	cfg                *config.Config
+}
+
+// contentsByID loads the contents with the given ids, keyed by id, as they
+// are before a write.
+func (e webhookEvents) contentsByID(ctx context.Context, ids []int64) (map[int64]entity.ContentEntity, error) {
+	contents, err := e.contentRepository.GetContentsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int64]entity.ContentEntity, len(contents))
+	for _, content := range contents {
+		result[content.ID] = content
+	}
+
+	return result, nil
+}
+
+// contents queues the events for written contents. before holds the
+// contents that existed ahead of the write; one missing afterwards was
+// deleted. Changes to contents that readers neither could nor can see are
+// not announced, so drafts stay private.
+func (e webhookEvents) contents(ctx context.Context, ids []int64, before map[int64]entity.ContentEntity) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	after, err := e.contentsByID(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	categorySlugs := map[int64]string{}
+	for _, id := range ids {
+		old, existed := before[id]
+		wasPublished := existed && old.Status == entity.ContentStatusPublish
+		content, exists := after[id]
+		isPublished := exists && content.Status == entity.ContentStatusPublish
+
+		switch {
+		case !exists && wasPublished:
+			err = e.webhookService.Dispatch(ctx, entity.EventContentDeleted, e.contentEvent(ctx, old, categorySlugs))
+		case isPublished && !wasPublished:
+			err = e.webhookService.Dispatch(ctx, entity.EventContentPublished, e.contentEvent(ctx, content, categorySlugs))
+		case exists && (isPublished || wasPublished):
+			err = e.webhookService.Dispatch(ctx, entity.EventContentUpdated, e.contentEvent(ctx, content, categorySlugs))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// contentEvent is the webhook data of content, with its public URL.
+// categorySlugs caches the category slugs the URLs need.
+func (e webhookEvents) contentEvent(ctx context.Context, content entity.ContentEntity, categorySlugs map[int64]string) contentEventData {
+	slug, ok := categorySlugs[content.CategoryID]
+	if !ok {
+		if category, err := e.categoryRepository.GetCategoryByID(ctx, content.CategoryID); err == nil {
+			slug = category.Slug
+		}
+		categorySlugs[content.CategoryID] = slug
+	}
+
+	tags := content.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return contentEventData{
+		ID:         content.ID,
+		Title:      content.Title,
+		Slug:       content.Slug,
+		URL:        e.cfg.App.PublicBaseURL + conv.ContentPath(e.cfg.App.ContentPath, slug, content.Slug),
+		Excerpt:    content.Excerpt,
+		Image:      content.Image,
+		Tags:       tags,
+		Status:     content.Status,
+		Type:       content.Type,
+		CategoryID: content.CategoryID,
+		UpdatedAt:  content.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// category queues category.changed for a category that was saved, or for
+// deleted, which is gone by now.
+func (e webhookEvents) category(ctx context.Context, action string, id int64, deleted *entity.CategoryEntity) error {
+	category := deleted
+	if category == nil {
+		var err error
+		category, err = e.categoryRepository.GetCategoryByID(ctx, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.webhookService.Dispatch(ctx, entity.EventCategoryChanged, categoryEventData{
+		Action: action,
+		ID:     category.ID,
+		Title:  category.Title,
+		Slug:   category.Slug,
+	})
+}
+
+// wake sends the events queued by a committed write.
+func (e webhookEvents) wake() {
+	e.webhookService.Wake()
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, userRepo repository.UserRepository, cfg *config.Config) WebhookService {
+	interval := cfg.App.WebhookPollInterval
+	if interval <= 0 {
+		interval = defaultWebhookPollInterval
+	}
+
+	timeout := cfg.App.WebhookTimeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	maxAttempts := cfg.App.WebhookMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultWebhookMaxAttempts
+	}
+
+	return &webhookService{
+		webhookRepository: webhookRepo,
+		userRepository:    userRepo,
+		sender:            webhook.NewHTTPSender(time.Duration(timeout) * time.Second),
+		interval:          time.Duration(interval) * time.Second,
+		maxAttempts:       maxAttempts,
+		wake:              make(chan struct{}, 1),
+	}
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"bwanews/config"
+	"bwanews/internal/adapter/cloudflare"
+	"bwanews/internal/adapter/repository"
+	"bwanews/internal/core/domain/entity"
+	"bwanews/lib/conv"
+	"bwanews/lib/transfer"
+	"bwanews/lib/wxr"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+const (
+	// wordPressExcerptLength is the longest excerpt a content can hold.
+	wordPressExcerptLength = 250
+	// maxWordPressImage bounds a downloaded featured image.
+	maxWordPressImage = 20 << 20
+)
+
+type WordPressService interface {
+	Import(ctx context.Context, r io.Reader, req entity.WordPressImportOptions) (*entity.WordPressImportReport, error)
+}
+
+type wordPressService struct {
+	transferService   TransferService
+	contentRepository repository.ContentRepository
+	userRepository    repository.UserRepository
+	unitOfWork        repository.UnitOfWork
+	r2                cloudflare.CloudflareR2Adapter
+	httpClient        *http.Client
+	cfg               *config.Config
+}
+
+// Import implements WordPressService.
+// Featured images are uploaded first, outside the transaction, unless an
+// earlier run already did; authors, categories and posts are then written
+// in one transaction through TransferService, so posts are upserted by
+// slug and a re-run updates them.
+// A dry run uploads nothing, keeps the WordPress image URLs and rolls the
+// transaction back.
+func (w *wordPressService) Import(ctx context.Context, r io.Reader, req entity.WordPressImportOptions) (*entity.WordPressImportReport, error) {
+	export, err := wxr.Parse(r)
+	if err != nil {
+		code = "[SERVICE] WordPressImport = 1"
+		log.Errorw(code, err)
+		return nil, fmt.Errorf("%w: %v", entity.ErrMalformedImport, err)
+	}
+
+	report := &entity.WordPressImportReport{
+		Authors:   []entity.WordPressAuthorResult{},
+		Images:    []entity.WordPressImageResult{},
+		Redirects: []entity.Redirect{},
+	}
+
+	attachments := map[int64]string{}
+	for _, item := range export.Items {
+		if item.PostType == "attachment" && item.AttachmentURL != "" {
+			attachments[item.PostID] = item.AttachmentURL
+		}
+	}
+
+	images := map[int64]string{}
+	for _, item := range export.Items {
+		source, ok := attachments[item.ThumbnailID()]
+		if item.PostType != "post" || !ok {
+			continue
+		}
+
+		images[item.PostID] = source
+		if req.DryRun {
+			continue
+		}
+
+		image := entity.WordPressImageResult{PostID: item.PostID, Source: source}
+		image.URL, image.Reused, err = w.importImage(ctx, item, source, req.UploadsDir)
+		if err != nil {
+			image.Error = err.Error()
+		} else {
+			images[item.PostID] = image.URL
+		}
+
+		report.Images = append(report.Images, image)
+	}
+
+	emails := map[string]string{}
+	for _, author := range export.Authors {
+		emails[author.Login] = author.Email
+	}
+
+	records, posts := wordPressRecords(export, emails, images)
+
+	err = w.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		for _, author := range export.Authors {
+			result, err := w.importAuthor(ctx, author, req.DryRun)
+			if err != nil {
+				return err
+			}
+
+			if result != nil {
+				report.Authors = append(report.Authors, *result)
+			}
+		}
+
+		result, err := w.transferService.ImportRecords(ctx, transfer.NewSliceDecoder(records), entity.ImportOptions{
+			DefaultAuthorEmail: req.DefaultAuthorEmail,
+		})
+		if err != nil {
+			return err
+		}
+
+		report.ImportReport = *result
+		report.DryRun = req.DryRun
+		if req.DryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		code = "[SERVICE] WordPressImport = 2"
+		log.Errorw(code, err)
+		return nil, err
+	}
+
+	for _, item := range report.Items {
+		post, ok := posts[item.Line]
+		if !ok || item.Action == entity.ImportItemFailed {
+			continue
+		}
+
+		to := conv.ContentPath(w.cfg.App.ContentPath, records[item.Line-1].CategorySlug, item.Slug)
+		if from, err := url.Parse(post.Link); err == nil && from.RequestURI() != "/" {
+			report.Redirects = append(report.Redirects, entity.Redirect{From: from.RequestURI(), To: to})
+		}
+
+		report.Redirects = append(report.Redirects, entity.Redirect{From: fmt.Sprintf("/?p=%d", post.PostID), To: to})
+	}
+
+	return report, nil
+}
+
+// importAuthor maps a WordPress author onto a user by email and creates a
+// journalist account with a random password when none exists. Authors
+// without an email are left to the default author.
+func (w *wordPressService) importAuthor(ctx context.Context, author wxr.Author, dryRun bool) (*entity.WordPressAuthorResult, error) {
+	if author.Email == "" {
+		return nil, nil
+	}
+
+	result := &entity.WordPressAuthorResult{Login: author.Login, Email: author.Email}
+	user, err := w.userRepository.GetUserByEmail(ctx, author.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user != nil {
+		result.Action = entity.WordPressAuthorExisting
+		return result, nil
+	}
+
+	// a dry run is rolled back, so it skips the slow hash
+	hashed := ""
+	if !dryRun {
+		result.Password, err = randomPassword()
+		if err != nil {
+			return nil, err
+		}
+
+		hashed, err = conv.HashPassword(result.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	name := author.DisplayName
+	if name == "" {
+		name = author.Login
+	}
+
+	_, err = w.userRepository.CreateUser(ctx, entity.UserEntity{
+		Name:     name,
+		Email:    author.Email,
+		Password: hashed,
+		Role:     entity.RoleJournalist,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Action = entity.WordPressAuthorCreated
+	return result, nil
+}
+
+// importImage returns the R2 URL of the featured image of item. A post
+// imported before already points at the object its image is uploaded to,
+// which is then reused rather than uploaded again.
+func (w *wordPressService) importImage(ctx context.Context, item wxr.Item, source, uploadsDir string) (string, bool, error) {
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		return "", false, err
+	}
+
+	name := fmt.Sprintf("wp-%d-%s", item.ThumbnailID(), path.Base(sourceURL.Path))
+
+	existing, err := w.contentRepository.GetContentBySlug(ctx, wordPressSlug(item))
+	if err != nil {
+		return "", false, err
+	}
+
+	if existing != nil && existing.Image == fmt.Sprintf("%s/%s", w.cfg.R2.PublicURL, name) {
+		return existing.Image, true, nil
+	}
+
+	imageURL, err := w.uploadImage(ctx, sourceURL, uploadsDir, name)
+	return imageURL, false, err
+}
+
+// uploadImage re-uploads an attachment to R2 as name. The file is read from
+// uploadsDir when it holds a copy of wp-content/uploads, and downloaded
+// from source otherwise. Paths that would leave uploadsDir are rejected, so
+// an export cannot publish other files of the machine running the import.
+func (w *wordPressService) uploadImage(ctx context.Context, source *url.URL, uploadsDir, name string) (string, error) {
+	if uploadsDir != "" {
+		if i := strings.Index(source.Path, "/uploads/"); i >= 0 {
+			rel := filepath.FromSlash(source.Path[i+len("/uploads/"):])
+			if !filepath.IsLocal(rel) {
+				return "", fmt.Errorf("%s: path leaves the uploads folder", source)
+			}
+
+			local := filepath.Join(uploadsDir, rel)
+			if _, err := os.Stat(local); err == nil {
+				return w.r2.UploadImage(ctx, &entity.FileUploadEntity{Name: name, Path: local})
+			}
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, source.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := w.httpClient.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download %s: %s", source, resp.Status)
+	}
+
+	tmp, err := os.CreateTemp("", "wp-image-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	n, err := io.Copy(tmp, io.LimitReader(resp.Body, maxWordPressImage+1))
+	if err != nil {
+		return "", err
+	}
+
+	if n > maxWordPressImage {
+		return "", fmt.Errorf("download %s: larger than %d MB", source, maxWordPressImage>>20)
+	}
+
+	return w.r2.UploadImage(ctx, &entity.FileUploadEntity{Name: name, Path: tmp.Name()})
+}
+
+// wordPressRecords converts categories and posts into transfer records.
+// posts maps the 1-based record index of every post to its source item.
+func wordPressRecords(export *wxr.Export, emails map[string]string, images map[int64]string) ([]entity.TransferRecord, map[int]wxr.Item) {
+	var records []entity.TransferRecord
+	posts := map[int]wxr.Item{}
+	categories := map[string]bool{}
+
+	addCategory := func(slug, name string) {
+		if slug == "" || categories[slug] {
+			return
+		}
+
+		categories[slug] = true
+		records = append(records, entity.TransferRecord{
+			Type:  entity.TransferTypeCategory,
+			Slug:  slug,
+			Title: html.UnescapeString(name),
+		})
+	}
+
+	for _, category := range export.Categories {
+		addCategory(category.Nicename, category.Name)
+	}
+
+	for _, item := range export.Items {
+		if term, ok := item.Category(); ok {
+			addCategory(term.Nicename, term.Name)
+		}
+	}
+
+	for _, item := range export.Items {
+		status, ok := wordPressStatus(item.Status)
+		if item.PostType != "post" || !ok {
+			continue
+		}
+
+		slug := wordPressSlug(item)
+
+		categorySlug := ""
+		if term, ok := item.Category(); ok {
+			categorySlug = term.Nicename
+		}
+
+		excerpt := plainExcerpt(item.Excerpt(), wordPressExcerptLength)
+		if excerpt == "" {
+			excerpt = plainExcerpt(item.Content(), wordPressExcerptLength)
+		}
+
+		records = append(records, entity.TransferRecord{
+			Type:         entity.TransferTypeContent,
+			Slug:         slug,
+			Title:        html.UnescapeString(item.Title),
+			Excerpt:      excerpt,
+			Description:  item.Content(),
+			Image:        images[item.PostID],
+			Tags:         item.TagNames(),
+			Status:       status,
+			CategorySlug: categorySlug,
+			AuthorEmail:  emails[item.Creator],
+			CreatedAt:    item.Published(),
+			UpdatedAt:    item.Modified(),
+		})
+		posts[len(records)] = item
+	}
+
+	return records, posts
+}
+
+// wordPressSlug is the slug a post is imported under.
+func wordPressSlug(item wxr.Item) string {
+	if item.PostName != "" {
+		return item.PostName
+	}
+
+	return fmt.Sprintf("wp-%d", item.PostID)
+}
+
+// wordPressStatus maps a post status; trashed and automatic drafts are
+// not imported.
+func wordPressStatus(status string) (string, bool) {
+	switch status {
+	case "publish":
+		return entity.ContentStatusPublish, true
+	case "draft", "pending", "future", "private":
+		return entity.ContentStatusDraft, true
+	}
+
+	return "", false
+}
+
+// plainExcerpt strips markup from body and cuts it to at most limit
+// characters on a word boundary.
+func plainExcerpt(body string, limit int) string {
+	text := html.UnescapeString(htmlTagPattern.ReplaceAllString(body, " "))
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	cut := string([]rune(text)[:limit])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		return cut[:i] + "…"
+	}
+
+	return string([]rune(text)[:limit-1]) + "…"
+}
+
+func randomPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func NewWordPressService(transferService TransferService, contentRepo repository.ContentRepository, userRepo repository.UserRepository, unitOfWork repository.UnitOfWork, r2 cloudflare.CloudflareR2Adapter, cfg *config.Config) WordPressService {
+	return &wordPressService{
+		transferService:   transferService,
+		contentRepository: contentRepo,
+		userRepository:    userRepo,
+		unitOfWork:        unitOfWork,
+		r2:                r2,
+		httpClient:        &http.Client{Timeout: 30 * time.Second},
+		cfg:               cfg,
+	}
+}
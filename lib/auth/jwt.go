@@ -14,9 +14,18 @@ type Jwt interface {
 	VerifyAccessToken(token string) (*entity.JwtData, error)
 }
 
+// Token audiences. Staff and reader tokens share the signing key, so the
+// audience is what keeps a reader token out of the admin API and a staff
+// token out of reader endpoints.
+const (
+	AudienceStaff  = "bwanews-staff"
+	AudienceReader = "bwanews-reader"
+)
+
 type Options struct {
 	signingKey string
 	issuer     string
+	audience   string
 }
 
 // GenerateToken implements Jwt.
@@ -25,6 +34,7 @@ func (o *Options) GenerateToken(data *entity.JwtData) (string, int64, error) {
 	expiresAt := now.Add(time.Hour * 24)
 	data.RegisteredClaims.ExpiresAt = jwt.NewNumericDate(expiresAt)
 	data.RegisteredClaims.Issuer = o.issuer
+	data.RegisteredClaims.Audience = jwt.ClaimStrings{o.audience}
 	data.RegisteredClaims.NotBefore = jwt.NewNumericDate(now)
 	acToken := jwt.NewWithClaims(jwt.SigningMethodHS256, data)
 	accesToken, err := acToken.SignedString([]byte(o.signingKey))
@@ -36,6 +46,7 @@ func (o *Options) GenerateToken(data *entity.JwtData) (string, int64, error) {
 }
 
 // VerifyAccessToken implements Jwt.
+// Tokens issued for another audience are rejected.
 func (o *Options) VerifyAccessToken(token string) (*entity.JwtData, error) {
 	parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -43,7 +54,7 @@ func (o *Options) VerifyAccessToken(token string) (*entity.JwtData, error) {
 		}
 
 		return []byte(o.signingKey), nil
-	})
+	}, jwt.WithAudience(o.audience))
 
 	if err != nil {
 		return nil, err
@@ -55,8 +66,13 @@ func (o *Options) VerifyAccessToken(token string) (*entity.JwtData, error) {
 			return nil, err
 		}
 
+		userID, ok := claims["user_id"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("Token is not valid")
+		}
+
 		jwtData := &entity.JwtData{
-			UserID: claims["user_id"].(float64),
+			UserID: userID,
 		}
 
 		return jwtData, nil
@@ -64,10 +80,22 @@ func (o *Options) VerifyAccessToken(token string) (*entity.JwtData, error) {
 
 	return nil, fmt.Errorf("Token is not valid")
 }
+
+// NewJwt returns the token issuer and verifier of staff users.
 func NewJwt(cfg *config.Config) Jwt {
+	return newJwt(cfg, AudienceStaff)
+}
+
+// NewReaderJwt returns the token issuer and verifier of readers.
+func NewReaderJwt(cfg *config.Config) Jwt {
+	return newJwt(cfg, AudienceReader)
+}
+
+func newJwt(cfg *config.Config, audience string) Jwt {
 	opt := new(Options)
 	opt.signingKey = cfg.App.JwtSecretKey
 	opt.issuer = cfg.App.JwtIssuer
+	opt.audience = audience
 
 	return opt
 }
@@ -0,0 +1,268 @@
+package blocks
+
+import (
+	"bwanews/lib/markup"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+)
+
+// Version is the current version of the block document schema.
+const Version = 1
+
+// Block types.
+const (
+	TypeParagraph = "paragraph"
+	TypeHeading   = "heading"
+	TypeQuote     = "quote"
+	TypePullQuote = "pullquote"
+	TypeImage     = "image"
+	TypeEmbed     = "embed"
+	TypeGallery   = "gallery"
+	TypeRelated   = "related"
+)
+
+// MaxBlocks caps the size of one document.
+const MaxBlocks = 500
+
+var ErrInvalidDocument = errors.New("invalid block document")
+
+// Document is a structured article body: an ordered list of blocks that
+// front ends can render natively instead of parsing HTML.
+type Document struct {
+	Version int     `json:"version"`
+	Blocks  []Block `json:"blocks"`
+}
+
+// Block is one element of a Document. Which members are used depends on
+// Type:
+//
+//	paragraph  text (inline HTML)
+//	heading    text, level (2-4)
+//	quote      text, cite
+//	pullquote  text, cite
+//	image      url, alt, caption, credit
+//	embed      url, caption
+//	gallery    images, caption
+//	related    title, links
+type Block struct {
+	Type    string  `json:"type"`
+	Text    string  `json:"text,omitempty"`
+	Level   int     `json:"level,omitempty"`
+	Cite    string  `json:"cite,omitempty"`
+	URL     string  `json:"url,omitempty"`
+	Alt     string  `json:"alt,omitempty"`
+	Caption string  `json:"caption,omitempty"`
+	Credit  string  `json:"credit,omitempty"`
+	Title   string  `json:"title,omitempty"`
+	Images  []Image `json:"images,omitempty"`
+	Links   []Link  `json:"links,omitempty"`
+}
+
+type Image struct {
+	URL     string `json:"url"`
+	Alt     string `json:"alt,omitempty"`
+	Caption string `json:"caption,omitempty"`
+}
+
+type Link struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Parse decodes and validates a block document. Unknown members are
+// rejected so typos do not silently drop content, and the inline HTML of
+// paragraphs is sanitized.
+func Parse(data []byte) (*Document, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var doc Document
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDocument, err.Error())
+	}
+
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	for i := range doc.Blocks {
+		if doc.Blocks[i].Type == TypeParagraph {
+			doc.Blocks[i].Text = markup.Sanitize(doc.Blocks[i].Text)
+		}
+	}
+
+	return &doc, nil
+}
+
+// Validate checks the document against the block schema and reports every
+// problem found, each prefixed with the index of the offending block.
+func (d *Document) Validate() error {
+	var problems []string
+	if d.Version != Version {
+		problems = append(problems, fmt.Sprintf("version must be %d", Version))
+	}
+
+	if len(d.Blocks) == 0 {
+		problems = append(problems, "blocks cannot be empty")
+	}
+
+	if len(d.Blocks) > MaxBlocks {
+		problems = append(problems, fmt.Sprintf("blocks cannot have more than %d items", MaxBlocks))
+	}
+
+	for i, b := range d.Blocks {
+		for _, problem := range b.validate() {
+			problems = append(problems, fmt.Sprintf("blocks[%d]: %s", i, problem))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidDocument, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+func (b Block) validate() []string {
+	var problems []string
+	required := func(name, value string) {
+		if strings.TrimSpace(value) == "" {
+			problems = append(problems, name+" is required")
+		}
+	}
+
+	link := func(name, value string) {
+		if !isHTTPURL(value) {
+			problems = append(problems, name+" must be an http(s) URL")
+		}
+	}
+
+	switch b.Type {
+	case TypeParagraph:
+		required("text", b.Text)
+	case TypeHeading:
+		required("text", b.Text)
+		if b.Level < 2 || b.Level > 4 {
+			problems = append(problems, "level must be between 2 and 4")
+		}
+	case TypeQuote, TypePullQuote:
+		required("text", b.Text)
+	case TypeImage:
+		link("url", b.URL)
+	case TypeEmbed:
+		link("url", b.URL)
+	case TypeGallery:
+		if len(b.Images) < 2 {
+			problems = append(problems, "images must have at least 2 items")
+		}
+
+		for i, image := range b.Images {
+			link(fmt.Sprintf("images[%d].url", i), image.URL)
+		}
+	case TypeRelated:
+		if len(b.Links) == 0 {
+			problems = append(problems, "links cannot be empty")
+		}
+
+		for i, l := range b.Links {
+			required(fmt.Sprintf("links[%d].title", i), l.Title)
+			if !isHTTPURL(l.URL) && !isPath(l.URL) {
+				problems = append(problems, fmt.Sprintf("links[%d].url must be an http(s) URL or an absolute path", i))
+			}
+		}
+	case "":
+		problems = append(problems, "type is required")
+	default:
+		problems = append(problems, fmt.Sprintf("unknown type %q", b.Type))
+	}
+
+	return problems
+}
+
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isPath reports whether value is a path on this site. Browsers treat a
+// backslash like a slash and drop tabs and newlines, so "/\evil.example"
+// would leave the site just like "//evil.example"; none of them are allowed.
+func isPath(value string) bool {
+	return strings.HasPrefix(value, "/") &&
+		!strings.HasPrefix(value, "//") &&
+		!strings.ContainsAny(value, "\\\t\r\n")
+}
+
+// Render turns the document into sanitized HTML for consumers that only
+// understand an HTML body. Embeds render as plain links.
+func Render(doc *Document) string {
+	var sb strings.Builder
+	for _, b := range doc.Blocks {
+		renderBlock(&sb, b)
+		sb.WriteString("\n")
+	}
+
+	return markup.Sanitize(sb.String())
+}
+
+func renderBlock(sb *strings.Builder, b Block) {
+	esc := html.EscapeString
+	switch b.Type {
+	case TypeParagraph:
+		sb.WriteString("<p>" + b.Text + "</p>")
+	case TypeHeading:
+		fmt.Fprintf(sb, "<h%d>%s</h%d>", b.Level, esc(b.Text), b.Level)
+	case TypeQuote, TypePullQuote:
+		class := ""
+		if b.Type == TypePullQuote {
+			class = ` class="pullquote"`
+		}
+
+		sb.WriteString("<blockquote" + class + "><p>" + esc(b.Text) + "</p>")
+		if b.Cite != "" {
+			sb.WriteString("<cite>" + esc(b.Cite) + "</cite>")
+		}
+		sb.WriteString("</blockquote>")
+	case TypeImage:
+		sb.WriteString(`<figure><img src="` + esc(b.URL) + `" alt="` + esc(b.Alt) + `">`)
+		caption := strings.TrimSpace(strings.Join([]string{b.Caption, b.Credit}, " "))
+		if caption != "" {
+			sb.WriteString("<figcaption>" + esc(caption) + "</figcaption>")
+		}
+		sb.WriteString("</figure>")
+	case TypeEmbed:
+		sb.WriteString(`<figure class="embed"><a href="` + esc(b.URL) + `">` + esc(b.URL) + `</a>`)
+		if b.Caption != "" {
+			sb.WriteString("<figcaption>" + esc(b.Caption) + "</figcaption>")
+		}
+		sb.WriteString("</figure>")
+	case TypeGallery:
+		sb.WriteString(`<figure class="gallery">`)
+		for _, image := range b.Images {
+			sb.WriteString(`<figure><img src="` + esc(image.URL) + `" alt="` + esc(image.Alt) + `">`)
+			if image.Caption != "" {
+				sb.WriteString("<figcaption>" + esc(image.Caption) + "</figcaption>")
+			}
+			sb.WriteString("</figure>")
+		}
+		if b.Caption != "" {
+			sb.WriteString("<figcaption>" + esc(b.Caption) + "</figcaption>")
+		}
+		sb.WriteString("</figure>")
+	case TypeRelated:
+		sb.WriteString(`<aside class="related">`)
+		if b.Title != "" {
+			sb.WriteString("<h4>" + esc(b.Title) + "</h4>")
+		}
+		sb.WriteString("<ul>")
+		for _, l := range b.Links {
+			sb.WriteString(`<li><a href="` + esc(l.URL) + `">` + esc(l.Title) + `</a></li>`)
+		}
+		sb.WriteString("</ul></aside>")
+	}
+}
@@ -0,0 +1,194 @@
+package blocks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:  "paragraph and heading",
+			input: `{"version":1,"blocks":[{"type":"heading","text":"Judul","level":2},{"type":"paragraph","text":"Isi"}]}`,
+		},
+		{
+			name:  "gallery",
+			input: `{"version":1,"blocks":[{"type":"gallery","images":[{"url":"https://cdn.example/a.jpg"},{"url":"https://cdn.example/b.jpg"}]}]}`,
+		},
+		{
+			name:  "related with absolute URL and path",
+			input: `{"version":1,"blocks":[{"type":"related","links":[{"title":"A","url":"https://news.example/a"},{"title":"B","url":"/politik/b"}]}]}`,
+		},
+		{
+			name:    "malformed JSON",
+			input:   `{"version":1,"blocks":[`,
+			wantErr: "unexpected EOF",
+		},
+		{
+			name:    "unknown member",
+			input:   `{"version":1,"blocks":[{"type":"paragraph","txt":"Isi"}]}`,
+			wantErr: `unknown field "txt"`,
+		},
+		{
+			name:    "wrong version",
+			input:   `{"version":2,"blocks":[{"type":"paragraph","text":"Isi"}]}`,
+			wantErr: "version must be 1",
+		},
+		{
+			name:    "no blocks",
+			input:   `{"version":1,"blocks":[]}`,
+			wantErr: "blocks cannot be empty",
+		},
+		{
+			name:    "missing type",
+			input:   `{"version":1,"blocks":[{"text":"Isi"}]}`,
+			wantErr: "blocks[0]: type is required",
+		},
+		{
+			name:    "unknown type",
+			input:   `{"version":1,"blocks":[{"type":"video"}]}`,
+			wantErr: `blocks[0]: unknown type "video"`,
+		},
+		{
+			name:    "empty paragraph",
+			input:   `{"version":1,"blocks":[{"type":"paragraph","text":"  "}]}`,
+			wantErr: "blocks[0]: text is required",
+		},
+		{
+			name:    "heading level out of range",
+			input:   `{"version":1,"blocks":[{"type":"heading","text":"Judul","level":1}]}`,
+			wantErr: "blocks[0]: level must be between 2 and 4",
+		},
+		{
+			name:    "image without http URL",
+			input:   `{"version":1,"blocks":[{"type":"image","url":"javascript:alert(1)"}]}`,
+			wantErr: "blocks[0]: url must be an http(s) URL",
+		},
+		{
+			name:    "gallery with one image",
+			input:   `{"version":1,"blocks":[{"type":"gallery","images":[{"url":"https://cdn.example/a.jpg"}]}]}`,
+			wantErr: "blocks[0]: images must have at least 2 items",
+		},
+		{
+			name:    "related without links",
+			input:   `{"version":1,"blocks":[{"type":"related","title":"Baca juga"}]}`,
+			wantErr: "blocks[0]: links cannot be empty",
+		},
+		{
+			name:    "related with protocol-relative URL",
+			input:   `{"version":1,"blocks":[{"type":"related","links":[{"title":"A","url":"//evil.example/a"}]}]}`,
+			wantErr: "blocks[0]: links[0].url must be an http(s) URL or an absolute path",
+		},
+		{
+			name:    "related with backslash after slash",
+			input:   `{"version":1,"blocks":[{"type":"related","links":[{"title":"A","url":"/\\evil.example/a"}]}]}`,
+			wantErr: "blocks[0]: links[0].url must be an http(s) URL or an absolute path",
+		},
+		{
+			name:    "related with tab between slashes",
+			input:   `{"version":1,"blocks":[{"type":"related","links":[{"title":"A","url":"/\t/evil.example/a"}]}]}`,
+			wantErr: "blocks[0]: links[0].url must be an http(s) URL or an absolute path",
+		},
+		{
+			name:    "related with relative path",
+			input:   `{"version":1,"blocks":[{"type":"related","links":[{"title":"A","url":"politik/a"}]}]}`,
+			wantErr: "blocks[0]: links[0].url must be an http(s) URL or an absolute path",
+		},
+		{
+			name:    "problems of every block are reported",
+			input:   `{"version":1,"blocks":[{"type":"paragraph"},{"type":"embed","url":"ftp://files.example/a"}]}`,
+			wantErr: "blocks[0]: text is required; blocks[1]: url must be an http(s) URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse([]byte(tt.input))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				if doc == nil {
+					t.Fatal("Parse() returned a nil document")
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidDocument) {
+				t.Fatalf("Parse() error = %v, want ErrInvalidDocument", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseSanitizesParagraphs(t *testing.T) {
+	doc, err := Parse([]byte(`{"version":1,"blocks":[{"type":"paragraph","text":"<b>Isi</b><script>alert(1)</script>"}]}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := doc.Blocks[0].Text; got != "<b>Isi</b>" {
+		t.Errorf("paragraph text = %q, want %q", got, "<b>Isi</b>")
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		block Block
+		want  string
+	}{
+		{
+			name:  "paragraph",
+			block: Block{Type: TypeParagraph, Text: "<em>Isi</em>"},
+			want:  "<p><em>Isi</em></p>",
+		},
+		{
+			name:  "heading escapes text",
+			block: Block{Type: TypeHeading, Text: "A <b>B</b>", Level: 3},
+			want:  "<h3>A &lt;b&gt;B&lt;/b&gt;</h3>",
+		},
+		{
+			name:  "quote with cite",
+			block: Block{Type: TypeQuote, Text: "Kata", Cite: "Budi"},
+			want:  "<blockquote><p>Kata</p><cite>Budi</cite></blockquote>",
+		},
+		{
+			name:  "pullquote",
+			block: Block{Type: TypePullQuote, Text: "Kata"},
+			want:  `<blockquote class="pullquote"><p>Kata</p></blockquote>`,
+		},
+		{
+			name:  "image with caption and credit",
+			block: Block{Type: TypeImage, URL: "https://cdn.example/a.jpg", Alt: "Foto", Caption: "Suasana", Credit: "Antara"},
+			want:  `<figure><img src="https://cdn.example/a.jpg" alt="Foto"><figcaption>Suasana Antara</figcaption></figure>`,
+		},
+		{
+			name:  "embed as link",
+			block: Block{Type: TypeEmbed, URL: "https://video.example/v/1"},
+			want:  `<figure class="embed"><a href="https://video.example/v/1" target="_blank" rel="noopener">https://video.example/v/1</a></figure>`,
+		},
+		{
+			name:  "related links",
+			block: Block{Type: TypeRelated, Title: "Baca juga", Links: []Link{{Title: "A", URL: "/politik/a"}}},
+			want:  `<aside class="related"><h4>Baca juga</h4><ul><li><a href="/politik/a">A</a></li></ul></aside>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.TrimSpace(Render(&Document{Version: Version, Blocks: []Block{tt.block}}))
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is a concurrency-safe in-memory map whose entries expire after a
+// fixed TTL. Expired entries are dropped when they are next read.
+type Cache[V any] struct {
+	mu    sync.RWMutex
+	ttl   time.Duration
+	items map[string]item[V]
+}
+
+type item[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+func New[V any](ttl time.Duration) *Cache[V] {
+	return &Cache[V]{
+		ttl:   ttl,
+		items: map[string]item[V]{},
+	}
+}
+
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.RLock()
+	entry, ok := c.items[key]
+	c.mu.RUnlock()
+
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		c.Delete(key)
+
+		var zero V
+		return zero, false
+	}
+
+	return entry.value, true
+}
+
+func (c *Cache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key] = item[V]{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+func (c *Cache[V]) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, key)
+}
+
+// Clear drops every entry, for changes that affect many keys at once.
+func (c *Cache[V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = map[string]item[V]{}
+}
@@ -1,6 +1,8 @@
 package conv
 
 import (
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -24,6 +26,67 @@ func GenerateSlug(title string) string {
 	return slug
 }
 
+// Default public paths used when the APP_*_PATH settings are empty.
+const (
+	DefaultContentPath  = "/{category}/{slug}"
+	DefaultCategoryPath = "/{category}"
+	DefaultTagPath      = "/tag/{tag}"
+)
+
+// ContentPath fills the {category} and {slug} placeholders of pattern.
+func ContentPath(pattern, categorySlug, slug string) string {
+	if pattern == "" {
+		pattern = DefaultContentPath
+	}
+
+	return strings.NewReplacer("{category}", categorySlug, "{slug}", slug).Replace(pattern)
+}
+
+// SlugFromContentPath is the reverse of ContentPath: it reports the slug
+// of path when path matches pattern.
+func SlugFromContentPath(pattern, path string) (string, bool) {
+	if pattern == "" {
+		pattern = DefaultContentPath
+	}
+
+	expr := strings.NewReplacer(`\{category\}`, `[^/]+`, `\{slug\}`, `(?P<slug>[^/]+)`).Replace(regexp.QuoteMeta(pattern))
+	re, err := regexp.Compile("^" + expr + "/?$")
+	if err != nil {
+		return "", false
+	}
+
+	match := re.FindStringSubmatch(path)
+	index := re.SubexpIndex("slug")
+	if match == nil || index < 0 {
+		return "", false
+	}
+
+	slug, err := url.PathUnescape(match[index])
+	if err != nil {
+		return "", false
+	}
+
+	return slug, true
+}
+
+// CategoryPath fills the {category} placeholder of pattern.
+func CategoryPath(pattern, categorySlug string) string {
+	if pattern == "" {
+		pattern = DefaultCategoryPath
+	}
+
+	return strings.ReplaceAll(pattern, "{category}", categorySlug)
+}
+
+// TagPath fills the {tag} placeholder of pattern with the escaped tag.
+func TagPath(pattern, tag string) string {
+	if pattern == "" {
+		pattern = DefaultTagPath
+	}
+
+	return strings.ReplaceAll(pattern, "{tag}", url.PathEscape(tag))
+}
+
 func StringToInt64(s string) (int64, error) {
 	newData, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
@@ -0,0 +1,203 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+var published = time.Date(2024, 5, 1, 8, 30, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+func sampleFeed(items ...Item) Feed {
+	return Feed{
+		Title:       "Bwanews",
+		Description: "Latest news from Bwanews",
+		Link:        "https://news.example/",
+		FeedURL:     "https://news.example/feed.xml",
+		Language:    "id",
+		Updated:     published,
+		Items:       items,
+	}
+}
+
+func sampleItem() Item {
+	return Item{
+		ID:         "https://news.example/politik/pemilu",
+		Title:      "Pemilu <2024>",
+		Link:       "https://news.example/politik/pemilu",
+		Summary:    "Ringkasan & catatan",
+		Author:     "Budi",
+		Image:      "https://cdn.example/pemilu.jpg",
+		Categories: []string{"Politik", "pemilu"},
+		Published:  published,
+		Updated:    published.Add(time.Hour),
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	full := sampleItem()
+	full.Content = "<p>Isi lengkap</p>"
+
+	tests := []struct {
+		name    string
+		render  func(Feed) ([]byte, error)
+		feed    Feed
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "rss excerpt",
+			render: RSS,
+			feed:   sampleFeed(sampleItem()),
+			want: []string{
+				`<rss version="2.0"`,
+				"<title>Pemilu &lt;2024&gt;</title>",
+				`<guid isPermaLink="true">https://news.example/politik/pemilu</guid>`,
+				"<description><![CDATA[Ringkasan & catatan]]></description>",
+				"<pubDate>Wed, 01 May 2024 01:30:00 +0000</pubDate>",
+				"<lastBuildDate>Wed, 01 May 2024 01:30:00 +0000</lastBuildDate>",
+				"<dc:creator>Budi</dc:creator>",
+				"<category>pemilu</category>",
+				`<enclosure url="https://cdn.example/pemilu.jpg" type="image/jpeg" length="0"></enclosure>`,
+			},
+			notWant: []string{"content:encoded>"},
+		},
+		{
+			name:   "rss full",
+			render: RSS,
+			feed:   sampleFeed(full),
+			want:   []string{"<content:encoded><![CDATA[<p>Isi lengkap</p>]]></content:encoded>"},
+		},
+		{
+			name:    "rss empty",
+			render:  RSS,
+			feed:    Feed{Title: "Bwanews"},
+			notWant: []string{"<item>", "lastBuildDate"},
+		},
+		{
+			name:   "atom excerpt",
+			render: Atom,
+			feed:   sampleFeed(sampleItem()),
+			want: []string{
+				`<feed xmlns="http://www.w3.org/2005/Atom">`,
+				"<updated>2024-05-01T01:30:00Z</updated>",
+				"<published>2024-05-01T01:30:00Z</published>",
+				"<updated>2024-05-01T02:30:00Z</updated>",
+				"<name>Budi</name>",
+				`<category term="Politik"></category>`,
+				`<summary type="html">Ringkasan &amp; catatan</summary>`,
+			},
+			notWant: []string{"<content "},
+		},
+		{
+			name:   "atom full",
+			render: Atom,
+			feed:   sampleFeed(full),
+			want:   []string{`<content type="html">&lt;p&gt;Isi lengkap&lt;/p&gt;</content>`},
+		},
+		{
+			name:    "atom empty",
+			render:  Atom,
+			feed:    Feed{Title: "Bwanews"},
+			want:    []string{"<updated>1970-01-01T00:00:00Z</updated>"},
+			notWant: []string{"<entry>"},
+		},
+		{
+			name:   "json excerpt",
+			render: JSON,
+			feed:   sampleFeed(sampleItem()),
+			want: []string{
+				`"version":"https://jsonfeed.org/version/1.1"`,
+				`"content_text":"Ringkasan \u0026 catatan"`,
+				`"date_published":"2024-05-01T01:30:00Z"`,
+				`"authors":[{"name":"Budi"}]`,
+				`"tags":["Politik","pemilu"]`,
+			},
+			notWant: []string{"content_html"},
+		},
+		{
+			name:    "json full",
+			render:  JSON,
+			feed:    sampleFeed(full),
+			want:    []string{`"content_html":"\u003cp\u003eIsi lengkap\u003c/p\u003e"`},
+			notWant: []string{"content_text"},
+		},
+		{
+			name:   "json empty",
+			render: JSON,
+			feed:   Feed{Title: "Bwanews"},
+			want:   []string{`"items":[]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := tt.render(tt.feed)
+			if err != nil {
+				t.Fatalf("render error = %v", err)
+			}
+
+			if strings.HasPrefix(tt.name, "json") {
+				if !json.Valid(body) {
+					t.Fatalf("render produced invalid JSON: %s", body)
+				}
+			} else if err := xml.Unmarshal(body, new(struct{})); err != nil {
+				t.Fatalf("render produced invalid XML: %v\n%s", err, body)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("render output missing %q\n%s", want, body)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(string(body), notWant) {
+					t.Errorf("render output contains %q\n%s", notWant, body)
+				}
+			}
+		})
+	}
+}
+
+// The handler derives the ETag from the rendered body, so rendering the
+// same feed twice must give the same bytes, even when it has no items. The
+// second round runs after the clock has moved past a second.
+func TestRenderersAreStable(t *testing.T) {
+	renderers := map[string]func(Feed) ([]byte, error){
+		"rss":  RSS,
+		"atom": Atom,
+		"json": JSON,
+	}
+
+	feeds := map[string]Feed{
+		"empty":      {Title: "Bwanews", FeedURL: "https://news.example/feed.xml"},
+		"with items": sampleFeed(sampleItem()),
+	}
+
+	render := func() map[string][]byte {
+		bodies := map[string][]byte{}
+		for renderName, render := range renderers {
+			for feedName, f := range feeds {
+				body, err := render(f)
+				if err != nil {
+					t.Fatalf("%s %s: render error = %v", renderName, feedName, err)
+				}
+				bodies[renderName+" "+feedName] = body
+			}
+		}
+		return bodies
+	}
+
+	first := render()
+	time.Sleep(1100 * time.Millisecond)
+	second := render()
+
+	for name, body := range first {
+		if !bytes.Equal(body, second[name]) {
+			t.Errorf("%s: render output changed between calls:\n%s\n%s", name, body, second[name])
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package live
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Broker wakes the event streams of a live blog when one of its entries
+// changes on this instance. It only signals; streams read the changes from
+// the database, which is also how changes made on other instances reach
+// them.
+type Broker struct {
+	mu   sync.Mutex
+	subs map[int64]map[chan struct{}]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: map[int64]map[chan struct{}]struct{}{}}
+}
+
+// Subscribe returns a channel signalled after changes to topic, and the
+// function that ends the subscription. Signals coalesce while the
+// subscriber is busy.
+func (b *Broker) Subscribe(topic int64) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	b.mu.Lock()
+	if b.subs[topic] == nil {
+		b.subs[topic] = map[chan struct{}]struct{}{}
+	}
+	b.subs[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subs[topic], ch)
+		if len(b.subs[topic]) == 0 {
+			delete(b.subs, topic)
+		}
+	}
+}
+
+// Publish signals every subscriber of topic without blocking.
+func (b *Broker) Publish(topic int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs[topic] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// WriteEvent writes one Server-Sent Event. Each line of data goes out as
+// its own data field, as the format requires.
+func WriteEvent(w *bufio.Writer, id int64, event string, data []byte) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\n", id, event)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if _, err = fmt.Fprintf(w, "data: %s\n", line); err != nil {
+			return err
+		}
+	}
+
+	_, err = w.WriteString("\n")
+	return err
+}
+
+// WriteComment writes an SSE comment, which clients ignore; it keeps idle
+// connections open through proxies and reveals readers that went away.
+func WriteComment(w *bufio.Writer, comment string) error {
+	_, err := fmt.Fprintf(w, ": %s\n\n", comment)
+	return err
+}
@@ -0,0 +1,141 @@
+package markup
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"sort"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// Body formats a content description can be written in.
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+var ErrUnknownFormat = errors.New("unknown body format, use html or markdown")
+
+var (
+	// Raw HTML is let through the Markdown renderer on purpose: the output
+	// always goes through the sanitizer, which decides what survives.
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	policy = newPolicy()
+)
+
+// newPolicy allows the formatting, links, images, lists and tables an
+// article needs. Scripts, styles, event handlers and javascript: URLs are
+// dropped; links to other sites open in a new tab with rel="noopener".
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowElements("figure", "figcaption", "aside")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^(embed|gallery|pullquote|related)$`)).OnElements("figure", "blockquote", "aside")
+	p.RequireNoFollowOnLinks(false)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	p.RequireNoReferrerOnFullyQualifiedLinks(false)
+
+	return p
+}
+
+// ValidFormat reports whether format is a known body format. The empty
+// string counts as valid and means FormatHTML.
+func ValidFormat(format string) bool {
+	return format == "" || format == FormatHTML || format == FormatMarkdown
+}
+
+// Render turns source written in format into sanitized HTML.
+func Render(format, source string) (string, error) {
+	switch format {
+	case "", FormatHTML:
+		return Sanitize(source), nil
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(source), &buf); err != nil {
+			return "", err
+		}
+
+		return Sanitize(buf.String()), nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// Sanitize strips everything outside the allowlist from html.
+func Sanitize(html string) string {
+	return policy.Sanitize(html)
+}
+
+// SanitizeMarkdown runs the raw HTML embedded in Markdown source through
+// the sanitizer and leaves the Markdown around it untouched, so the source
+// itself is as safe to hand out as its rendering.
+func SanitizeMarkdown(source string) string {
+	src := []byte(source)
+	doc := markdown.Parser().Parse(text.NewReader(src))
+
+	var spans []text.Segment
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.RawHTML:
+			for i := 0; i < node.Segments.Len(); i++ {
+				spans = append(spans, node.Segments.At(i))
+			}
+		case *ast.HTMLBlock:
+			for i := 0; i < node.Lines().Len(); i++ {
+				spans = append(spans, node.Lines().At(i))
+			}
+			if node.HasClosure() {
+				spans = append(spans, node.ClosureLine)
+			}
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	if len(spans) == 0 {
+		return source
+	}
+
+	// Adjacent spans are sanitized together so that a tag spread over
+	// several lines, or a script block with its body, is judged whole.
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	merged := []text.Segment{spans[0]}
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if span.Start <= last.Stop {
+			if span.Stop > last.Stop {
+				last.Stop = span.Stop
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+
+	var buf bytes.Buffer
+	prev := 0
+	for _, span := range merged {
+		raw := src[span.Start:span.Stop]
+		trimmed := bytes.TrimRight(raw, " \t\r\n")
+
+		buf.Write(src[prev:span.Start])
+		buf.WriteString(Sanitize(string(trimmed)))
+		buf.Write(raw[len(trimmed):])
+		prev = span.Stop
+	}
+	buf.Write(src[prev:])
+
+	return buf.String()
+}
@@ -0,0 +1,14 @@
+//go:build !(linux || darwin)
+
+package middleware
+
+import (
+	"net"
+	"time"
+)
+
+// watchDisconnect is a no-op where sockets cannot be peeked; requests then
+// only end with their timeout or the server.
+func watchDisconnect(conn net.Conn, interval time.Duration, cancel func()) (stop func()) {
+	return func() {}
+}
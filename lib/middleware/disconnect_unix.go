@@ -0,0 +1,67 @@
+//go:build linux || darwin
+
+package middleware
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+// watchDisconnect calls cancel once the client at the other end of conn has
+// closed it, checking every interval until stop is called. fasthttp gives
+// handlers no close notification, so the socket is peeked without blocking:
+// a read of zero bytes is the client's FIN.
+func watchDisconnect(conn net.Conn, interval time.Duration, cancel func()) (stop func()) {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return func() {}
+	}
+
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		buf := make([]byte, 1)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			closed, pending := false, false
+			raw.Read(func(fd uintptr) bool {
+				n, _, err := syscall.Recvfrom(int(fd), buf, syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+				switch {
+				case errors.Is(err, syscall.EAGAIN), errors.Is(err, syscall.EINTR):
+				case err != nil:
+					closed = true
+				case n == 0:
+					closed = true
+				default:
+					// A pipelined request; it is left for fasthttp.
+					pending = true
+				}
+				return true
+			})
+
+			if closed {
+				cancel()
+				return
+			}
+			if pending {
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
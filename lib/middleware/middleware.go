@@ -4,17 +4,29 @@ import (
 	"bwanews/config"
 	"bwanews/internal/adapter/handler/response"
 	"bwanews/lib/auth"
+	"context"
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// disconnectInterval is how often a running request checks whether its
+// client has gone.
+const disconnectInterval = time.Second
+
 type Middleware interface {
 	CheckToken() fiber.Handler
+	CheckReaderToken() fiber.Handler
+	OptionalReaderToken() fiber.Handler
+	RequestContext(parent context.Context, untimed ...string) fiber.Handler
 }
 
 type Options struct {
-	authJwt auth.Jwt
+	authJwt        auth.Jwt
+	readerJwt      auth.Jwt
+	requestTimeout time.Duration
 }
 
 // CheckToken implements Middleware.
@@ -43,9 +55,108 @@ func (o *Options) CheckToken() func(*fiber.Ctx) error {
 	}
 }
 
+// CheckReaderToken implements Middleware.
+// It requires a reader token and stores its claims in the "reader" local;
+// staff tokens are rejected.
+func (o *Options) CheckReaderToken() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var errorResponse response.ErrorResponseDefault
+		tokenString, ok := strings.CutPrefix(c.Get("Authorization"), "Bearer ")
+		if !ok || tokenString == "" {
+			errorResponse.Meta.Status = false
+			errorResponse.Meta.Message = "Missing Authorization Header"
+			return c.Status(fiber.StatusUnauthorized).JSON(errorResponse)
+		}
+
+		claims, err := o.readerJwt.VerifyAccessToken(tokenString)
+		if err != nil {
+			errorResponse.Meta.Status = false
+			errorResponse.Meta.Message = "Invalid or Expired Token"
+			return c.Status(fiber.StatusUnauthorized).JSON(errorResponse)
+		}
+
+		c.Locals("reader", claims)
+
+		return c.Next()
+	}
+}
+
+// OptionalReaderToken implements Middleware.
+// It is CheckReaderToken for endpoints guests may use too: requests without
+// an Authorization header go through anonymously.
+func (o *Options) OptionalReaderToken() fiber.Handler {
+	checkToken := o.CheckReaderToken()
+	return func(c *fiber.Ctx) error {
+		if c.Get("Authorization") == "" {
+			return c.Next()
+		}
+
+		return checkToken(c)
+	}
+}
+
+// RequestContext implements Middleware.
+// Every request gets a user context derived from parent, so cancelling parent
+// on shutdown aborts in-flight queries and uploads, bounded by the configured
+// request timeout. The context is also cancelled when the client disconnects.
+// A timed out request answers 504 unless the handler already produced a
+// response of its own. Requests to the untimed paths, such as bulk exports
+// and imports, get no timeout; shutdown and disconnects still cancel them.
+func (o *Options) RequestContext(parent context.Context, untimed ...string) fiber.Handler {
+	skip := make(map[string]bool, len(untimed))
+	for _, path := range untimed {
+		skip[strings.ToLower(path)] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		var ctx context.Context
+		var cancel context.CancelFunc
+		// Routing ignores case and a trailing slash, and so does the match.
+		if o.requestTimeout > 0 && !skip[strings.ToLower(strings.TrimSuffix(c.Path(), "/"))] {
+			ctx, cancel = context.WithTimeout(parent, o.requestTimeout)
+		} else {
+			ctx, cancel = context.WithCancel(parent)
+		}
+		defer cancel()
+
+		c.SetUserContext(ctx)
+
+		stop := watchDisconnect(c.Context().Conn(), disconnectInterval, cancel)
+		err := c.Next()
+		stop()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !responded(c, err) {
+			var errorResponse response.ErrorResponseDefault
+			errorResponse.Meta.Status = false
+			errorResponse.Meta.Message = "Request timed out"
+			return c.Status(fiber.StatusGatewayTimeout).JSON(errorResponse)
+		}
+
+		return err
+	}
+}
+
+// responded reports whether the handler left a response worth keeping: it
+// returned no error and wrote a stream or a body with a status below 500.
+// Failures caused by the deadline are replaced with a 504.
+func responded(c *fiber.Ctx, err error) bool {
+	if err != nil {
+		return false
+	}
+
+	resp := c.Response()
+	if resp.IsBodyStream() {
+		return true
+	}
+
+	return len(resp.Body()) > 0 && resp.StatusCode() < fiber.StatusInternalServerError
+}
+
 func NewMiddleware(cfg *config.Config) Middleware {
 	opt := new(Options)
 	opt.authJwt = auth.NewJwt(cfg)
+	opt.readerJwt = auth.NewReaderJwt(cfg)
+	opt.requestTimeout = time.Duration(cfg.App.RequestTimeout) * time.Second
 
 	return opt
 }
@@ -0,0 +1,258 @@
+package oembed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoProvider = errors.New("no oEmbed provider for url")
+	ErrNotFound   = errors.New("oEmbed provider has no embed for url")
+)
+
+// maxResponseSize bounds how much of a provider response is read.
+const maxResponseSize = 1 << 20
+
+// Response is an oEmbed 1.0 response. Providers send width and height as
+// numbers or strings, so both are accepted.
+type Response struct {
+	Type            string `json:"type"`
+	Version         string `json:"version"`
+	Title           string `json:"title,omitempty"`
+	AuthorName      string `json:"author_name,omitempty"`
+	AuthorURL       string `json:"author_url,omitempty"`
+	ProviderName    string `json:"provider_name,omitempty"`
+	ProviderURL     string `json:"provider_url,omitempty"`
+	CacheAge        Number `json:"cache_age,omitempty"`
+	ThumbnailURL    string `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth  Number `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight Number `json:"thumbnail_height,omitempty"`
+	URL             string `json:"url,omitempty"`
+	HTML            string `json:"html,omitempty"`
+	Width           Number `json:"width,omitempty"`
+	Height          Number `json:"height,omitempty"`
+}
+
+// Number is an integer that may be encoded as a JSON number or string.
+type Number int
+
+func (n *Number) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("oembed: invalid number %s", data)
+	}
+
+	*n = Number(f)
+	return nil
+}
+
+// Provider resolves the URLs matching one of its schemes through its
+// oEmbed endpoint. Query is added to every request, for access tokens.
+type Provider struct {
+	Name     string
+	Endpoint string
+	Schemes  []*regexp.Regexp
+	Query    url.Values
+}
+
+// Matches reports whether rawURL is handled by p.
+func (p Provider) Matches(rawURL string) bool {
+	for _, scheme := range p.Schemes {
+		if scheme.MatchString(rawURL) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fetcher calls an oEmbed endpoint. HTTPFetcher is the real
+// implementation; tests can swap in a stub or point providers at a local
+// server.
+type Fetcher interface {
+	Fetch(ctx context.Context, provider Provider, rawURL string) (*Response, error)
+}
+
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+func NewHTTPFetcher(timeout time.Duration) *HTTPFetcher {
+	return &HTTPFetcher{Client: &http.Client{Timeout: timeout}}
+}
+
+// Fetch implements Fetcher.
+func (f *HTTPFetcher) Fetch(ctx context.Context, provider Provider, rawURL string) (*Response, error) {
+	endpoint, err := url.Parse(provider.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	query := endpoint.Query()
+	for key, values := range provider.Query {
+		query[key] = values
+	}
+	query.Set("url", rawURL)
+	query.Set("format", "json")
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %s returned %d", ErrNotFound, provider.Name, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("oembed: %s returned %d", provider.Name, resp.StatusCode)
+	}
+
+	var result Response
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("oembed: %s sent an invalid response: %w", provider.Name, err)
+	}
+
+	if result.Type == "" {
+		return nil, fmt.Errorf("oembed: %s sent a response without a type", provider.Name)
+	}
+
+	return &result, nil
+}
+
+// Registry holds the known providers and resolves URLs through them.
+type Registry struct {
+	providers []Provider
+	fetcher   Fetcher
+}
+
+func NewRegistry(fetcher Fetcher, providers ...Provider) *Registry {
+	return &Registry{providers: providers, fetcher: fetcher}
+}
+
+// Register adds a provider; it is consulted after the existing ones.
+func (r *Registry) Register(provider Provider) {
+	r.providers = append(r.providers, provider)
+}
+
+// Match returns the provider handling rawURL.
+func (r *Registry) Match(rawURL string) (Provider, bool) {
+	for _, provider := range r.providers {
+		if provider.Matches(rawURL) {
+			return provider, true
+		}
+	}
+
+	return Provider{}, false
+}
+
+// Resolve fetches the oEmbed response for rawURL from its provider.
+func (r *Registry) Resolve(ctx context.Context, rawURL string) (Provider, *Response, error) {
+	provider, ok := r.Match(rawURL)
+	if !ok {
+		return Provider{}, nil, ErrNoProvider
+	}
+
+	resp, err := r.fetcher.Fetch(ctx, provider, rawURL)
+	if err != nil {
+		return provider, nil, err
+	}
+
+	return provider, resp, nil
+}
+
+// DefaultProviders returns YouTube, Vimeo, X/Twitter and, when a Facebook
+// app access token is given, Instagram.
+func DefaultProviders(facebookToken string) []Provider {
+	providers := []Provider{
+		{
+			Name:     "YouTube",
+			Endpoint: "https://www.youtube.com/oembed",
+			Schemes: []*regexp.Regexp{
+				regexp.MustCompile(`^https?://(www\.|m\.)?youtube\.com/(watch\?|shorts/|live/)\S+$`),
+				regexp.MustCompile(`^https?://youtu\.be/\S+$`),
+			},
+		},
+		{
+			Name:     "Vimeo",
+			Endpoint: "https://vimeo.com/api/oembed.json",
+			Schemes: []*regexp.Regexp{
+				regexp.MustCompile(`^https?://(www\.)?vimeo\.com/\d+\S*$`),
+			},
+		},
+		{
+			Name:     "X",
+			Endpoint: "https://publish.twitter.com/oembed",
+			Schemes: []*regexp.Regexp{
+				regexp.MustCompile(`^https?://(www\.|mobile\.)?(twitter|x)\.com/\w+/status(es)?/\d+\S*$`),
+			},
+		},
+	}
+
+	if facebookToken != "" {
+		providers = append(providers, Provider{
+			Name:     "Instagram",
+			Endpoint: "https://graph.facebook.com/v18.0/instagram_oembed",
+			Schemes: []*regexp.Regexp{
+				regexp.MustCompile(`^https?://(www\.)?instagram\.com/(p|reel|tv)/[\w-]+/?\S*$`),
+			},
+			Query: url.Values{"access_token": {facebookToken}},
+		})
+	}
+
+	return providers
+}
+
+// standaloneLink finds paragraphs holding nothing but a URL, either as text
+// or as a link whose text is the URL, which is how embeds are pasted.
+var standaloneLink = regexp.MustCompile(`<p>\s*(?:<a [^>]*href="([^"]+)"[^>]*>\s*([^<]*?)\s*</a>|(https?://[^\s<]+))\s*</p>`)
+
+// FindURLs returns, in order and without duplicates, the standalone URLs
+// of an HTML body that a registered provider can embed.
+func (r *Registry) FindURLs(body string) []string {
+	var urls []string
+	seen := map[string]bool{}
+	for _, match := range standaloneLink.FindAllStringSubmatch(body, -1) {
+		candidate := html.UnescapeString(match[3])
+		if match[1] != "" {
+			candidate = html.UnescapeString(match[1])
+			if html.UnescapeString(match[2]) != candidate {
+				continue
+			}
+		}
+
+		if seen[candidate] {
+			continue
+		}
+
+		if _, ok := r.Match(candidate); ok {
+			seen[candidate] = true
+			urls = append(urls, candidate)
+		}
+	}
+
+	return urls
+}
@@ -0,0 +1,248 @@
+package oembed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type stubFetcher struct {
+	calls []string
+	resp  *Response
+	err   error
+}
+
+func (s *stubFetcher) Fetch(ctx context.Context, provider Provider, rawURL string) (*Response, error) {
+	s.calls = append(s.calls, provider.Name+" "+rawURL)
+	return s.resp, s.err
+}
+
+func TestNumberUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Number
+		wantErr bool
+	}{
+		{name: "number", input: `480`, want: 480},
+		{name: "string", input: `"480"`, want: 480},
+		{name: "float", input: `480.0`, want: 480},
+		{name: "empty string", input: `""`, want: 0},
+		{name: "null", input: `null`, want: 0},
+		{name: "not a number", input: `"wide"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Number
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Unmarshal(%s) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryMatch(t *testing.T) {
+	registry := NewRegistry(&stubFetcher{}, DefaultProviders("token")...)
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", want: "YouTube"},
+		{url: "https://youtu.be/dQw4w9WgXcQ", want: "YouTube"},
+		{url: "https://m.youtube.com/shorts/abc123", want: "YouTube"},
+		{url: "https://vimeo.com/76979871", want: "Vimeo"},
+		{url: "https://x.com/jack/status/20", want: "X"},
+		{url: "https://twitter.com/jack/statuses/20", want: "X"},
+		{url: "https://www.instagram.com/p/Cabc-123/", want: "Instagram"},
+		{url: "https://www.youtube.com/channel/abc", want: ""},
+		{url: "https://vimeo.com/channels/staffpicks", want: ""},
+		{url: "https://example.com/watch?v=1", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			provider, ok := registry.Match(tt.url)
+			if ok != (tt.want != "") || provider.Name != tt.want {
+				t.Errorf("Match(%q) = %q, %v, want %q", tt.url, provider.Name, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultProvidersWithoutToken(t *testing.T) {
+	registry := NewRegistry(&stubFetcher{}, DefaultProviders("")...)
+	if _, ok := registry.Match("https://www.instagram.com/p/Cabc/"); ok {
+		t.Error("Instagram registered without a token")
+	}
+}
+
+func TestRegistryFindURLs(t *testing.T) {
+	registry := NewRegistry(&stubFetcher{}, DefaultProviders("")...)
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "bare url",
+			body: `<p>https://youtu.be/abc</p>`,
+			want: []string{"https://youtu.be/abc"},
+		},
+		{
+			name: "linked url",
+			body: `<p><a href="https://vimeo.com/1" rel="nofollow">https://vimeo.com/1</a></p>`,
+			want: []string{"https://vimeo.com/1"},
+		},
+		{
+			name: "escaped query",
+			body: `<p><a href="https://www.youtube.com/watch?v=a&amp;t=1">https://www.youtube.com/watch?v=a&amp;t=1</a></p>`,
+			want: []string{"https://www.youtube.com/watch?v=a&t=1"},
+		},
+		{
+			name: "link with other text",
+			body: `<p><a href="https://youtu.be/abc">watch this</a></p>`,
+		},
+		{
+			name: "url inside a sentence",
+			body: `<p>see https://youtu.be/abc for more</p>`,
+		},
+		{
+			name: "unknown provider",
+			body: `<p>https://example.com/video</p>`,
+		},
+		{
+			name: "duplicates in order",
+			body: `<p>https://vimeo.com/2</p><p>https://youtu.be/abc</p><p>https://vimeo.com/2</p>`,
+			want: []string{"https://vimeo.com/2", "https://youtu.be/abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := registry.FindURLs(tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindURLs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryResolve(t *testing.T) {
+	fetchErr := errors.New("provider down")
+
+	tests := []struct {
+		name      string
+		url       string
+		fetcher   *stubFetcher
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "resolved",
+			url:       "https://youtu.be/abc",
+			fetcher:   &stubFetcher{resp: &Response{Type: "video"}},
+			wantCalls: 1,
+		},
+		{
+			name:      "fetch error",
+			url:       "https://youtu.be/abc",
+			fetcher:   &stubFetcher{err: fetchErr},
+			wantCalls: 1,
+			wantErr:   fetchErr,
+		},
+		{
+			name:    "no provider",
+			url:     "https://example.com/video",
+			fetcher: &stubFetcher{},
+			wantErr: ErrNoProvider,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(tt.fetcher, DefaultProviders("")...)
+			_, resp, err := registry.Resolve(context.Background(), tt.url)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(tt.fetcher.calls) != tt.wantCalls {
+				t.Errorf("Resolve() fetched %d times, want %d", len(tt.fetcher.calls), tt.wantCalls)
+			}
+			if tt.wantErr == nil && resp != tt.fetcher.resp {
+				t.Errorf("Resolve() = %v, want %v", resp, tt.fetcher.resp)
+			}
+		})
+	}
+}
+
+func TestHTTPFetcherFetch(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		wantType     string
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{name: "ok", status: http.StatusOK, body: `{"type":"video","version":"1.0","width":"640"}`, wantType: "video"},
+		{name: "not found", status: http.StatusNotFound, wantNotFound: true, wantErr: true},
+		{name: "forbidden", status: http.StatusForbidden, wantNotFound: true, wantErr: true},
+		{name: "server error", status: http.StatusBadGateway, wantErr: true},
+		{name: "invalid json", status: http.StatusOK, body: `<html>`, wantErr: true},
+		{name: "missing type", status: http.StatusOK, body: `{"version":"1.0"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query map[string][]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query()
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := Provider{
+				Name:     "Test",
+				Endpoint: server.URL + "/oembed?maxwidth=500",
+				Schemes:  []*regexp.Regexp{regexp.MustCompile(`.*`)},
+				Query:    map[string][]string{"access_token": {"secret"}},
+			}
+
+			resp, err := NewHTTPFetcher(time.Second).Fetch(context.Background(), provider, "https://example.com/v/1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrNotFound) != tt.wantNotFound {
+				t.Errorf("Fetch() error = %v, want ErrNotFound %v", err, tt.wantNotFound)
+			}
+
+			wantQuery := map[string][]string{
+				"url":          {"https://example.com/v/1"},
+				"format":       {"json"},
+				"maxwidth":     {"500"},
+				"access_token": {"secret"},
+			}
+			if !reflect.DeepEqual(query, wantQuery) {
+				t.Errorf("Fetch() sent query %v, want %v", query, wantQuery)
+			}
+
+			if tt.wantType != "" && (resp == nil || resp.Type != tt.wantType || resp.Width != 640) {
+				t.Errorf("Fetch() = %+v, want type %q and width 640", resp, tt.wantType)
+			}
+		})
+	}
+}
@@ -3,8 +3,12 @@ package pagination
 import "errors"
 
 var (
-	ErrorMaxPage     = errors.New("page number exceeds maximum page limit")
-	ErrorPage        = errors.New("page must be greater than zero")
-	ErrorPageEmpty   = errors.New("page cannot be empty")
-	ErrorPageInvalid = errors.New("page is invalid, must be a number")
+	ErrorMaxPage       = errors.New("page number exceeds maximum page limit")
+	ErrorPage          = errors.New("page must be greater than zero")
+	ErrorPageEmpty     = errors.New("page cannot be empty")
+	ErrorPageInvalid   = errors.New("page is invalid, must be a number")
+	ErrorLimit         = errors.New("limit must be greater than zero")
+	ErrorMaxLimit      = errors.New("limit exceeds maximum per page limit")
+	ErrorLimitInvalid  = errors.New("limit is invalid, must be a number")
+	ErrorCursorInvalid = errors.New("cursor is invalid")
 )
@@ -2,15 +2,71 @@ package pagination
 
 import (
 	"bwanews/internal/core/domain/entity"
+	"encoding/base64"
+	"fmt"
 	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
 )
 
 type PaginationInterface interface {
+	ParsePage(page, limit string, defaultLimit int) (int, int, error)
 	AddPagination(totalData, page, perPage int) (*entity.Page, error)
+	LinkHeader(uri *url.URL, page *entity.Page) string
+	EncodeCursor(cursor entity.Cursor) string
+	DecodeCursor(cursor string) (*entity.Cursor, error)
 }
 
 type Options struct{}
 
+// ParsePage implements PaginationInterface.
+func (o *Options) ParsePage(page string, limit string, defaultLimit int) (int, int, error) {
+	newPage := 1
+	if page != "" {
+		parsed, err := strconv.Atoi(page)
+		if err != nil {
+			return 0, 0, ErrorPageInvalid
+		}
+
+		if parsed <= 0 {
+			return 0, 0, ErrorPage
+		}
+
+		newPage = parsed
+	}
+
+	newLimit := defaultLimit
+	if newLimit <= 0 {
+		newLimit = DefaultLimit
+	}
+
+	if limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			return 0, 0, ErrorLimitInvalid
+		}
+
+		if parsed <= 0 {
+			return 0, 0, ErrorLimit
+		}
+
+		if parsed > MaxLimit {
+			return 0, 0, ErrorMaxLimit
+		}
+
+		newLimit = parsed
+	}
+
+	return newPage, newLimit, nil
+}
+
 // AddPagination implements PaginationInterface.
 func (o *Options) AddPagination(totalData int, page int, perPage int) (*entity.Page, error) {
 	newPage := page
@@ -19,7 +75,7 @@ func (o *Options) AddPagination(totalData int, page int, perPage int) (*entity.P
 		return nil, ErrorPage
 	}
 
-	limitData := 10
+	limitData := DefaultLimit
 	if perPage > 0 {
 		limitData = perPage
 	}
@@ -33,7 +89,7 @@ func (o *Options) AddPagination(totalData int, page int, perPage int) (*entity.P
 		last = totalData
 	}
 
-	zeroPage := &entity.Page{PageCount: 1, Page: newPage}
+	zeroPage := &entity.Page{PageCount: 1, Page: newPage, Perpage: limitData}
 	if totalData == 0 && newPage == 1 {
 		return zeroPage, nil
 	}
@@ -44,7 +100,7 @@ func (o *Options) AddPagination(totalData int, page int, perPage int) (*entity.P
 
 	pages := &entity.Page{
 		Page:       newPage,
-		Perpage:    perPage,
+		Perpage:    limitData,
 		PageCount:  totalPage,
 		TotalCount: totalData,
 		First:      first,
@@ -54,6 +110,71 @@ func (o *Options) AddPagination(totalData int, page int, perPage int) (*entity.P
 	return pages, nil
 }
 
+// LinkHeader implements PaginationInterface.
+// It returns an RFC 8288 Link header value pointing at the first, previous,
+// next and last pages of uri, keeping every other query parameter as is.
+func (o *Options) LinkHeader(uri *url.URL, page *entity.Page) string {
+	if uri == nil || page == nil {
+		return ""
+	}
+
+	links := []string{}
+	addLink := func(target int, rel string) {
+		query := uri.Query()
+		query.Set("page", strconv.Itoa(target))
+		query.Set("limit", strconv.Itoa(page.Perpage))
+
+		link := *uri
+		link.RawQuery = query.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, link.String(), rel))
+	}
+
+	addLink(1, "first")
+	if page.Page > 1 {
+		addLink(page.Page-1, "prev")
+	}
+
+	if page.Page < page.PageCount {
+		addLink(page.Page+1, "next")
+	}
+
+	addLink(page.PageCount, "last")
+
+	return strings.Join(links, ", ")
+}
+
+// EncodeCursor implements PaginationInterface.
+func (o *Options) EncodeCursor(cursor entity.Cursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatInt(cursor.ID, 10)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor implements PaginationInterface.
+func (o *Options) DecodeCursor(cursor string) (*entity.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrorCursorInvalid
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, ErrorCursorInvalid
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrorCursorInvalid
+	}
+
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id <= 0 {
+		return nil, ErrorCursorInvalid
+	}
+
+	return &entity.Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
 func NewPagination() PaginationInterface {
 	pagination := new(Options)
 
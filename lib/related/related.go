@@ -0,0 +1,145 @@
+package related
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Weights of the signals a candidate is scored by. Each signal is in
+// [0, 1] before weighting, so a weight is the most that signal can add.
+const (
+	TagWeight      = 4.0
+	CategoryWeight = 2.0
+	TextWeight     = 3.0
+	RecencyWeight  = 1.0
+
+	// RecencyHalfLife is the age at which the recency signal is halved.
+	RecencyHalfLife = 14 * 24 * time.Hour
+)
+
+// stopWords are left out of the text similarity; the site publishes in
+// Indonesian and English.
+var stopWords = map[string]bool{
+	"yang": true, "dan": true, "dari": true, "untuk": true, "dengan": true, "ini": true,
+	"itu": true, "pada": true, "dalam": true, "akan": true, "tidak": true, "ada": true,
+	"the": true, "and": true, "for": true, "with": true, "from": true, "that": true,
+	"this": true, "are": true, "was": true, "how": true, "what": true, "why": true,
+}
+
+// Score rates how well candidate follows target: the share of target's
+// tags it carries, whether it is in the same category, how much the words of
+// their titles and excerpts overlap, and how recent it is at now.
+func Score(target, candidate entity.ContentEntity, now time.Time) float64 {
+	score := TagWeight * tagOverlap(target.Tags, candidate.Tags)
+
+	if target.CategoryID != 0 && target.CategoryID == candidate.CategoryID {
+		score += CategoryWeight
+	}
+
+	score += TextWeight * jaccard(words(target.Title+" "+target.Excerpt), words(candidate.Title+" "+candidate.Excerpt))
+
+	age := now.Sub(candidate.CreatedAt)
+	if age < 0 {
+		age = 0
+	}
+	score += RecencyWeight * math.Pow(0.5, age.Hours()/RecencyHalfLife.Hours())
+
+	return score
+}
+
+// Rank scores candidates against target and returns the best limit of them,
+// highest first. target itself is never returned.
+func Rank(target entity.ContentEntity, candidates []entity.ContentEntity, now time.Time, limit int) []entity.RelatedContentEntity {
+	results := []entity.RelatedContentEntity{}
+	for _, candidate := range candidates {
+		if candidate.ID == target.ID {
+			continue
+		}
+
+		results = append(results, entity.RelatedContentEntity{
+			Content: candidate,
+			Score:   Score(target, candidate, now),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+// NormalizeTags lower-cases and trims tags, dropping empty ones.
+func NormalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	return normalized
+}
+
+// tagOverlap is the share of target tags that candidate also has.
+func tagOverlap(target, candidate []string) float64 {
+	targetTags := NormalizeTags(target)
+	if len(targetTags) == 0 {
+		return 0
+	}
+
+	candidateTags := map[string]bool{}
+	for _, tag := range NormalizeTags(candidate) {
+		candidateTags[tag] = true
+	}
+
+	shared := 0
+	for _, tag := range targetTags {
+		if candidateTags[tag] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(targetTags))
+}
+
+// words splits text into its distinct lower-cased words, skipping stop
+// words and words shorter than three letters.
+func words(text string) map[string]bool {
+	set := map[string]bool{}
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range fields {
+		if len([]rune(word)) >= 3 && !stopWords[word] {
+			set[word] = true
+		}
+	}
+
+	return set
+}
+
+// jaccard is the size of the intersection of a and b over their union.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
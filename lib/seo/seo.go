@@ -0,0 +1,109 @@
+package seo
+
+import (
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxHeadlineLength is the longest headline Google shows for a
+// NewsArticle; longer titles are cut.
+const MaxHeadlineLength = 110
+
+// Article describes a news article for its schema.org JSON-LD block.
+type Article struct {
+	URL           string
+	Headline      string
+	Description   string
+	Images        []string
+	Published     time.Time
+	Modified      time.Time
+	Authors       []string
+	Section       string
+	Keywords      []string
+	Language      string
+	PublisherName string
+	PublisherLogo string
+}
+
+type thing struct {
+	Type string `json:"@type"`
+	ID   string `json:"@id,omitempty"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+	Logo *thing `json:"logo,omitempty"`
+}
+
+type newsArticle struct {
+	Context          string   `json:"@context"`
+	Type             string   `json:"@type"`
+	MainEntityOfPage *thing   `json:"mainEntityOfPage,omitempty"`
+	Headline         string   `json:"headline"`
+	Description      string   `json:"description,omitempty"`
+	Image            []string `json:"image,omitempty"`
+	DatePublished    string   `json:"datePublished"`
+	DateModified     string   `json:"dateModified,omitempty"`
+	Author           []thing  `json:"author,omitempty"`
+	Publisher        *thing   `json:"publisher,omitempty"`
+	ArticleSection   string   `json:"articleSection,omitempty"`
+	Keywords         []string `json:"keywords,omitempty"`
+	InLanguage       string   `json:"inLanguage,omitempty"`
+}
+
+// NewsArticle renders a as a schema.org NewsArticle JSON-LD document.
+// HTML characters are escaped so the result can be placed in a script tag
+// as is.
+func NewsArticle(a Article) ([]byte, error) {
+	doc := newsArticle{
+		Context:        "https://schema.org",
+		Type:           "NewsArticle",
+		Headline:       truncate(a.Headline, MaxHeadlineLength),
+		Description:    a.Description,
+		DatePublished:  a.Published.UTC().Format(time.RFC3339),
+		ArticleSection: a.Section,
+		InLanguage:     a.Language,
+	}
+
+	if a.URL != "" {
+		doc.MainEntityOfPage = &thing{Type: "WebPage", ID: a.URL}
+	}
+
+	for _, image := range a.Images {
+		if image != "" {
+			doc.Image = append(doc.Image, image)
+		}
+	}
+
+	if !a.Modified.IsZero() {
+		doc.DateModified = a.Modified.UTC().Format(time.RFC3339)
+	}
+
+	for _, author := range a.Authors {
+		if author != "" {
+			doc.Author = append(doc.Author, thing{Type: "Person", Name: author})
+		}
+	}
+
+	if a.PublisherName != "" {
+		doc.Publisher = &thing{Type: "Organization", Name: a.PublisherName}
+		if a.PublisherLogo != "" {
+			doc.Publisher.Logo = &thing{Type: "ImageObject", URL: a.PublisherLogo}
+		}
+	}
+
+	for _, keyword := range a.Keywords {
+		if keyword != "" {
+			doc.Keywords = append(doc.Keywords, keyword)
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+
+	return string([]rune(s)[:limit-1]) + "…"
+}
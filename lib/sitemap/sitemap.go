@@ -0,0 +1,124 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs is the protocol limit of URLs in one sitemap file; MaxNewsURLs
+// is Google's limit for a news sitemap.
+const (
+	MaxURLs     = 50000
+	MaxNewsURLs = 1000
+)
+
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// NewsURL is an article entry of a Google News sitemap.
+type NewsURL struct {
+	Loc             string
+	Title           string
+	PublicationDate time.Time
+}
+
+// Publication names the news outlet in a Google News sitemap.
+type Publication struct {
+	Name     string
+	Language string
+}
+
+type urlSet struct {
+	XMLName xml.Name   `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []urlEntry `xml:"url"`
+}
+
+type urlEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name   `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []urlEntry `xml:"sitemap"`
+}
+
+type newsURLSet struct {
+	XMLName xml.Name       `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	NewsNS  string         `xml:"xmlns:news,attr"`
+	URLs    []newsURLEntry `xml:"url"`
+}
+
+type newsURLEntry struct {
+	Loc  string    `xml:"loc"`
+	News newsEntry `xml:"news:news"`
+}
+
+type newsEntry struct {
+	Publication     newsPublication `xml:"news:publication"`
+	PublicationDate string          `xml:"news:publication_date"`
+	Title           string          `xml:"news:title"`
+}
+
+type newsPublication struct {
+	Name     string `xml:"news:name"`
+	Language string `xml:"news:language"`
+}
+
+// URLSet renders a sitemap listing page URLs.
+func URLSet(urls []URL) ([]byte, error) {
+	doc := urlSet{URLs: entries(urls)}
+	return marshal(doc)
+}
+
+// Index renders a sitemap index pointing at other sitemaps.
+func Index(sitemaps []URL) ([]byte, error) {
+	doc := sitemapIndex{Sitemaps: entries(sitemaps)}
+	return marshal(doc)
+}
+
+// News renders a Google News sitemap.
+func News(publication Publication, urls []NewsURL) ([]byte, error) {
+	doc := newsURLSet{
+		NewsNS: "http://www.google.com/schemas/sitemap-news/0.9",
+		URLs:   []newsURLEntry{},
+	}
+
+	for _, u := range urls {
+		doc.URLs = append(doc.URLs, newsURLEntry{
+			Loc: u.Loc,
+			News: newsEntry{
+				Publication:     newsPublication{Name: publication.Name, Language: publication.Language},
+				PublicationDate: u.PublicationDate.UTC().Format(time.RFC3339),
+				Title:           u.Title,
+			},
+		})
+	}
+
+	return marshal(doc)
+}
+
+func entries(urls []URL) []urlEntry {
+	result := make([]urlEntry, 0, len(urls))
+	for _, u := range urls {
+		entry := urlEntry{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			entry.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+
+		result = append(result, entry)
+	}
+
+	return result
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
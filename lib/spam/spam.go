@@ -0,0 +1,237 @@
+package spam
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Scores at which a comment is held for moderation or filed as spam.
+const (
+	HoldScore = 50
+	SpamScore = 100
+)
+
+// Flag is what a filter reports about a suspicious comment. Reason is shown
+// to moderators, so it should say what was found.
+type Flag struct {
+	Score  int
+	Reason string
+}
+
+// Filter inspects a comment before it is stored and returns nil when it
+// finds nothing wrong.
+type Filter interface {
+	Check(ctx context.Context, comment entity.CommentEntity) (*Flag, error)
+}
+
+// FilterFunc adapts a function to the Filter interface.
+type FilterFunc func(ctx context.Context, comment entity.CommentEntity) (*Flag, error)
+
+// Check implements Filter.
+func (f FilterFunc) Check(ctx context.Context, comment entity.CommentEntity) (*Flag, error) {
+	return f(ctx, comment)
+}
+
+// CountFunc counts the stored comments matching a query; the duplicate and
+// rate-limit filters use it so limits hold across API instances.
+type CountFunc func(ctx context.Context, query entity.CommentCountQuery) (int64, error)
+
+// Verdict is the combined result of a chain: the sum of the scores and the
+// reason of every filter that flagged the comment.
+type Verdict struct {
+	Score   int
+	Reasons []string
+}
+
+// Status maps the verdict onto a comment status. Clean comments get
+// cleanStatus, which is approved or pending depending on the site policy.
+func (v Verdict) Status(cleanStatus string) string {
+	switch {
+	case v.Score >= SpamScore:
+		return entity.CommentStatusSpam
+	case v.Score >= HoldScore:
+		return entity.CommentStatusPending
+	default:
+		return cleanStatus
+	}
+}
+
+// Chain runs filters in order and adds up their scores.
+type Chain struct {
+	filters []Filter
+}
+
+func NewChain(filters ...Filter) *Chain {
+	return &Chain{filters: filters}
+}
+
+// Add appends a filter to the chain.
+func (c *Chain) Add(filter Filter) {
+	c.filters = append(c.filters, filter)
+}
+
+// Evaluate runs every filter. It stops at the first error, since a verdict
+// missing a check cannot be trusted.
+func (c *Chain) Evaluate(ctx context.Context, comment entity.CommentEntity) (Verdict, error) {
+	var verdict Verdict
+	for _, filter := range c.filters {
+		flag, err := filter.Check(ctx, comment)
+		if err != nil {
+			return verdict, err
+		}
+
+		if flag != nil {
+			verdict.Score += flag.Score
+			verdict.Reasons = append(verdict.Reasons, flag.Reason)
+		}
+	}
+
+	return verdict, nil
+}
+
+// Honeypot flags comments that filled in the hidden form field only bots
+// see.
+func Honeypot() Filter {
+	return FilterFunc(func(ctx context.Context, comment entity.CommentEntity) (*Flag, error) {
+		if strings.TrimSpace(comment.Honeypot) == "" {
+			return nil, nil
+		}
+
+		return &Flag{Score: SpamScore, Reason: "honeypot field was filled in"}, nil
+	})
+}
+
+// linkPattern matches a whole link, so the "www." of "http://www.…" is not
+// counted a second time.
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S*`)
+
+// LinkLimit holds comments with more than max links.
+func LinkLimit(max int) Filter {
+	return FilterFunc(func(ctx context.Context, comment entity.CommentEntity) (*Flag, error) {
+		links := len(linkPattern.FindAllStringIndex(comment.Body, -1))
+		if links <= max {
+			return nil, nil
+		}
+
+		return &Flag{Score: HoldScore, Reason: fmt.Sprintf("contains %d links, at most %d allowed", links, max)}, nil
+	})
+}
+
+// BannedWords holds comments whose body or author name contains one of
+// words, matched case-insensitively on word boundaries. Empty entries are
+// ignored and a list without words never flags anything.
+func BannedWords(words []string) Filter {
+	var quoted []string
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+
+	if len(quoted) == 0 {
+		return FilterFunc(func(ctx context.Context, comment entity.CommentEntity) (*Flag, error) {
+			return nil, nil
+		})
+	}
+
+	pattern := regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`)
+	return FilterFunc(func(ctx context.Context, comment entity.CommentEntity) (*Flag, error) {
+		found := map[string]bool{}
+		var matches []string
+		for _, match := range pattern.FindAllString(comment.AuthorName+"\n"+comment.Body, -1) {
+			match = strings.ToLower(match)
+			if !found[match] {
+				found[match] = true
+				matches = append(matches, match)
+			}
+		}
+
+		if len(matches) == 0 {
+			return nil, nil
+		}
+
+		return &Flag{Score: HoldScore, Reason: "contains banned words: " + strings.Join(matches, ", ")}, nil
+	})
+}
+
+// Duplicate files as spam a comment whose body was already posted from the
+// same IP address, or by the same reader, within window.
+func Duplicate(count CountFunc, window time.Duration) Filter {
+	return FilterFunc(func(ctx context.Context, comment entity.CommentEntity) (*Flag, error) {
+		query := entity.CommentCountQuery{
+			Body:      comment.Body,
+			IPAddress: comment.IPAddress,
+			ReaderID:  comment.ReaderID,
+			Since:     time.Now().Add(-window),
+		}
+
+		if query.ReaderID != nil {
+			query.IPAddress = ""
+		} else if query.IPAddress == "" {
+			return nil, nil
+		}
+
+		total, err := count(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		if total == 0 {
+			return nil, nil
+		}
+
+		return &Flag{Score: SpamScore, Reason: fmt.Sprintf("same comment already posted within %s", window)}, nil
+	})
+}
+
+// RateLimitByIP files as spam the comments of an IP address that already
+// posted limit comments within window.
+func RateLimitByIP(count CountFunc, limit int, window time.Duration) Filter {
+	return FilterFunc(func(ctx context.Context, comment entity.CommentEntity) (*Flag, error) {
+		if comment.IPAddress == "" {
+			return nil, nil
+		}
+
+		total, err := count(ctx, entity.CommentCountQuery{
+			IPAddress: comment.IPAddress,
+			Since:     time.Now().Add(-window),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if total < int64(limit) {
+			return nil, nil
+		}
+
+		return &Flag{Score: SpamScore, Reason: fmt.Sprintf("%d comments from %s within %s, the limit is %d", total, comment.IPAddress, window, limit)}, nil
+	})
+}
+
+// RateLimitByAccount is RateLimitByIP for signed-in readers; guest
+// comments are left to the IP limit.
+func RateLimitByAccount(count CountFunc, limit int, window time.Duration) Filter {
+	return FilterFunc(func(ctx context.Context, comment entity.CommentEntity) (*Flag, error) {
+		if comment.ReaderID == nil {
+			return nil, nil
+		}
+
+		total, err := count(ctx, entity.CommentCountQuery{
+			ReaderID: comment.ReaderID,
+			Since:    time.Now().Add(-window),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if total < int64(limit) {
+			return nil, nil
+		}
+
+		return &Flag{Score: SpamScore, Reason: fmt.Sprintf("%d comments from reader %d within %s, the limit is %d", total, *comment.ReaderID, window, limit)}, nil
+	})
+}
@@ -0,0 +1,310 @@
+package spam
+
+import (
+	"bwanews/internal/core/domain/entity"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func readerID(id int64) *int64 {
+	return &id
+}
+
+func check(t *testing.T, filter Filter, comment entity.CommentEntity) *Flag {
+	t.Helper()
+
+	flag, err := filter.Check(context.Background(), comment)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	return flag
+}
+
+func TestHoneypot(t *testing.T) {
+	tests := []struct {
+		name     string
+		honeypot string
+		want     *Flag
+	}{
+		{name: "empty", honeypot: ""},
+		{name: "whitespace", honeypot: "  \n"},
+		{name: "filled in", honeypot: "http://spam.example", want: &Flag{Score: SpamScore, Reason: "honeypot field was filled in"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := check(t, Honeypot(), entity.CommentEntity{Honeypot: tt.honeypot})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkLimit(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *Flag
+	}{
+		{name: "no links", body: "Setuju dengan artikel ini."},
+		{name: "at the limit", body: "Lihat https://a.example dan www.b.example"},
+		{name: "scheme with www counts once", body: "http://www.a.example dan HTTPS://WWW.b.example"},
+		{name: "www inside a path counts once", body: "https://a.example/www.b dan www.c.example"},
+		{
+			name: "over the limit",
+			body: "https://a.example http://b.example www.c.example",
+			want: &Flag{Score: HoldScore, Reason: "contains 3 links, at most 2 allowed"},
+		},
+		{
+			name: "scheme with www over the limit",
+			body: "http://www.a.example http://www.b.example http://www.c.example",
+			want: &Flag{Score: HoldScore, Reason: "contains 3 links, at most 2 allowed"},
+		},
+		{name: "words ending in www are not links", body: "awww.lucu http:/a.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := check(t, LinkLimit(2), entity.CommentEntity{Body: tt.body})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check(%q) = %+v, want %+v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBannedWords(t *testing.T) {
+	tests := []struct {
+		name   string
+		words  []string
+		author string
+		body   string
+		want   *Flag
+	}{
+		{name: "no words", words: nil, body: "judi online"},
+		{name: "only empty words", words: []string{"", "  "}, body: "judi online"},
+		{name: "clean", words: []string{"judi", "slot"}, body: "Berita bagus."},
+		{name: "word inside another word", words: []string{"slot"}, body: "Slotnya penuh."},
+		{
+			name:  "case-insensitive and deduplicated",
+			words: []string{"judi", " slot "},
+			body:  "JUDI dan Slot, judi lagi",
+			want:  &Flag{Score: HoldScore, Reason: "contains banned words: judi, slot"},
+		},
+		{
+			name:   "author name",
+			words:  []string{"slot"},
+			author: "Agen Slot",
+			body:   "Halo",
+			want:   &Flag{Score: HoldScore, Reason: "contains banned words: slot"},
+		},
+		{
+			name:  "words are quoted",
+			words: []string{"a.b"},
+			body:  "axb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := check(t, BannedWords(tt.words), entity.CommentEntity{AuthorName: tt.author, Body: tt.body})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// counter records the queries it gets and answers with total.
+type counter struct {
+	total   int64
+	err     error
+	queries []entity.CommentCountQuery
+}
+
+func (c *counter) count(ctx context.Context, query entity.CommentCountQuery) (int64, error) {
+	c.queries = append(c.queries, query)
+	return c.total, c.err
+}
+
+func TestCountingFilters(t *testing.T) {
+	window := 10 * time.Minute
+	guest := entity.CommentEntity{Body: "Halo", IPAddress: "203.0.113.7"}
+	reader := entity.CommentEntity{Body: "Halo", IPAddress: "203.0.113.7", ReaderID: readerID(42)}
+
+	tests := []struct {
+		name      string
+		filter    func(CountFunc) Filter
+		comment   entity.CommentEntity
+		total     int64
+		wantQuery *entity.CommentCountQuery
+		want      *Flag
+	}{
+		{
+			name:      "duplicate by guest",
+			filter:    func(count CountFunc) Filter { return Duplicate(count, window) },
+			comment:   guest,
+			total:     1,
+			wantQuery: &entity.CommentCountQuery{Body: "Halo", IPAddress: "203.0.113.7"},
+			want:      &Flag{Score: SpamScore, Reason: "same comment already posted within 10m0s"},
+		},
+		{
+			name:      "duplicate by reader ignores the IP address",
+			filter:    func(count CountFunc) Filter { return Duplicate(count, window) },
+			comment:   reader,
+			total:     1,
+			wantQuery: &entity.CommentCountQuery{Body: "Halo", ReaderID: readerID(42)},
+			want:      &Flag{Score: SpamScore, Reason: "same comment already posted within 10m0s"},
+		},
+		{
+			name:      "first comment is no duplicate",
+			filter:    func(count CountFunc) Filter { return Duplicate(count, window) },
+			comment:   guest,
+			wantQuery: &entity.CommentCountQuery{Body: "Halo", IPAddress: "203.0.113.7"},
+		},
+		{
+			name:    "duplicate without IP address or reader",
+			filter:  func(count CountFunc) Filter { return Duplicate(count, window) },
+			comment: entity.CommentEntity{Body: "Halo"},
+			total:   1,
+		},
+		{
+			name:      "IP address under the limit",
+			filter:    func(count CountFunc) Filter { return RateLimitByIP(count, 5, window) },
+			comment:   guest,
+			total:     4,
+			wantQuery: &entity.CommentCountQuery{IPAddress: "203.0.113.7"},
+		},
+		{
+			name:      "IP address at the limit",
+			filter:    func(count CountFunc) Filter { return RateLimitByIP(count, 5, window) },
+			comment:   guest,
+			total:     5,
+			wantQuery: &entity.CommentCountQuery{IPAddress: "203.0.113.7"},
+			want:      &Flag{Score: SpamScore, Reason: "5 comments from 203.0.113.7 within 10m0s, the limit is 5"},
+		},
+		{
+			name:    "IP limit without IP address",
+			filter:  func(count CountFunc) Filter { return RateLimitByIP(count, 5, window) },
+			comment: entity.CommentEntity{Body: "Halo"},
+			total:   5,
+		},
+		{
+			name:      "reader at the limit",
+			filter:    func(count CountFunc) Filter { return RateLimitByAccount(count, 5, window) },
+			comment:   reader,
+			total:     7,
+			wantQuery: &entity.CommentCountQuery{ReaderID: readerID(42)},
+			want:      &Flag{Score: SpamScore, Reason: "7 comments from reader 42 within 10m0s, the limit is 5"},
+		},
+		{
+			name:    "account limit for guests",
+			filter:  func(count CountFunc) Filter { return RateLimitByAccount(count, 5, window) },
+			comment: guest,
+			total:   7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &counter{total: tt.total}
+			before := time.Now()
+			got := check(t, tt.filter(c.count), tt.comment)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %+v, want %+v", got, tt.want)
+			}
+
+			if tt.wantQuery == nil {
+				if len(c.queries) != 0 {
+					t.Errorf("count called with %+v, want no call", c.queries)
+				}
+				return
+			}
+
+			if len(c.queries) != 1 {
+				t.Fatalf("count called %d times, want 1", len(c.queries))
+			}
+
+			query := c.queries[0]
+			if since := before.Add(-window); query.Since.Before(since.Add(-time.Second)) || query.Since.After(time.Now().Add(-window)) {
+				t.Errorf("query.Since = %v, want about %v", query.Since, since)
+			}
+
+			query.Since = time.Time{}
+			if !reflect.DeepEqual(query, *tt.wantQuery) {
+				t.Errorf("count query = %+v, want %+v", query, *tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestChainEvaluate(t *testing.T) {
+	errCount := errors.New("count failed")
+	flag := func(score int, reason string) Filter {
+		return FilterFunc(func(ctx context.Context, comment entity.CommentEntity) (*Flag, error) {
+			return &Flag{Score: score, Reason: reason}, nil
+		})
+	}
+	failing := FilterFunc(func(ctx context.Context, comment entity.CommentEntity) (*Flag, error) {
+		return nil, errCount
+	})
+
+	tests := []struct {
+		name       string
+		filters    []Filter
+		want       Verdict
+		wantErr    error
+		wantStatus string
+	}{
+		{
+			name:       "clean",
+			filters:    []Filter{Honeypot(), LinkLimit(2)},
+			wantStatus: entity.CommentStatusApproved,
+		},
+		{
+			name:       "held",
+			filters:    []Filter{Honeypot(), flag(HoldScore, "links")},
+			want:       Verdict{Score: HoldScore, Reasons: []string{"links"}},
+			wantStatus: entity.CommentStatusPending,
+		},
+		{
+			name:       "scores add up to spam",
+			filters:    []Filter{flag(HoldScore, "links"), flag(HoldScore, "words")},
+			want:       Verdict{Score: SpamScore, Reasons: []string{"links", "words"}},
+			wantStatus: entity.CommentStatusSpam,
+		},
+		{
+			name:    "error stops the chain",
+			filters: []Filter{flag(HoldScore, "links"), failing, flag(HoldScore, "words")},
+			want:    Verdict{Score: HoldScore, Reasons: []string{"links"}},
+			wantErr: errCount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := NewChain(tt.filters[0])
+			for _, filter := range tt.filters[1:] {
+				chain.Add(filter)
+			}
+
+			got, err := chain.Evaluate(context.Background(), entity.CommentEntity{Body: "Halo"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Evaluate() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantErr == nil {
+				if status := got.Status(entity.CommentStatusApproved); status != tt.wantStatus {
+					t.Errorf("Status() = %q, want %q", status, tt.wantStatus)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package transfer
+
+import (
+	"bufio"
+	"bwanews/internal/core/domain/entity"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Encoder writes transfer records one by one; Flush must be called once
+// the last record is written.
+type Encoder interface {
+	Encode(record entity.TransferRecord) error
+	Flush() error
+}
+
+// Decoder reads transfer records one by one and returns io.EOF after the
+// last one. Line reports where the record returned last started.
+type Decoder interface {
+	Decode() (entity.TransferRecord, error)
+	Line() int
+}
+
+var columns = []string{
+	"type", "slug", "title", "excerpt", "description", "body_format", "image",
+	"tags", "status", "category_slug", "author_email", "created_at", "updated_at",
+	"blocks", "content_type",
+}
+
+type record struct {
+	Type         string    `json:"type"`
+	Slug         string    `json:"slug"`
+	Title        string    `json:"title"`
+	Excerpt      string    `json:"excerpt,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	BodyFormat   string    `json:"body_format,omitempty"`
+	Image        string    `json:"image,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	CategorySlug string    `json:"category_slug,omitempty"`
+	AuthorEmail  string    `json:"author_email,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	Blocks      json.RawMessage `json:"blocks,omitempty"`
+	ContentType string          `json:"content_type,omitempty"`
+}
+
+func NewEncoder(w io.Writer, format string) (Encoder, error) {
+	switch format {
+	case entity.TransferFormatJSONL:
+		buf := bufio.NewWriter(w)
+		return &jsonlEncoder{buf: buf, enc: json.NewEncoder(buf)}, nil
+	case entity.TransferFormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	}
+
+	return nil, entity.ErrUnknownFormat
+}
+
+func NewDecoder(r io.Reader, format string) (Decoder, error) {
+	switch format {
+	case entity.TransferFormatJSONL:
+		return &jsonlDecoder{r: bufio.NewReader(r)}, nil
+	case entity.TransferFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		return &csvDecoder{r: reader}, nil
+	}
+
+	return nil, entity.ErrUnknownFormat
+}
+
+// NewSliceDecoder decodes records that are already in memory, such as those
+// converted from another system. Line reports the 1-based record index.
+func NewSliceDecoder(records []entity.TransferRecord) Decoder {
+	return &sliceDecoder{records: records}
+}
+
+// FormatFromFilename guesses the format from a file extension, defaulting
+// to JSON Lines.
+func FormatFromFilename(name string) string {
+	if strings.HasSuffix(strings.ToLower(name), ".csv") {
+		return entity.TransferFormatCSV
+	}
+
+	return entity.TransferFormatJSONL
+}
+
+type jsonlEncoder struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func (e *jsonlEncoder) Encode(rec entity.TransferRecord) error {
+	return e.enc.Encode(record(rec))
+}
+
+func (e *jsonlEncoder) Flush() error {
+	return e.buf.Flush()
+}
+
+type jsonlDecoder struct {
+	r    *bufio.Reader
+	line int
+}
+
+func (d *jsonlDecoder) Decode() (entity.TransferRecord, error) {
+	for {
+		raw, err := d.r.ReadBytes('\n')
+		if len(raw) == 0 && err != nil {
+			return entity.TransferRecord{}, err
+		}
+
+		d.line++
+		raw = bytes.TrimSpace(raw)
+		if len(raw) == 0 {
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal(raw, &rec); err != nil {
+			return entity.TransferRecord{}, fmt.Errorf("line %d: %w", d.line, err)
+		}
+
+		return entity.TransferRecord(rec), nil
+	}
+}
+
+func (d *jsonlDecoder) Line() int {
+	return d.line
+}
+
+type sliceDecoder struct {
+	records []entity.TransferRecord
+	line    int
+}
+
+func (d *sliceDecoder) Decode() (entity.TransferRecord, error) {
+	if d.line >= len(d.records) {
+		return entity.TransferRecord{}, io.EOF
+	}
+
+	d.line++
+	return d.records[d.line-1], nil
+}
+
+func (d *sliceDecoder) Line() int {
+	return d.line
+}
+
+type csvEncoder struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (e *csvEncoder) Encode(rec entity.TransferRecord) error {
+	if !e.header {
+		e.header = true
+		if err := e.w.Write(columns); err != nil {
+			return err
+		}
+	}
+
+	return e.w.Write([]string{
+		rec.Type, rec.Slug, rec.Title, rec.Excerpt, rec.Description, rec.BodyFormat,
+		rec.Image, strings.Join(rec.Tags, ","), rec.Status, rec.CategorySlug, rec.AuthorEmail,
+		formatTime(rec.CreatedAt), formatTime(rec.UpdatedAt), string(rec.Blocks), rec.ContentType,
+	})
+}
+
+func (e *csvEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvDecoder matches columns by the header row, so their order is free and
+// unknown columns are ignored.
+type csvDecoder struct {
+	r     *csv.Reader
+	index map[string]int
+	line  int
+}
+
+func (d *csvDecoder) Decode() (entity.TransferRecord, error) {
+	if d.index == nil {
+		header, err := d.r.Read()
+		if err != nil {
+			return entity.TransferRecord{}, err
+		}
+
+		d.index = make(map[string]int, len(header))
+		for i, name := range header {
+			d.index[strings.TrimSpace(name)] = i
+		}
+	}
+
+	row, err := d.r.Read()
+	if err != nil {
+		return entity.TransferRecord{}, err
+	}
+
+	d.line, _ = d.r.FieldPos(0)
+	field := func(name string) string {
+		i, ok := d.index[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+
+		return row[i]
+	}
+
+	rec := entity.TransferRecord{
+		Type:         field("type"),
+		Slug:         field("slug"),
+		Title:        field("title"),
+		Excerpt:      field("excerpt"),
+		Description:  field("description"),
+		BodyFormat:   field("body_format"),
+		Blocks:       json.RawMessage(field("blocks")),
+		Image:        field("image"),
+		Status:       field("status"),
+		CategorySlug: field("category_slug"),
+		AuthorEmail:  field("author_email"),
+		ContentType:  field("content_type"),
+	}
+
+	if tags := field("tags"); tags != "" {
+		rec.Tags = strings.Split(tags, ",")
+	}
+
+	if rec.CreatedAt, err = parseTime(field("created_at")); err != nil {
+		return entity.TransferRecord{}, fmt.Errorf("line %d: %w", d.line, err)
+	}
+
+	if rec.UpdatedAt, err = parseTime(field("updated_at")); err != nil {
+		return entity.TransferRecord{}, fmt.Errorf("line %d: %w", d.line, err)
+	}
+
+	return rec, nil
+}
+
+func (d *csvDecoder) Line() int {
+	return d.line
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, s)
+}
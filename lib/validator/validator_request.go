@@ -24,6 +24,14 @@ func ValidateStruct(s interface{}) error {
 				errorMessages = append(errorMessages, err.Field()+" must be a valid email")
 			case "min":
 				errorMessages = append(errorMessages, err.Field()+" must be at least "+err.Param()+" characters long")
+			case "max":
+				errorMessages = append(errorMessages, err.Field()+" must be at most "+err.Param()+" characters long")
+			case "required_without":
+				errorMessages = append(errorMessages, err.Field()+" is required when "+err.Param()+" is empty")
+			case "oneof":
+				errorMessages = append(errorMessages, err.Field()+" must be one of: "+err.Param())
+			case "url", "http_url":
+				errorMessages = append(errorMessages, err.Field()+" must be a valid URL")
 			case "eqfield":
 				errorMessages = append(errorMessages, err.Field()+" must be equal to "+err.Param())
 			default:
@@ -0,0 +1,176 @@
+package views
+
+import (
+	"container/list"
+	"context"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var botPattern = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|archiver|facebookexternalhit|embedly|preview|headless|lighthouse|pingdom|uptime|monitor|curl|wget|python|go-http-client|java/|okhttp|httpclient|scrapy`)
+
+// IsBot reports whether userAgent belongs to a crawler, a link preview or a
+// script rather than a person. Requests without a user agent count as bots.
+func IsBot(userAgent string) bool {
+	return userAgent == "" || botPattern.MatchString(userAgent)
+}
+
+// Count is the number of views a content got in the bucket starting at
+// Bucket.
+type Count struct {
+	ContentID int64
+	Bucket    time.Time
+	Views     int64
+}
+
+// FlushFunc stores a batch of counts. The counter retries a failed batch
+// with the next one, so it must add to what is stored rather than replace it.
+type FlushFunc func(ctx context.Context, counts []Count) error
+
+type key struct {
+	contentID int64
+	bucket    time.Time
+}
+
+type seenEntry struct {
+	key  string
+	last time.Time
+}
+
+// Counter aggregates views in memory and hands them to a FlushFunc in
+// batches. A visitor viewing the same content again within the dedupe
+// window is counted once.
+type Counter struct {
+	flush      FlushFunc
+	bucket     time.Duration
+	dedupe     time.Duration
+	maxPending int
+	maxSeen    int
+
+	flushMu sync.Mutex
+	mu      sync.Mutex
+	pending map[key]int64
+	full    chan struct{}
+
+	// seen holds the last counted view per visitor and content, in order
+	// with the most recent at the front, so the oldest go first when the
+	// map is full or the window has passed.
+	seen      map[string]*list.Element
+	seenOrder *list.List
+}
+
+// NewCounter returns a counter grouping views in buckets of the given size.
+// Once maxPending distinct buckets wait for a flush, Run flushes early. At
+// most maxSeen recent views are remembered for deduplication; past that the
+// oldest are forgotten, so a flood of visitors costs bounded memory at the
+// price of counting some repeat views again.
+func NewCounter(flush FlushFunc, bucket, dedupe time.Duration, maxPending, maxSeen int) *Counter {
+	return &Counter{
+		flush:      flush,
+		bucket:     bucket,
+		dedupe:     dedupe,
+		maxPending: maxPending,
+		maxSeen:    maxSeen,
+		pending:    map[key]int64{},
+		full:       make(chan struct{}, 1),
+		seen:       map[string]*list.Element{},
+		seenOrder:  list.New(),
+	}
+}
+
+// Add records a view of contentID by visitor at now and reports whether it
+// was counted.
+func (c *Counter) Add(contentID int64, visitor string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seenKey := visitor + "|" + strconv.FormatInt(contentID, 10)
+	if elem, ok := c.seen[seenKey]; ok {
+		entry := elem.Value.(*seenEntry)
+		if now.Sub(entry.last) < c.dedupe {
+			return false
+		}
+
+		entry.last = now
+		c.seenOrder.MoveToFront(elem)
+	} else {
+		c.seen[seenKey] = c.seenOrder.PushFront(&seenEntry{key: seenKey, last: now})
+		for c.seenOrder.Len() > c.maxSeen {
+			c.forget(c.seenOrder.Back())
+		}
+	}
+
+	c.pending[key{contentID: contentID, bucket: now.Truncate(c.bucket)}]++
+	if len(c.pending) >= c.maxPending {
+		select {
+		case c.full <- struct{}{}:
+		default:
+		}
+	}
+
+	return true
+}
+
+// Flush hands the pending counts to the FlushFunc. When it fails, the counts
+// are kept and go out with the next flush. Flushes never overlap.
+func (c *Counter) Flush(ctx context.Context) error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = map[key]int64{}
+
+	now := time.Now()
+	for elem := c.seenOrder.Back(); elem != nil && now.Sub(elem.Value.(*seenEntry).last) >= c.dedupe; elem = c.seenOrder.Back() {
+		c.forget(elem)
+	}
+	c.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	counts := make([]Count, 0, len(batch))
+	for k, views := range batch {
+		counts = append(counts, Count{ContentID: k.contentID, Bucket: k.bucket, Views: views})
+	}
+
+	err := c.flush(ctx, counts)
+	if err != nil {
+		c.mu.Lock()
+		for k, views := range batch {
+			c.pending[k] += views
+		}
+		c.mu.Unlock()
+	}
+
+	return err
+}
+
+func (c *Counter) forget(elem *list.Element) {
+	c.seenOrder.Remove(elem)
+	delete(c.seen, elem.Value.(*seenEntry).key)
+}
+
+// Run flushes every interval, or sooner when too many counts are pending,
+// until ctx is done. Errors go to onError.
+func (c *Counter) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-c.full:
+		}
+
+		if err := c.Flush(ctx); err != nil {
+			onError(err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package wxr
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Namespace of content:encoded; excerpt:encoded lives in a versioned
+// namespace ending in /excerpt/.
+const contentNamespace = "http://purl.org/rss/1.0/modules/content/"
+
+const dateLayout = "2006-01-02 15:04:05"
+
+// Export is the part of a WordPress eXtended RSS file the importer uses.
+// Elements are matched by local name so WXR 1.0 to 1.2 all parse.
+type Export struct {
+	Link       string     `xml:"link"`
+	Authors    []Author   `xml:"author"`
+	Categories []Category `xml:"category"`
+	Tags       []Tag      `xml:"tag"`
+	Items      []Item     `xml:"item"`
+}
+
+type Author struct {
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+type Category struct {
+	Nicename string `xml:"category_nicename"`
+	Name     string `xml:"cat_name"`
+}
+
+type Tag struct {
+	Slug string `xml:"tag_slug"`
+	Name string `xml:"tag_name"`
+}
+
+type Item struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Creator       string     `xml:"creator"`
+	Encoded       []encoded  `xml:"encoded"`
+	PostID        int64      `xml:"post_id"`
+	PostDate      string     `xml:"post_date"`
+	PostDateGMT   string     `xml:"post_date_gmt"`
+	ModifiedGMT   string     `xml:"post_modified_gmt"`
+	PostName      string     `xml:"post_name"`
+	Status        string     `xml:"status"`
+	PostType      string     `xml:"post_type"`
+	AttachmentURL string     `xml:"attachment_url"`
+	Terms         []ItemTerm `xml:"category"`
+	Meta          []PostMeta `xml:"postmeta"`
+}
+
+type ItemTerm struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+type PostMeta struct {
+	Key   string `xml:"meta_key"`
+	Value string `xml:"meta_value"`
+}
+
+type encoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// Parse reads a whole WXR document.
+func Parse(r io.Reader) (*Export, error) {
+	var doc struct {
+		Channel Export `xml:"channel"`
+	}
+
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return &doc.Channel, nil
+}
+
+// Content returns the post body from content:encoded.
+func (i Item) Content() string {
+	for _, e := range i.Encoded {
+		if e.XMLName.Space == contentNamespace {
+			return e.Value
+		}
+	}
+
+	return ""
+}
+
+// Excerpt returns the hand-written excerpt from excerpt:encoded.
+func (i Item) Excerpt() string {
+	for _, e := range i.Encoded {
+		if strings.HasSuffix(e.XMLName.Space, "/excerpt/") {
+			return e.Value
+		}
+	}
+
+	return ""
+}
+
+// Category returns the first category term of the post.
+func (i Item) Category() (ItemTerm, bool) {
+	for _, term := range i.Terms {
+		if term.Domain == "category" {
+			return term, true
+		}
+	}
+
+	return ItemTerm{}, false
+}
+
+// TagNames returns the names of the post_tag terms of the post.
+func (i Item) TagNames() []string {
+	var tags []string
+	for _, term := range i.Terms {
+		if term.Domain == "post_tag" {
+			tags = append(tags, strings.TrimSpace(term.Name))
+		}
+	}
+
+	return tags
+}
+
+// ThumbnailID returns the attachment id of the featured image, or 0.
+func (i Item) ThumbnailID() int64 {
+	for _, meta := range i.Meta {
+		if meta.Key == "_thumbnail_id" {
+			id, _ := strconv.ParseInt(strings.TrimSpace(meta.Value), 10, 64)
+			return id
+		}
+	}
+
+	return 0
+}
+
+// Published returns the publication time in UTC. Unpublished posts carry
+// a zero GMT date, so the local post_date is used instead.
+func (i Item) Published() time.Time {
+	if t, err := time.Parse(dateLayout, i.PostDateGMT); err == nil {
+		return t
+	}
+
+	t, _ := time.Parse(dateLayout, i.PostDate)
+	return t
+}
+
+// Modified returns the last modification time in UTC, or the publication
+// time for exports that do not carry one.
+func (i Item) Modified() time.Time {
+	if t, err := time.Parse(dateLayout, i.ModifiedGMT); err == nil {
+		return t
+	}
+
+	return i.Published()
+}